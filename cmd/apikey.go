@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/auth"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+const apiKeyUsage = "usage: apikey create -name name [-scopes scope,...] [-tenant tenant]"
+
+// runAPIKey runs the apikey command, create creates an api key and prints it once. It creates the first admin key of
+// a tenant which creates the others of the tenant through the api.
+func runAPIKey(ctx context.Context, logger log.Logger, cfg envvars.Postgres, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return errors.New(apiKeyUsage)
+	}
+
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	name := fs.String("name", "", "name of the api key")
+	scopes := fs.String("scopes", auth.ScopeAdmin, "comma separated scopes of the api key")
+	tenantID := fs.String("tenant", tenant.Default, "tenant of the api key")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w, %s", err, apiKeyUsage)
+	}
+
+	if *name == "" {
+		return errors.New(apiKeyUsage)
+	}
+
+	if !tenant.Valid(*tenantID) {
+		return fmt.Errorf("invalid tenant %q, tenants are up to 64 letters, digits, - and _", *tenantID)
+	}
+
+	var keyScopes []string
+	for _, scope := range strings.Split(*scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if !auth.ValidScope(scope) {
+			return fmt.Errorf("unknown scope %q, scopes are %s", scope, strings.Join(auth.Scopes, ", "))
+		}
+
+		keyScopes = append(keyScopes, scope)
+	}
+
+	key, prefix, hash, err := auth.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	ps, err := postgrestore.NewStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer ps.Close()
+
+	apiKey := postgrestore.APIKey{
+		Name:      *name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    keyScopes,
+		CreatedBy: "cli",
+	}
+
+	if err := ps.InsertAPIKey(tenant.With(ctx, *tenantID), &apiKey); err != nil {
+		return err
+	}
+
+	_ = logger.Log("apikey", apiKey.ID, "name", apiKey.Name, "tenant", apiKey.TenantID, "scopes", strings.Join(keyScopes, ","), "key", key)
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+const devJWTKeyID = "dev"
+
+// runDevJWT runs the devjwt command, a local stand-in of the identity provider. It creates an ES256 key and its jwks
+// in -dir unless they exist and prints a token signed with the key, the service verifies it with AUTH_JWT_JWKS set
+// to the jwks file. It is refused in production environments.
+func runDevJWT(_ context.Context, logger log.Logger, env *envvars.Configs, args []string) error {
+	if env.Service.IsProduction() {
+		return fmt.Errorf("devjwt is not allowed in the %s environment", env.Service.Environment)
+	}
+
+	fs := flag.NewFlagSet("devjwt", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	dir := fs.String("dir", ".dev-jwks", "directory of the signing key and the jwks")
+	sub := fs.String("sub", "dev", "subject of the token")
+	scopes := fs.String("scopes", "admin", "space separated scopes of the token")
+	tenantID := fs.String("tenant", tenant.Default, "tenant of the token")
+	ttl := fs.Duration("ttl", time.Hour, "lifetime of the token")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w, usage: devjwt [-dir dir] [-sub subject] [-scopes scopes] [-tenant tenant] [-ttl ttl]", err)
+	}
+
+	if !tenant.Valid(*tenantID) {
+		return fmt.Errorf("invalid tenant %q, tenants are up to 64 letters, digits, - and _", *tenantID)
+	}
+
+	key, err := devJWTKey(*dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"sub":                   *sub,
+		"scope":                 *scopes,
+		env.Auth.JWTTenantClaim: *tenantID,
+		"iat":                   now.Unix(),
+		"exp":                   now.Add(*ttl).Unix(),
+	}
+
+	if env.Auth.JWTIssuer != "" {
+		claims["iss"] = env.Auth.JWTIssuer
+	}
+
+	if env.Auth.JWTAudience != "" {
+		claims["aud"] = env.Auth.JWTAudience
+	}
+
+	token, err := signDevJWT(key, claims)
+	if err != nil {
+		return err
+	}
+
+	_ = logger.Log("jwks", filepath.Join(*dir, "jwks.json"), "sub", *sub, "scopes", *scopes, "token", token)
+
+	return nil
+}
+
+// devJWTKey reads the signing key of dir, the key and its jwks are created if the key does not exist
+func devJWTKey(dir string) (*ecdsa.PrivateKey, error) {
+	keyPath := filepath.Join(dir, "key.pem")
+
+	b, err := os.ReadFile(keyPath)
+	if err == nil {
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, fmt.Errorf("%s is not a pem file", keyPath)
+		}
+
+		return x509.ParseECPrivateKey(block.Bytes)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks, err := json.MarshalIndent(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": devJWTKeyID,
+			"use": "sig",
+			"alg": "ES256",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "jwks.json"), jwks, 0o644); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func signDevJWT(key *ecdsa.PrivateKey, claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT", "kid": devJWTKeyID})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString(header),
+		base64.RawURLEncoding.EncodeToString(payload),
+	}, ".")
+
+	digest := sha256.Sum256([]byte(signed))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -3,24 +3,28 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	rest "notify-hub-backend"
 	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/auth"
 	hookclient "notify-hub-backend/internal/client/hook"
+	"notify-hub-backend/internal/lifecycle"
+	"notify-hub-backend/internal/limit"
+	"notify-hub-backend/internal/scheduler"
 	"notify-hub-backend/internal/service"
 	postgrestore "notify-hub-backend/internal/store/postgres"
 	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
 	httptransport "notify-hub-backend/internal/transport/http"
 
 	"github.com/go-kit/log"
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/robfig/cron/v3"
 
 	_ "notify-hub-backend/docs"
 
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"time"
 )
 
 func main() {
@@ -42,24 +46,46 @@ func main() {
 		}
 	}
 
+	// subcommands run instead of the service
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, logger, env, os.Args[1:]); err != nil {
+			_ = logger.Log("error", err.Error())
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if err = env.ValidateServer(); err != nil {
+		_ = logger.Log("error", err.Error())
+		return
+	}
+
 	var redis redisstore.Store
 	{
-		redis, err = redisstore.NewStore(env.Redis)
+		redis, err = redisstore.NewStore(env.Redis, env.Service.Environment)
 		if err != nil {
 			_ = logger.Log("redis error:", err.Error())
 			return
 		}
 	}
 
+	var queue redisstore.Queue
+	if env.Queue.Backend == envvars.QueueBackendStream {
+		queue, err = redisstore.NewQueue(env.Redis, env.Service.Environment, env.Queue)
+		if err != nil {
+			_ = logger.Log("redis queue error:", err.Error())
+			return
+		}
+	}
+
 	var postgres postgrestore.Store
 	{
-		postgres, _ = postgrestore.NewStore(env.Postgres)
+		postgres, err = postgrestore.NewStore(env.Postgres)
 		if err != nil {
 			_ = logger.Log("posgres error:", err.Error())
 			return
 		}
-
-		postgres.InsertDummyMessages(ctx)
 	}
 
 	var hc hookclient.Client
@@ -67,27 +93,126 @@ func main() {
 		hc = hookclient.NewClient(env.Hook, cleanhttp.DefaultPooledClient())
 	}
 
+	// requests and queued messages of a tenant are limited per tenant
+	var limiter *limit.Limiter
+	{
+		limiter, err = limit.New(logger, redis, env.Tenant)
+		if err != nil {
+			_ = logger.Log("tenant limit error:", err.Error())
+			return
+		}
+	}
+
+	// credentials are tried with each configured method in order
+	var authenticator auth.Authenticator
+	if env.Auth.Enabled {
+		var authenticators []auth.Authenticator
+
+		if env.Auth.HasMethod(envvars.AuthMethodAPIKey) {
+			authenticators = append(authenticators, auth.NewAPIKeyAuthenticator(postgres))
+		}
+
+		if env.Auth.HasMethod(envvars.AuthMethodJWT) {
+			jwtAuthenticator, err := auth.NewJWTAuthenticator(ctx, env.Auth, cleanhttp.DefaultPooledClient())
+			if err != nil {
+				_ = logger.Log("auth error:", err.Error())
+				return
+			}
+
+			authenticators = append(authenticators, jwtAuthenticator)
+		}
+
+		authenticator = auth.Chain(authenticators...)
+	}
+
+	instance, _ := os.Hostname()
+
+	// jobs and their locks are shared by every tenant, jobs acting on the data of a tenant set it themselves
+	sc := scheduler.New(tenant.HubWide(ctx), log.With(logger, "component", "scheduler"), redis, postgres, instance, env.Service.JobLockTTL)
+
 	var s rest.Service
 	{
-		s = service.NewService(logger, redis, postgres, hc, env.Service.Environment)
+		s = service.NewService(logger, redis, queue, postgres, hc, limiter, sc, env.Service, env.Inbound)
 	}
 
-	c := cron.New()
+	// jobs start on their configured schedules, schedules changed at runtime are applied by SyncSchedules. Exclusive
+	// jobs run on one instance at a time, schedules are synced on every instance.
+	{
+		jobs := []struct {
+			name      string
+			spec      string
+			exclusive bool
+			job       scheduler.Job
+		}{
+			{service.JobSendMessages, env.Service.SendingMessageTicker, true, s.CronSendMessage},
+			{service.JobRelayOutbox, env.Service.OutboxRelayTicker, true, s.RelayOutbox},
+			{service.JobSyncSchedules, env.Service.ScheduleSyncTicker, false, s.SyncSchedules},
+			{service.JobPruneJobRuns, env.Service.JobRunPruneTicker, true, s.PruneJobRuns},
+		}
+
+		for _, j := range jobs {
+			if err := sc.Add(j.name, j.spec, j.exclusive, j.job); err != nil {
+				_ = logger.Log("scheduler error:", err.Error())
+				return
+			}
+		}
+
+		if _, err := s.SyncSchedules(ctx); err != nil {
+			logger.Log("SyncSchedules err:", err.Error())
+		}
+	}
+
+	sc.Start()
+
+	// workers and the listener claim work until shutdown starts
+	workCtx, stopWork := context.WithCancel(ctx)
+
+	// inserted messages wake the sender up right away, the cron stays as a safety net for missed notifications
+	var listener postgrestore.Listener
+	if env.Service.SendingNotify {
+		listener = postgrestore.NewListener(env.Postgres, postgrestore.MessageQueuedChannel)
 
-	_, _ = c.AddFunc(env.Service.SendingMessageTicker, func() {
 		go func() {
-			err = s.CronSendMessage(ctx)
-			if err != nil {
-				logger.Log("CronSendMessage err:", err.Error())
+			for workCtx.Err() == nil {
+				if err := listener.Wait(workCtx); err != nil {
+					if workCtx.Err() != nil {
+						return
+					}
+
+					logger.Log("Listener err:", err.Error())
+					time.Sleep(5 * time.Second)
+					continue
+				}
+
+				// a wakeup while a run is in progress is skipped, its messages are sent by the next run
+				err := sc.Trigger(service.JobSendMessages)
+				if errors.Is(err, scheduler.ErrStopped) {
+					return
+				} else if err != nil {
+					logger.Log("Trigger err:", err.Error())
+				}
 			}
 		}()
-	})
+	}
 
-	c.Start()
+	if queue != nil {
+		consumer := env.Queue.Consumer
+		if consumer == "" {
+			consumer = instance
+		}
+
+		for i := 0; i < env.Queue.Workers; i++ {
+			go func(consumer string) {
+				if err := s.ConsumeQueue(workCtx, consumer); err != nil {
+					logger.Log("ConsumeQueue err:", err.Error())
+				}
+			}(fmt.Sprintf("%s-%d", consumer, i))
+		}
+	}
 
 	var handler http.Handler
 	{
-		handler = httptransport.MakeHTTPHandler(log.With(logger, "transport", "http"), s)
+		handler = httptransport.MakeHTTPHandler(log.With(logger, "transport", "http"), s, authenticator, limiter)
 	}
 
 	// Rest Http Server struct with Handler and Addr
@@ -99,37 +224,74 @@ func main() {
 		}
 	}
 
-	errs := make(chan error)
-	go func() {
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
-		errs <- errors.New((<-c).String())
-	}()
+	lc := lifecycle.New(log.With(logger, "component", "lifecycle"), env.HTTPServer.ShutdownTimeout)
 
 	// http Handler Serve with routine
 	go func() {
 		_ = logger.Log("transport", "http", "address", env.HTTPServer.Port)
 
-		err = httpServer.ListenAndServe()
-		if errors.Is(err, http.ErrServerClosed) {
-			errs <- err
+		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			lc.Fail(err)
 		}
 	}()
 
-	err = <-errs
-	_ = logger.Log("error", err.Error())
+	// shutdown steps run in order: stop taking requests and claiming work, let in-flight sends and jobs finish,
+	// then close the connections they use, each with a deadline of its own
+	lc.OnShutdown("http", httpServer.Shutdown)
 
-	ctx, cf := context.WithTimeout(ctx, env.HTTPServer.ShutdownTimeout)
+	// stopping is not skipped when the http shutdown used up the timeout
+	lc.OnShutdownWithin("stop claiming", lifecycle.CloseTimeout, func(context.Context) error {
+		sc.Stop()
+		stopWork()
 
-	defer cf()
+		return nil
+	})
 
-	if err := httpServer.Shutdown(ctx); err != nil {
-		_ = logger.Log("error", err.Error())
+	lc.OnShutdown("drain sends", s.Drain)
+
+	// scheduled and triggered runs are waited for, they use redis and postgres
+	lc.OnShutdown("wait jobs", func(ctx context.Context) error {
+		select {
+		case <-sc.Stop().Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	if listener != nil {
+		lc.OnShutdownWithin("close listener", lifecycle.CloseTimeout, listener.Close)
 	}
 
-	if err := redis.Close(); err != nil {
-		_ = logger.Log("error", err.Error())
+	if queue != nil {
+		lc.OnShutdownWithin("close queue", lifecycle.CloseTimeout, func(context.Context) error {
+			return queue.Close()
+		})
+	}
+
+	lc.OnShutdownWithin("close redis", lifecycle.CloseTimeout, func(context.Context) error {
+		return redis.Close()
+	})
+
+	lc.OnShutdownWithin("close postgres", lifecycle.CloseTimeout, func(context.Context) error {
+		return postgres.Close()
+	})
+
+	os.Exit(lc.Wait())
+}
+
+// runCommand runs the subcommand given in args
+func runCommand(ctx context.Context, logger log.Logger, env *envvars.Configs, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(ctx, logger, env.Postgres, args[1:])
+	case "seed":
+		return runSeed(ctx, logger, env, args[1:])
+	case "apikey":
+		return runAPIKey(ctx, logger, env.Postgres, args[1:])
+	case "devjwt":
+		return runDevJWT(ctx, logger, env, args[1:])
 	}
 
-	select {}
+	return fmt.Errorf("unknown command %q", args[0])
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+
+	"github.com/go-kit/log"
+)
+
+const migrateUsage = "usage: migrate up [steps] | down [steps] | version"
+
+// runMigrate runs the migrate command, up applies all pending migrations unless steps is given and down reverts
+// the last migration unless steps is given
+func runMigrate(ctx context.Context, logger log.Logger, cfg envvars.Postgres, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+
+	steps := 0
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid steps %q, %s", args[1], migrateUsage)
+		}
+
+		steps = n
+	}
+
+	m, err := postgrestore.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	var migrations []postgrestore.Migration
+
+	switch args[0] {
+	case "up":
+		migrations, err = m.Up(ctx, steps)
+		for _, migration := range migrations {
+			_ = logger.Log("migration", migration.Version, "name", migration.Name, "action", "applied")
+		}
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+
+		migrations, err = m.Down(ctx, steps)
+		for _, migration := range migrations {
+			_ = logger.Log("migration", migration.Version, "name", migration.Name, "action", "reverted")
+		}
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], migrateUsage)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected, err := postgrestore.LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	_ = logger.Log("version", version, "latest", expected)
+
+	return nil
+}
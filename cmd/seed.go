@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+var (
+	seedFirstNames = []string{"Ayşe", "Mehmet", "Elif", "Mustafa", "Zeynep", "Emre", "Defne", "Burak", "Selin", "Can", "Ece", "Murat"}
+	seedLastNames  = []string{"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Aydın", "Öztürk", "Arslan", "Doğan"}
+	seedTeams      = []string{"payments", "platform", "growth", "support", "mobile"}
+	seedCities     = []string{"İstanbul", "Ankara", "İzmir", "Bursa", "Antalya"}
+	// operator prefixes of turkish mobile numbers
+	seedOperators = []int{530, 532, 533, 535, 541, 542, 505, 506, 551, 552}
+)
+
+// runSeed runs the seed command, it inserts fake contacts and queued messages of the default tenant and deletes
+// existing data only when -reset is given. It is refused in production environments.
+func runSeed(ctx context.Context, logger log.Logger, env *envvars.Configs, args []string) error {
+	if env.Service.IsProduction() {
+		return fmt.Errorf("seed is not allowed in the %s environment", env.Service.Environment)
+	}
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	contacts := fs.Int("contacts", 10, "number of contacts to insert")
+	messages := fs.Int("messages", 10, "number of queued messages to insert")
+	reset := fs.Bool("reset", false, "delete existing messages, campaigns, contacts and groups first")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "random seed, the same seed generates the same data")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w, usage: seed [-contacts n] [-messages n] [-reset] [-seed n]", err)
+	}
+
+	if *contacts < 0 || *messages < 0 {
+		return errors.New("counts must not be negative")
+	}
+
+	ps, err := postgrestore.NewStore(env.Postgres)
+	if err != nil {
+		return err
+	}
+
+	defer ps.Close()
+
+	if *reset {
+		if err := ps.ResetData(ctx); err != nil {
+			return err
+		}
+
+		_ = logger.Log("seed", "reset")
+	}
+
+	ctx = tenant.With(ctx, tenant.Default)
+	r := rand.New(rand.NewSource(*seed))
+
+	seededContacts := fakeContacts(r, *contacts)
+	if err := ps.InsertContacts(ctx, seededContacts); err != nil {
+		return err
+	}
+
+	seededMessages := fakeMessages(r, seededContacts, *messages)
+	if err := ps.InsertMessages(ctx, seededMessages); err != nil {
+		return err
+	}
+
+	_ = logger.Log("seed", *seed, "contacts", len(seededContacts), "messages", len(seededMessages))
+
+	return nil
+}
+
+func fakeContacts(r *rand.Rand, n int) []postgrestore.Contact {
+	contacts := make([]postgrestore.Contact, 0, n)
+	for i := 0; i < n; i++ {
+		contacts = append(contacts, postgrestore.Contact{
+			Name:  pick(r, seedFirstNames) + " " + pick(r, seedLastNames),
+			Phone: fakePhone(r),
+			Attributes: postgrestore.Attributes{
+				"team": pick(r, seedTeams),
+				"city": pick(r, seedCities),
+			},
+		})
+	}
+
+	return contacts
+}
+
+// fakeMessages returns queued messages to seeded contacts, or to random numbers when no contact is seeded
+func fakeMessages(r *rand.Rand, contacts []postgrestore.Contact, n int) []postgrestore.Message {
+	messages := make([]postgrestore.Message, 0, n)
+	for i := 0; i < n; i++ {
+		name, recipient := pick(r, seedFirstNames), fakePhone(r)
+		if len(contacts) > 0 {
+			contact := contacts[r.Intn(len(contacts))]
+			name, recipient = contact.Name, contact.Phone
+		}
+
+		messages = append(messages, postgrestore.Message{
+			Recipient: recipient,
+			Content:   fakeContent(r, name),
+			Status:    postgrestore.MessageStatusQueued,
+		})
+	}
+
+	return messages
+}
+
+func fakeContent(r *rand.Rand, name string) string {
+	switch r.Intn(4) {
+	case 0:
+		return fmt.Sprintf("Your verification code is %06d. Do not share it with anyone.", r.Intn(1000000))
+	case 1:
+		return fmt.Sprintf("Hi %s, your order #%d has been shipped and will be delivered within 2 business days. "+
+			"You can track your package from the orders page of your account.", name, 100000+r.Intn(900000))
+	case 2:
+		return fmt.Sprintf("Hi %s, this is a reminder of your appointment tomorrow at %02d:00.", name, 9+r.Intn(9))
+	}
+
+	return fmt.Sprintf("Hi %s, your payment of %d.%02d TRY has been received, thank you.", name, 50+r.Intn(950), r.Intn(100))
+}
+
+func fakePhone(r *rand.Rand) string {
+	return fmt.Sprintf("+90%d%07d", pick(r, seedOperators), r.Intn(10000000))
+}
+
+func pick[T any](r *rand.Rand, values []T) T {
+	return values[r.Intn(len(values))]
+}
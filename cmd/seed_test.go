@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/phone"
+
+	"github.com/go-kit/log"
+)
+
+func TestRunSeedRefusedInProduction(t *testing.T) {
+	for _, environment := range []string{"prod", "Production"} {
+		env := &envvars.Configs{Service: envvars.Service{Environment: environment}}
+
+		if err := runSeed(context.Background(), log.NewNopLogger(), env, nil); err == nil {
+			t.Errorf("runSeed in %s error = nil, want the seed refused", environment)
+		}
+	}
+}
+
+func TestFakeData(t *testing.T) {
+	generate := func(seed int64) ([]string, []string) {
+		r := rand.New(rand.NewSource(seed))
+		contacts := fakeContacts(r, 5)
+		messages := fakeMessages(r, contacts, 10)
+
+		phones := make([]string, 0, len(contacts))
+		for _, contact := range contacts {
+			phones = append(phones, contact.Phone)
+		}
+
+		recipients := make([]string, 0, len(messages))
+		for _, message := range messages {
+			recipients = append(recipients, message.Recipient)
+		}
+
+		return phones, recipients
+	}
+
+	phones, recipients := generate(42)
+	if again, _ := generate(42); !reflect.DeepEqual(phones, again) {
+		t.Errorf("seed 42 generated %v and then %v, want the same contacts", phones, again)
+	}
+
+	for _, p := range phones {
+		if normalized, err := phone.Normalize(p, "TR"); err != nil || normalized != p {
+			t.Errorf("fake phone %q normalizes to %q, %v, want it in E.164 format", p, normalized, err)
+		}
+	}
+
+	// messages are sent to the seeded contacts
+	for _, recipient := range recipients {
+		found := false
+		for _, p := range phones {
+			found = found || p == recipient
+		}
+
+		if !found {
+			t.Errorf("message recipient %q is not a seeded contact", recipient)
+		}
+	}
+}
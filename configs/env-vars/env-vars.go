@@ -1,7 +1,9 @@
 package envvars
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/codingconcepts/env"
@@ -14,20 +16,45 @@ type Configs struct {
 	HTTPServer HTTPServer
 	Postgres   Postgres
 	Hook       Hook
+	Inbound    Inbound
+	Queue      Queue
+	Auth       Auth
+	Tenant     Tenant
 }
 
 // Service represents service configurations
 type Service struct {
-	Environment          string `env:"SERVICE_ENVIRONMENT" required:"true"`
-	SendingMessageTicker string `env:"SERVICE_SENDING_MESSAGE_TICKER" default:"@every 120s"`
+	Environment          string        `env:"SERVICE_ENVIRONMENT" required:"true"`
+	SendingMessageTicker string        `env:"SERVICE_SENDING_MESSAGE_TICKER" default:"@every 120s"`
+	OutboxRelayTicker    string        `env:"SERVICE_OUTBOX_RELAY_TICKER" default:"@every 10s"`
+	ScheduleSyncTicker   string        `env:"SERVICE_SCHEDULE_SYNC_TICKER" default:"@every 30s"`
+	JobRunPruneTicker    string        `env:"SERVICE_JOB_RUN_PRUNE_TICKER" default:"@daily"`
+	JobRunRetention      time.Duration `env:"SERVICE_JOB_RUN_RETENTION" default:"168h"`
+	JobLockTTL           time.Duration `env:"SERVICE_JOB_LOCK_TTL" default:"1m"`
+	ReadinessTimeout     time.Duration `env:"SERVICE_READINESS_TIMEOUT" default:"2s"`
+	SendingLease         time.Duration `env:"SERVICE_SENDING_LEASE" default:"5m"`
+	SendingNotify        bool          `env:"SERVICE_SENDING_NOTIFY" default:"false"`
+	DefaultPhoneRegion   string        `env:"SERVICE_DEFAULT_PHONE_REGION" default:"TR"`
+	MaxSendAttempts      int           `env:"SERVICE_MAX_SEND_ATTEMPTS" default:"3"`
+}
+
+// IsProduction reports whether the service runs in a production environment
+func (s Service) IsProduction() bool {
+	switch strings.ToLower(s.Environment) {
+	case "prod", "production":
+		return true
+	}
+
+	return false
 }
 
 // Redis represents redis configurations
 type Redis struct {
-	Address  string        `env:"REDIS_ADDRESS" required:"true"`
-	Password string        `env:"REDIS_PASSWORD"`
-	DB       int           `env:"REDIS_DB" required:"true"`
-	Expiry   time.Duration `env:"REDIS_EXPIRY" default:"24h"`
+	Address   string        `env:"REDIS_ADDRESS" required:"true"`
+	Password  string        `env:"REDIS_PASSWORD"`
+	DB        int           `env:"REDIS_DB" required:"true"`
+	Expiry    time.Duration `env:"REDIS_EXPIRY" default:"24h"`
+	KeyPrefix string        `env:"REDIS_KEY_PREFIX" default:"notify-hub"`
 }
 
 // HTTPServer represents http server configurations
@@ -51,6 +78,81 @@ type Hook struct {
 	ClientSecret string `env:"HOOK_CLIENT_SECRET" required:"true"`
 }
 
+// Inbound represents inbound message configurations, webhooks require WebhookSecret unless WebhookInsecure is set for
+// local development
+type Inbound struct {
+	WebhookSecret    string   `env:"INBOUND_WEBHOOK_SECRET"`
+	WebhookInsecure  bool     `env:"INBOUND_WEBHOOK_INSECURE" default:"false"`
+	StopKeywords     []string `env:"INBOUND_STOP_KEYWORDS" default:"STOP,STOPALL,UNSUBSCRIBE,CANCEL,END,QUIT,IPTAL,DUR,ALTO,BAJA,ARRET,ABMELDEN"`
+	StartKeywords    []string `env:"INBOUND_START_KEYWORDS" default:"START,UNSTOP,SUBSCRIBE,BASLA,BAŞLA,ALTA,COMMENCER,ANMELDEN"`
+	HelpKeywords     []string `env:"INBOUND_HELP_KEYWORDS" default:"HELP,INFO,YARDIM,AYUDA,AIDE,HILFE"`
+	AutoReplyEnabled bool     `env:"INBOUND_AUTO_REPLY_ENABLED" default:"false"`
+	StopReply        string   `env:"INBOUND_STOP_REPLY" default:"You have been unsubscribed and will not receive further messages. Reply START to resubscribe."`
+	StartReply       string   `env:"INBOUND_START_REPLY" default:"You have been resubscribed. Reply STOP to unsubscribe."`
+	HelpReply        string   `env:"INBOUND_HELP_REPLY" default:"Reply STOP to unsubscribe or START to resubscribe."`
+}
+
+// queue backends
+const (
+	QueueBackendPoll   = "poll"
+	QueueBackendStream = "stream"
+)
+
+// Queue represents sending queue configurations, the stream backend sends created messages right away through a
+// redis stream while the cron keeps polling for campaign messages and retries
+type Queue struct {
+	Backend     string        `env:"QUEUE_BACKEND" default:"poll"`
+	Stream      string        `env:"QUEUE_STREAM" default:"messages"`
+	Group       string        `env:"QUEUE_GROUP" default:"senders"`
+	Consumer    string        `env:"QUEUE_CONSUMER"`
+	Workers     int           `env:"QUEUE_WORKERS" default:"4"`
+	Block       time.Duration `env:"QUEUE_BLOCK" default:"5s"`
+	ReclaimIdle time.Duration `env:"QUEUE_RECLAIM_IDLE" default:"1m"`
+}
+
+// authentication methods
+const (
+	AuthMethodAPIKey = "apikey"
+	AuthMethodJWT    = "jwt"
+)
+
+// Auth represents authentication configurations, endpoints other than health checks and webhooks require credentials
+// of one of the methods unless authentication is disabled. JWTs are verified with the keys of JWTJWKS, a file path or
+// an http(s) url.
+type Auth struct {
+	Enabled        bool          `env:"AUTH_ENABLED" default:"true"`
+	Methods        []string      `env:"AUTH_METHODS" default:"apikey"`
+	JWTJWKS        string        `env:"AUTH_JWT_JWKS"`
+	JWTJWKSRefresh time.Duration `env:"AUTH_JWT_JWKS_REFRESH" default:"10m"`
+	JWTIssuer      string        `env:"AUTH_JWT_ISSUER"`
+	JWTAudience    string        `env:"AUTH_JWT_AUDIENCE"`
+	JWTLeeway      time.Duration `env:"AUTH_JWT_LEEWAY" default:"1m"`
+	JWTNameClaim   string        `env:"AUTH_JWT_NAME_CLAIM" default:"sub"`
+	JWTScopeClaim  string        `env:"AUTH_JWT_SCOPE_CLAIM" default:"scope"`
+	JWTScopeMap    []string      `env:"AUTH_JWT_SCOPE_MAP"`
+	JWTTenantClaim string        `env:"AUTH_JWT_TENANT_CLAIM" default:"tenant"`
+}
+
+// HasMethod reports whether method is one of the authentication methods
+func (a Auth) HasMethod(method string) bool {
+	for _, m := range a.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tenant represents per tenant limits, a tenant queues at most DailyQuota messages per UTC day and makes at most
+// RateLimit requests per minute, 0 disables a limit. Limits of single tenants are overridden with tenant=limit pairs.
+type Tenant struct {
+	DailyQuota          int      `env:"TENANT_DAILY_QUOTA" default:"100000"`
+	DailyQuotaOverrides []string `env:"TENANT_DAILY_QUOTA_OVERRIDES"`
+	RateLimit           int      `env:"TENANT_RATE_LIMIT" default:"1200"`
+	RateLimitOverrides  []string `env:"TENANT_RATE_LIMIT_OVERRIDES"`
+}
+
 // LoadEnvVars loads and returns environment variables
 func LoadEnvVars() (*Configs, error) {
 	s := Service{}
@@ -58,6 +160,10 @@ func LoadEnvVars() (*Configs, error) {
 		return nil, fmt.Errorf("loading service environment variables failed, %s", err.Error())
 	}
 
+	if s.JobLockTTL <= 0 {
+		return nil, fmt.Errorf("loading service environment variables failed, SERVICE_JOB_LOCK_TTL must be positive, got %s", s.JobLockTTL)
+	}
+
 	r := Redis{}
 	if err := env.Set(&r); err != nil {
 		return nil, fmt.Errorf("loading redis environment variables failed, %s", err.Error())
@@ -78,13 +184,69 @@ func LoadEnvVars() (*Configs, error) {
 		return nil, fmt.Errorf("loading hook environment variables failed, %s", err.Error())
 	}
 
+	i := Inbound{}
+	if err := env.Set(&i); err != nil {
+		return nil, fmt.Errorf("loading inbound environment variables failed, %s", err.Error())
+	}
+
+	q := Queue{}
+	if err := env.Set(&q); err != nil {
+		return nil, fmt.Errorf("loading queue environment variables failed, %s", err.Error())
+	}
+
+	if q.Backend != QueueBackendPoll && q.Backend != QueueBackendStream {
+		return nil, fmt.Errorf("loading queue environment variables failed, unknown backend %q", q.Backend)
+	}
+
+	a := Auth{}
+	if err := env.Set(&a); err != nil {
+		return nil, fmt.Errorf("loading auth environment variables failed, %s", err.Error())
+	}
+
+	for _, m := range a.Methods {
+		if m != AuthMethodAPIKey && m != AuthMethodJWT {
+			return nil, fmt.Errorf("loading auth environment variables failed, unknown method %q", m)
+		}
+	}
+
+	if a.Enabled && a.HasMethod(AuthMethodJWT) && a.JWTJWKS == "" {
+		return nil, errors.New("loading auth environment variables failed, AUTH_JWT_JWKS is required by the jwt method")
+	}
+
+	t := Tenant{}
+	if err := env.Set(&t); err != nil {
+		return nil, fmt.Errorf("loading tenant environment variables failed, %s", err.Error())
+	}
+
+	if t.DailyQuota < 0 || t.RateLimit < 0 {
+		return nil, errors.New("loading tenant environment variables failed, limits can not be negative")
+	}
+
 	ev := &Configs{
 		Service:    s,
 		Redis:      r,
 		HTTPServer: hs,
 		Postgres:   ps,
 		Hook:       h,
+		Inbound:    i,
+		Queue:      q,
+		Auth:       a,
+		Tenant:     t,
 	}
 
 	return ev, nil
 }
+
+// ValidateServer returns an error when configurations needed only to serve requests are missing, it is checked when the
+// server starts so that other uses of the configurations do not require them
+func (c *Configs) ValidateServer() error {
+	if c.Inbound.WebhookSecret == "" && !c.Inbound.WebhookInsecure {
+		return errors.New("INBOUND_WEBHOOK_SECRET is required unless INBOUND_WEBHOOK_INSECURE is true")
+	}
+
+	if c.Inbound.WebhookInsecure && c.Service.IsProduction() {
+		return errors.New("INBOUND_WEBHOOK_INSECURE is not allowed in production")
+	}
+
+	return nil
+}
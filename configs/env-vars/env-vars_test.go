@@ -0,0 +1,56 @@
+package envvars
+
+import "testing"
+
+func TestValidateServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		inbound     Inbound
+		wantErr     bool
+	}{
+		{name: "secret", environment: "prod", inbound: Inbound{WebhookSecret: "secret"}},
+		{name: "insecure", environment: "dev", inbound: Inbound{WebhookInsecure: true}},
+		{name: "insecure in production", environment: "Production", inbound: Inbound{WebhookInsecure: true}, wantErr: true},
+		{name: "no secret", environment: "dev", inbound: Inbound{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Configs{Service: Service{Environment: tt.environment}, Inbound: tt.inbound}
+			if err := c.ValidateServer(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServer error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEnvVarsJobLockTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		wantErr bool
+	}{
+		{name: "default", ttl: "1m"},
+		{name: "positive", ttl: "30s"},
+		{name: "zero", ttl: "0s", wantErr: true},
+		{name: "negative", ttl: "-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SERVICE_ENVIRONMENT", "dev")
+			t.Setenv("REDIS_ADDRESS", "localhost:6379")
+			t.Setenv("REDIS_DB", "0")
+			t.Setenv("HTTP_SERVER_PORT", ":8080")
+			t.Setenv("POSTGRES_DSN", "postgres://localhost/notify_hub")
+			t.Setenv("HOOK_CLIENT_URL", "https://webhook.site/test")
+			t.Setenv("HOOK_CLIENT_SECRET", "secret")
+			t.Setenv("SERVICE_JOB_LOCK_TTL", tt.ttl)
+
+			if _, err := LoadEnvVars(); (err != nil) != tt.wantErr {
+				t.Errorf("LoadEnvVars error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
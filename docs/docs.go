@@ -12,18 +12,110 @@
 //	Produces:
 //	- application/json
 //
+//	Security:
+//	- api_key: []
+//	- bearer: []
+//
+//	SecurityDefinitions:
+//	api_key:
+//	  type: apiKey
+//	  name: X-API-Key
+//	  in: header
+//	bearer:
+//	  description: an api key or a jwt of the identity provider, as Bearer <token>
+//	  type: apiKey
+//	  name: Authorization
+//	  in: header
+//
 // swagger:meta
 package docs
 
 import "time"
 
 type apiError struct {
+	// example: validation failed, tag: required, field: Recipient
 	Message string `json:"message"`
-	Code    int    `json:"code"`
+	// http status code of the response
+	// example: 400
+	Code int `json:"code"`
+	// one of invalid_request, validation_failed, unauthorized, forbidden, not_found, method_not_allowed, conflict, rate_limited, quota_exceeded, internal
+	// example: validation_failed
+	Type string `json:"type"`
+	// seconds to wait before retrying a request that exceeded a limit, sent in the Retry-After header as well
+	// example: 42
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+// Failed operation, written with the status code of the error
+// swagger:response errorResponse
+type errorResponse struct {
+	// in:body
+	Body struct {
+		Data   interface{} `json:"data"`
+		Result *apiError   `json:"result"`
+	}
+}
+
+// swagger:parameters livezRequest
+type livezRequest struct{}
+
+// Successful operation
+// swagger:response livezResponse
+type livezResponse struct {
+	// in:body
+	Body struct {
+		// example: up
+		Status string `json:"status"`
+	}
+}
+
+// swagger:parameters readyzRequest
+type readyzRequest struct{}
+
+// Readiness of the service, written with 503 when status is down
+// swagger:response readyzResponse
+type readyzResponse struct {
+	// in:body
+	Body struct {
+		// up or down
+		// example: up
+		Status string `json:"status"`
+		// true while the service is shutting down
+		// example: false
+		Draining bool `json:"draining"`
+		// ok or unavailable for postgres, redis and the stream queue when it is enabled, errors are logged only
+		// example: {"postgres": "ok", "redis": "ok"}
+		Dependencies map[string]string `json:"dependencies"`
+		Backlog      backlogData       `json:"backlog"`
+		// example: 2024-09-09 15:30
+		LastSentAt *time.Time `json:"lastSentAt"`
+	}
+}
+
+type backlogData struct {
+	// queued messages
+	// example: 120
+	Queued *int64 `json:"queued"`
+	// messages claimed by a run
+	// example: 2
+	Sending *int64 `json:"sending"`
+	// entries of the stream queue, only reported with the stream backend
+	// example: 4
+	Stream *int64 `json:"stream,omitempty"`
 }
 
 // swagger:parameters switchAutoSendRequest
-type switchAutoSendRequest struct{}
+type switchAutoSendRequest struct {
+	// name of the operator switching auto send, recorded in the history
+	// in:header
+	// name: x-ins-operator
+	Operator string `json:"x-ins-operator"`
+	// in:body
+	Body struct {
+		// example: provider maintenance
+		Reason string `json:"reason"`
+	}
+}
 
 // Successful operation
 // swagger:response switchAutoSendResponse
@@ -38,10 +130,177 @@ type switchAutoSendResponse struct {
 type switchAutoSendData struct {
 	// example: true
 	AutoSendOn bool `json:"autoSendOn"`
+	// example: provider maintenance
+	Reason string `json:"reason"`
+	// example: jane.doe
+	ChangedBy string `json:"changedBy"`
+	// example: 2024-09-09 15:30
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// swagger:parameters fetchAutoSendHistoryRequest
+type fetchAutoSendHistoryRequest struct {
+	// defaults to global
+	// in:query
+	Scope string `json:"scope"`
+	// in:query
+	// minimum: 1
+	// maximum: 1000
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// Successful operation
+// swagger:response fetchAutoSendHistoryResponse
+type fetchAutoSendHistoryResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchAutoSendHistoryData `json:"data"`
+		Result *apiError                 `json:"result"`
+	}
+}
+
+type fetchAutoSendHistoryData struct {
+	Changes []sendingStateData `json:"changes"`
+}
+
+// swagger:parameters setSendingStateRequest
+type setSendingStateRequest struct {
+	// name of the operator changing the state, recorded in the history
+	// in:header
+	// name: x-ins-operator
+	Operator string `json:"x-ins-operator"`
+	// in:body
+	Body struct {
+		// global, channel:marketing, channel:transactional, provider:hook or campaign:<id>, defaults to global
+		// example: channel:marketing
+		Scope string `json:"scope"`
+		// example: false
+		// required: true
+		Enabled bool `json:"enabled"`
+		// example: provider incident
+		Reason string `json:"reason"`
+		// sending is resumed automatically at this time, only allowed when pausing
+		// example: 2024-09-09 15:30
+		ResumeAt *time.Time `json:"resumeAt"`
+	}
+}
+
+// Successful operation
+// swagger:response setSendingStateResponse
+type setSendingStateResponse struct {
+	// in:body
+	Body struct {
+		Data   *sendingStateData `json:"data"`
+		Result *apiError         `json:"result"`
+	}
+}
+
+type sendingStateData struct {
+	// example: channel:marketing
+	Scope string `json:"scope"`
+	// example: false
+	Enabled bool `json:"enabled"`
+	// example: provider incident
+	Reason string `json:"reason"`
+	// example: 2024-09-09 15:30
+	ResumeAt *time.Time `json:"resumeAt"`
+	// example: jane.doe
+	ChangedBy string `json:"changedBy"`
+	// example: 2024-09-09 15:30
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// swagger:parameters fetchSendingStatesRequest
+type fetchSendingStatesRequest struct{}
+
+// Successful operation
+// swagger:response fetchSendingStatesResponse
+type fetchSendingStatesResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchSendingStatesData `json:"data"`
+		Result *apiError               `json:"result"`
+	}
+}
+
+type fetchSendingStatesData struct {
+	States []sendingStateData `json:"states"`
+}
+
+// swagger:parameters createMessageRequest
+type createMessageRequest struct {
+	// in:body
+	Body struct {
+		// required without groupId
+		// example: 05325008081
+		Recipient string `json:"recipient"`
+		// required without recipient
+		// example: 3
+		GroupID int64 `json:"groupId"`
+		// content is a text/template for group sends and when template is set, name, phone and contact attributes are
+		// available as variables
+		// example: Hi {{.name}}, you are on call for {{.team}} this week
+		// required: true
+		Content string `json:"content"`
+		// renders the content of a recipient send as a template, recipients that are not contacts have the phone only
+		// example: false
+		Template bool `json:"template"`
+	}
+}
+
+// Successful operation
+// swagger:response createMessageResponse
+type createMessageResponse struct {
+	// in:body
+	Body struct {
+		Data   *createMessageData `json:"data"`
+		Result *apiError          `json:"result"`
+	}
+}
+
+type createMessageData struct {
+	Messages []createdMessage `json:"messages"`
+}
+
+type createdMessage struct {
+	// example: 42
+	ID int64 `json:"id"`
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: queued
+	Status string `json:"status"`
 }
 
 // swagger:parameters fetchSentMessagesRequest
-type fetchSentMessagesRequest struct{}
+type fetchSentMessagesRequest struct {
+	// next cursor of the previous page
+	// in:query
+	Cursor string `json:"cursor"`
+	// in:query
+	// minimum: 1
+	// maximum: 1000
+	// default: 100
+	Limit int `json:"limit"`
+	// in:query
+	Recipient string `json:"recipient"`
+	// inclusive lower bound of sending time in RFC 3339 format
+	// in:query
+	From time.Time `json:"from"`
+	// exclusive upper bound of sending time in RFC 3339 format
+	// in:query
+	To time.Time `json:"to"`
+	// defaults to sent and delivered
+	// in:query
+	// enum: queued,sending,sent,delivered,failed,suppressed,cancelled
+	Status []string `json:"status"`
+	// in:query
+	CampaignID int64 `json:"campaignId"`
+	// in:query
+	// enum: id,-id,sentAt,-sentAt
+	// default: id
+	Sort string `json:"sort"`
+}
 
 // Successful operation
 // swagger:response fetchSentMessagesResponse
@@ -55,11 +314,23 @@ type fetchSentMessagesResponse struct {
 
 type fetchSentMessagesData struct {
 	SentMessages []fetchSentMessage `json:"sentMessages"`
+	// empty on the last page
+	// example: eyJzb3J0IjoiIiwiaWQiOjQyfQ
+	NextCursor string `json:"nextCursor"`
 }
 
 type fetchSentMessage struct {
-	Recipient string                    `json:"recipient"`
-	Contents  []fetchSentMessageContent `json:"contents"`
+	// example: 42
+	ID int64 `json:"id"`
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: sent
+	Status string `json:"status"`
+	// example: 5
+	CampaignID *int64 `json:"campaignId"`
+	// example: 2024-09-09 15:30
+	SentAt   *time.Time                `json:"sentAt"`
+	Contents []fetchSentMessageContent `json:"contents"`
 }
 
 type fetchSentMessageContent struct {
@@ -70,3 +341,849 @@ type fetchSentMessageContent struct {
 	// example: Lorem ipsum data content
 	Content string `json:"content"`
 }
+
+// swagger:parameters fetchMessageRequest
+type fetchMessageRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response fetchMessageResponse
+type fetchMessageResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchMessageData `json:"data"`
+		Result *apiError         `json:"result"`
+	}
+}
+
+type fetchMessageData struct {
+	// example: 42
+	ID int64 `json:"id"`
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: Lorem ipsum data content
+	Content string `json:"content"`
+	// example: delivered
+	Status string `json:"status"`
+	// example: 3
+	GroupID *int64 `json:"groupId"`
+	// example: 5
+	CampaignID *int64 `json:"campaignId"`
+	// example: 2024-09-09 15:30
+	SentAt   *time.Time            `json:"sentAt"`
+	Attempts []messageAttemptData  `json:"attempts"`
+	Chunks   []messageChunkData    `json:"chunks"`
+	Receipts []deliveryReceiptData `json:"receipts"`
+}
+
+type messageAttemptData struct {
+	// example: 2024-09-09 15:30
+	StartedAt time.Time `json:"startedAt"`
+	// example: 2024-09-09 15:30
+	FinishedAt time.Time `json:"finishedAt"`
+	// example: 1
+	ChunksSent int `json:"chunksSent"`
+	// example: unexpected status code: 503
+	Error string `json:"error"`
+}
+
+type messageChunkData struct {
+	// example: 0
+	Index int `json:"index"`
+	// example: 5f4f647f-26b5-4d27-b603-e5d7f4a9dd08
+	MessageId string `json:"messageId"`
+	// example: 2024-09-09 15:30
+	SendingTime time.Time `json:"sendingTime"`
+	// example: Lorem ipsum data content
+	Content string `json:"content"`
+}
+
+type deliveryReceiptData struct {
+	// example: 5f4f647f-26b5-4d27-b603-e5d7f4a9dd08
+	MessageId string `json:"messageId"`
+	// example: delivered
+	Status string `json:"status"`
+	// example: handset unreachable
+	Error string `json:"error"`
+	// example: 2024-09-09 15:30
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// swagger:parameters receiveDeliveryReceiptRequest
+type receiveDeliveryReceiptRequest struct {
+	// in:header
+	// name: x-ins-auth-key
+	AuthKey string `json:"x-ins-auth-key"`
+	// in:body
+	Body struct {
+		// example: 5f4f647f-26b5-4d27-b603-e5d7f4a9dd08
+		// required: true
+		MessageID string `json:"messageId"`
+		// example: delivered
+		// required: true
+		Status string `json:"status"`
+		// example: handset unreachable
+		Error string `json:"error"`
+		// example: 2024-09-09 15:30
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+}
+
+// Successful operation
+// swagger:response receiveDeliveryReceiptResponse
+type receiveDeliveryReceiptResponse struct {
+	// in:body
+	Body struct {
+		Data   *receiveDeliveryReceiptData `json:"data"`
+		Result *apiError                   `json:"result"`
+	}
+}
+
+type receiveDeliveryReceiptData struct {
+	// example: 42
+	ID *int64 `json:"id"`
+	// example: delivered
+	Status string `json:"status"`
+}
+
+// swagger:parameters addSuppressionRequest
+type addSuppressionRequest struct {
+	// in:body
+	Body struct {
+		// example: +905325008081
+		// required: true
+		Recipient string `json:"recipient"`
+		// example: STOP received by support
+		// required: true
+		Reason string `json:"reason"`
+	}
+}
+
+// Successful operation
+// swagger:response addSuppressionResponse
+type addSuppressionResponse struct {
+	// in:body
+	Body struct {
+		Data   *suppressionData `json:"data"`
+		Result *apiError        `json:"result"`
+	}
+}
+
+type suppressionData struct {
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: STOP received by support
+	Reason string `json:"reason"`
+	// example: api
+	Source string `json:"source"`
+	// example: 2024-09-09 15:30
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// swagger:parameters removeSuppressionRequest
+type removeSuppressionRequest struct {
+	// in:path
+	// required: true
+	Recipient string `json:"recipient"`
+}
+
+// Successful operation
+// swagger:response removeSuppressionResponse
+type removeSuppressionResponse struct {
+	// in:body
+	Body struct {
+		Data   *removeSuppressionData `json:"data"`
+		Result *apiError              `json:"result"`
+	}
+}
+
+type removeSuppressionData struct {
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+}
+
+// swagger:parameters fetchSuppressionsRequest
+type fetchSuppressionsRequest struct{}
+
+// Successful operation
+// swagger:response fetchSuppressionsResponse
+type fetchSuppressionsResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchSuppressionsData `json:"data"`
+		Result *apiError              `json:"result"`
+	}
+}
+
+type fetchSuppressionsData struct {
+	Suppressions []suppressionData `json:"suppressions"`
+}
+
+// swagger:parameters receiveInboundMessageRequest
+type receiveInboundMessageRequest struct {
+	// in:header
+	// name: x-ins-auth-key
+	AuthKey string `json:"x-ins-auth-key"`
+	// in:body
+	Body struct {
+		// example: +905325008081
+		// required: true
+		From string `json:"from"`
+		// example: STOP
+		Content string `json:"content"`
+		// example: 5f4f647f-26b5-4d27-b603-e5d7f4a9dd08
+		MessageID string `json:"messageId"`
+		// example: 2024-09-09 15:30
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+}
+
+// Successful operation
+// swagger:response receiveInboundMessageResponse
+type receiveInboundMessageResponse struct {
+	// in:body
+	Body struct {
+		Data   *receiveInboundMessageData `json:"data"`
+		Result *apiError                  `json:"result"`
+	}
+}
+
+type receiveInboundMessageData struct {
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: STOP
+	Keyword string `json:"keyword"`
+	// example: suppressed
+	Action string `json:"action"`
+	// example: true
+	Replied bool `json:"replied"`
+}
+
+// swagger:parameters fetchInboundMessagesRequest
+type fetchInboundMessagesRequest struct {
+	// in:query
+	// required: true
+	Recipient string `json:"recipient"`
+	// in:query
+	// minimum: 1
+	// maximum: 1000
+	Limit int `json:"limit"`
+}
+
+// Successful operation
+// swagger:response fetchInboundMessagesResponse
+type fetchInboundMessagesResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchInboundMessagesData `json:"data"`
+		Result *apiError                 `json:"result"`
+	}
+}
+
+type fetchInboundMessagesData struct {
+	InboundMessages []inboundMessage `json:"inboundMessages"`
+}
+
+type inboundMessage struct {
+	// example: +905325008081
+	Recipient string `json:"recipient"`
+	// example: STOP
+	Content string `json:"content"`
+	// example: STOP
+	Keyword string `json:"keyword"`
+	// example: 5f4f647f-26b5-4d27-b603-e5d7f4a9dd08
+	MessageID string `json:"messageId"`
+	// example: 2024-09-09 15:30
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+type contactData struct {
+	// example: 7
+	ID int64 `json:"id"`
+	// example: Jane Doe
+	Name string `json:"name"`
+	// example: +905325008081
+	Phone string `json:"phone"`
+	// example: {"team": "payments"}
+	Attributes map[string]string `json:"attributes"`
+	// example: 2024-09-09 15:30
+	CreatedAt time.Time `json:"createdAt"`
+	// example: 2024-09-09 15:30
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type contactBody struct {
+	// example: Jane Doe
+	// required: true
+	Name string `json:"name"`
+	// example: 05325008081
+	// required: true
+	Phone string `json:"phone"`
+	// example: {"team": "payments"}
+	Attributes map[string]string `json:"attributes"`
+}
+
+// swagger:parameters createContactRequest
+type createContactRequest struct {
+	// in:body
+	Body contactBody
+}
+
+// Successful operation
+// swagger:response createContactResponse
+type createContactResponse struct {
+	// in:body
+	Body struct {
+		Data   *contactData `json:"data"`
+		Result *apiError    `json:"result"`
+	}
+}
+
+// swagger:parameters updateContactRequest
+type updateContactRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+	// in:body
+	Body contactBody
+}
+
+// Successful operation
+// swagger:response updateContactResponse
+type updateContactResponse struct {
+	// in:body
+	Body struct {
+		Data   *contactData `json:"data"`
+		Result *apiError    `json:"result"`
+	}
+}
+
+// swagger:parameters deleteContactRequest
+type deleteContactRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response deleteContactResponse
+type deleteContactResponse struct {
+	// in:body
+	Body struct {
+		Data   *deletedData `json:"data"`
+		Result *apiError    `json:"result"`
+	}
+}
+
+type deletedData struct {
+	// example: 7
+	ID int64 `json:"id"`
+}
+
+// swagger:parameters fetchContactRequest
+type fetchContactRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response fetchContactResponse
+type fetchContactResponse struct {
+	// in:body
+	Body struct {
+		Data   *contactData `json:"data"`
+		Result *apiError    `json:"result"`
+	}
+}
+
+// swagger:parameters fetchContactsRequest
+type fetchContactsRequest struct{}
+
+// Successful operation
+// swagger:response fetchContactsResponse
+type fetchContactsResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchContactsData `json:"data"`
+		Result *apiError          `json:"result"`
+	}
+}
+
+type fetchContactsData struct {
+	Contacts []contactData `json:"contacts"`
+}
+
+type groupData struct {
+	// example: 3
+	ID int64 `json:"id"`
+	// example: on-call engineers
+	Name string `json:"name"`
+	// example: Engineers on call this week
+	Description string        `json:"description"`
+	Contacts    []contactData `json:"contacts"`
+	// example: 2024-09-09 15:30
+	CreatedAt time.Time `json:"createdAt"`
+	// example: 2024-09-09 15:30
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type groupBody struct {
+	// example: on-call engineers
+	// required: true
+	Name string `json:"name"`
+	// example: Engineers on call this week
+	Description string `json:"description"`
+	// example: [7, 8]
+	ContactIDs []int64 `json:"contactIds"`
+}
+
+// swagger:parameters createGroupRequest
+type createGroupRequest struct {
+	// in:body
+	Body groupBody
+}
+
+// Successful operation
+// swagger:response createGroupResponse
+type createGroupResponse struct {
+	// in:body
+	Body struct {
+		Data   *groupData `json:"data"`
+		Result *apiError  `json:"result"`
+	}
+}
+
+// swagger:parameters updateGroupRequest
+type updateGroupRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+	// in:body
+	Body groupBody
+}
+
+// Successful operation
+// swagger:response updateGroupResponse
+type updateGroupResponse struct {
+	// in:body
+	Body struct {
+		Data   *groupData `json:"data"`
+		Result *apiError  `json:"result"`
+	}
+}
+
+// swagger:parameters deleteGroupRequest
+type deleteGroupRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response deleteGroupResponse
+type deleteGroupResponse struct {
+	// in:body
+	Body struct {
+		Data   *deletedData `json:"data"`
+		Result *apiError    `json:"result"`
+	}
+}
+
+// swagger:parameters fetchGroupRequest
+type fetchGroupRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response fetchGroupResponse
+type fetchGroupResponse struct {
+	// in:body
+	Body struct {
+		Data   *groupData `json:"data"`
+		Result *apiError  `json:"result"`
+	}
+}
+
+// swagger:parameters fetchGroupsRequest
+type fetchGroupsRequest struct{}
+
+// Successful operation
+// swagger:response fetchGroupsResponse
+type fetchGroupsResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchGroupsData `json:"data"`
+		Result *apiError        `json:"result"`
+	}
+}
+
+type fetchGroupsData struct {
+	Groups []groupData `json:"groups"`
+}
+
+type campaignData struct {
+	// example: 5
+	ID int64 `json:"id"`
+	// example: Spring sale
+	Name string `json:"name"`
+	// example: Hi {{.name}}, spring sale starts today
+	Content string `json:"content"`
+	// scheduled, active, paused, aborted or completed
+	// example: active
+	Status string `json:"status"`
+	// example: 3
+	GroupID *int64 `json:"groupId"`
+	// example: 1200
+	AudienceSize int `json:"audienceSize"`
+	// example: 2024-09-09 15:30
+	StartAt time.Time `json:"startAt"`
+	// example: 60
+	ThrottlePerMinute int                  `json:"throttlePerMinute"`
+	Progress          campaignProgressData `json:"progress"`
+	// example: 2024-09-09 15:30
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type campaignProgressData struct {
+	// example: 700
+	Queued int `json:"queued"`
+	// example: 300
+	Sent int `json:"sent"`
+	// example: 10
+	Failed int `json:"failed"`
+	// example: 180
+	Delivered int `json:"delivered"`
+	// example: 5
+	Suppressed int `json:"suppressed"`
+	// example: 5
+	Cancelled int `json:"cancelled"`
+	// example: 1200
+	Total int `json:"total"`
+}
+
+// swagger:parameters createCampaignRequest
+type createCampaignRequest struct {
+	// in:body
+	Body struct {
+		// example: Spring sale
+		// required: true
+		Name string `json:"name"`
+		// example: Hi {{.name}}, spring sale starts today
+		// required: true
+		Content string `json:"content"`
+		// required without recipients
+		// example: 3
+		GroupID int64 `json:"groupId"`
+		// required without groupId
+		// example: ["05325008081", "+905325008082"]
+		Recipients []string `json:"recipients"`
+		// renders the content of each recipient as a template, recipients that are not contacts have the phone only
+		// example: false
+		Template bool `json:"template"`
+		// defaults to now
+		// example: 2024-09-09 15:30
+		StartAt time.Time `json:"startAt"`
+		// 0 means unlimited
+		// example: 60
+		ThrottlePerMinute int `json:"throttlePerMinute"`
+	}
+}
+
+// Successful operation
+// swagger:response createCampaignResponse
+type createCampaignResponse struct {
+	// in:body
+	Body struct {
+		Data   *campaignData `json:"data"`
+		Result *apiError     `json:"result"`
+	}
+}
+
+// swagger:parameters fetchCampaignRequest pauseCampaignRequest resumeCampaignRequest abortCampaignRequest
+type campaignRequest struct {
+	// in:path
+	// required: true
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response fetchCampaignResponse
+type fetchCampaignResponse struct {
+	// in:body
+	Body struct {
+		Data   *campaignData `json:"data"`
+		Result *apiError     `json:"result"`
+	}
+}
+
+// Successful operation
+// swagger:response pauseCampaignResponse
+type pauseCampaignResponse struct {
+	// in:body
+	Body struct {
+		Data   *campaignData `json:"data"`
+		Result *apiError     `json:"result"`
+	}
+}
+
+// Successful operation
+// swagger:response resumeCampaignResponse
+type resumeCampaignResponse struct {
+	// in:body
+	Body struct {
+		Data   *campaignData `json:"data"`
+		Result *apiError     `json:"result"`
+	}
+}
+
+// Successful operation
+// swagger:response abortCampaignResponse
+type abortCampaignResponse struct {
+	// in:body
+	Body struct {
+		Data   *abortCampaignData `json:"data"`
+		Result *apiError          `json:"result"`
+	}
+}
+
+type abortCampaignData struct {
+	campaignData
+	// queued messages that are cancelled by the abort
+	// example: 700
+	CancelledMessages int `json:"cancelledMessages"`
+}
+
+// swagger:parameters fetchSchedulesRequest
+type fetchSchedulesRequest struct{}
+
+// Successful operation
+// swagger:response fetchSchedulesResponse
+type fetchSchedulesResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchSchedulesData `json:"data"`
+		Result *apiError           `json:"result"`
+	}
+}
+
+type fetchSchedulesData struct {
+	Schedules []scheduleData `json:"schedules"`
+}
+
+type scheduleData struct {
+	// example: send-messages
+	Name string `json:"name"`
+	// example: @every 2m
+	Spec string `json:"spec"`
+	// example: 2024-09-09 15:32
+	NextRunAt *time.Time `json:"nextRunAt"`
+	// example: false
+	Running bool `json:"running"`
+	// outcome of the last run on the instance serving the request
+	LastRun *scheduleRunData `json:"lastRun"`
+	// empty unless the schedule was changed at runtime
+	// example: jane.doe
+	ChangedBy string `json:"changedBy"`
+	// example: 2024-09-09 15:30
+	ChangedAt *time.Time `json:"changedAt"`
+}
+
+type scheduleRunData struct {
+	// example: 2024-09-09 15:30
+	StartedAt time.Time `json:"startedAt"`
+	// example: 2024-09-09 15:30
+	FinishedAt time.Time `json:"finishedAt"`
+	// example: 2
+	Processed int `json:"processed"`
+	// example: failed to claim sendable messages: connection refused
+	Error string `json:"error"`
+}
+
+// swagger:parameters updateScheduleRequest
+type updateScheduleRequest struct {
+	// name of the operator changing the schedule
+	// in:header
+	// name: x-ins-operator
+	Operator string `json:"x-ins-operator"`
+	// send-messages, relay-outbox, sync-schedules or prune-job-runs
+	// in:path
+	// required: true
+	Name string `json:"name"`
+	// in:body
+	Body struct {
+		// cron expression or descriptor
+		// example: @every 30s
+		// required: true
+		Spec string `json:"spec"`
+	}
+}
+
+// Successful operation
+// swagger:response updateScheduleResponse
+type updateScheduleResponse struct {
+	// in:body
+	Body struct {
+		Data   *scheduleData `json:"data"`
+		Result *apiError     `json:"result"`
+	}
+}
+
+// swagger:parameters fetchJobRunsRequest
+type fetchJobRunsRequest struct {
+	// in:path
+	// required: true
+	// example: send-messages
+	Name string `json:"name"`
+	// in:query
+	// minimum: 1
+	// maximum: 1000
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// Successful operation
+// swagger:response fetchJobRunsResponse
+type fetchJobRunsResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchJobRunsData `json:"data"`
+		Result *apiError         `json:"result"`
+	}
+}
+
+type fetchJobRunsData struct {
+	Runs []jobRunData `json:"runs"`
+}
+
+type jobRunData struct {
+	// example: 42
+	ID int64 `json:"id"`
+	// example: notify-hub-7c9f8d6b5-x2x4q
+	Instance string `json:"instance"`
+	// example: 2024-09-09 15:30
+	StartedAt time.Time `json:"startedAt"`
+	// example: 2024-09-09 15:30
+	FinishedAt time.Time `json:"finishedAt"`
+	// example: 840
+	DurationMs int64 `json:"durationMs"`
+	// example: 2
+	Processed int `json:"processed"`
+	// example: failed to claim sendable messages: connection refused
+	Error string `json:"error"`
+}
+
+type apiKeyData struct {
+	// example: 3
+	ID int64 `json:"id"`
+	// example: billing-service
+	Name string `json:"name"`
+	// tenant the callers of the key act on behalf of
+	// example: payments
+	Tenant string `json:"tenant"`
+	// first characters of the key
+	// example: nhk_Q2x1c3Rl
+	Prefix string `json:"prefix"`
+	// example: ["messages:write","messages:read"]
+	Scopes []string `json:"scopes"`
+	// only returned when the key is created or rotated
+	// example: nhk_Q2x1c3RlcjEyMzQ1Njc4OTBhYmNkZWZnaGlqa2xtbm9w
+	Key string `json:"key,omitempty"`
+	// example: jane.doe
+	CreatedBy string `json:"createdBy"`
+	// example: 2024-09-09 15:30
+	CreatedAt time.Time `json:"createdAt"`
+	// example: 2024-09-09 15:30
+	RotatedAt *time.Time `json:"rotatedAt"`
+	// example: 2024-09-09 15:30
+	RevokedAt *time.Time `json:"revokedAt"`
+	// written at most once a minute
+	// example: 2024-09-09 15:30
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// swagger:parameters createAPIKeyRequest
+type createAPIKeyRequest struct {
+	// name of the operator creating the key, defaults to the name of the caller
+	// in:header
+	// name: x-ins-operator
+	Operator string `json:"x-ins-operator"`
+	// in:body
+	Body struct {
+		// required: true
+		// example: billing-service
+		Name string `json:"name"`
+		// messages:read, messages:write, contacts:read, contacts:write or admin
+		// required: true
+		// example: ["messages:write","messages:read"]
+		Scopes []string `json:"scopes"`
+	}
+}
+
+// Successful operation
+// swagger:response createAPIKeyResponse
+type createAPIKeyResponse struct {
+	// in:body
+	Body struct {
+		Data   *apiKeyData `json:"data"`
+		Result *apiError   `json:"result"`
+	}
+}
+
+// swagger:parameters fetchAPIKeysRequest
+type fetchAPIKeysRequest struct{}
+
+// Successful operation
+// swagger:response fetchAPIKeysResponse
+type fetchAPIKeysResponse struct {
+	// in:body
+	Body struct {
+		Data   *fetchAPIKeysData `json:"data"`
+		Result *apiError         `json:"result"`
+	}
+}
+
+type fetchAPIKeysData struct {
+	Keys []apiKeyData `json:"keys"`
+}
+
+// swagger:parameters rotateAPIKeyRequest
+type rotateAPIKeyRequest struct {
+	// in:path
+	// required: true
+	// example: 3
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response rotateAPIKeyResponse
+type rotateAPIKeyResponse struct {
+	// in:body
+	Body struct {
+		Data   *apiKeyData `json:"data"`
+		Result *apiError   `json:"result"`
+	}
+}
+
+// swagger:parameters revokeAPIKeyRequest
+type revokeAPIKeyRequest struct {
+	// in:path
+	// required: true
+	// example: 3
+	ID int64 `json:"id"`
+}
+
+// Successful operation
+// swagger:response revokeAPIKeyResponse
+type revokeAPIKeyResponse struct {
+	// in:body
+	Body struct {
+		Data   *apiKeyData `json:"data"`
+		Result *apiError   `json:"result"`
+	}
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// api keys are keyPrefix followed by keyBytes random bytes, the first keyDisplayLength characters identify a key in
+// listings
+const (
+	keyPrefix        = "nhk_"
+	keyBytes         = 32
+	keyDisplayLength = len(keyPrefix) + 8
+)
+
+// GenerateKey returns a new api key, its display prefix and its hash
+func GenerateKey() (key, prefix, hash string, err error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return key, key[:keyDisplayLength], HashKey(key), nil
+}
+
+// HashKey returns the hash of an api key stored in place of the key, keys are random so an unsalted hash is enough
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyAuthenticator authenticates api keys stored in postgres
+type apiKeyAuthenticator struct {
+	ps postgrestore.Store
+}
+
+// NewAPIKeyAuthenticator returns an authenticator of api keys stored in postgres
+func NewAPIKeyAuthenticator(ps postgrestore.Store) Authenticator {
+	return &apiKeyAuthenticator{ps: ps}
+}
+
+// Authenticate returns the caller of an api key that is not revoked, the caller acts on behalf of the key's tenant
+func (a *apiKeyAuthenticator) Authenticate(ctx context.Context, credentials string) (Caller, error) {
+	if !strings.HasPrefix(credentials, keyPrefix) {
+		return Caller{}, ErrUnauthenticated
+	}
+
+	key, err := a.ps.FetchAPIKeyByHash(ctx, HashKey(credentials))
+	if err != nil {
+		return Caller{}, err
+	}
+
+	if key == nil {
+		return Caller{}, ErrUnauthenticated
+	}
+
+	// the last use is informational, failing to record it does not fail the request
+	_ = a.ps.TouchAPIKey(ctx, key.ID)
+
+	return Caller{
+		ID:     fmt.Sprintf("apikey:%d", key.ID),
+		Name:   key.Name,
+		Tenant: key.TenantID,
+		Scopes: key.Scopes,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+
+	"notify-hub-backend/internal/tenant"
+)
+
+// scopes of callers, admin grants every scope
+const (
+	ScopeMessagesRead  = "messages:read"
+	ScopeMessagesWrite = "messages:write"
+	ScopeContactsRead  = "contacts:read"
+	ScopeContactsWrite = "contacts:write"
+	ScopeAdmin         = "admin"
+)
+
+// Scopes lists every scope
+var Scopes = []string{ScopeMessagesRead, ScopeMessagesWrite, ScopeContactsRead, ScopeContactsWrite, ScopeAdmin}
+
+// APIKeyHeader is the header carrying an api key, a key can be sent as a bearer token as well
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// ErrUnauthenticated represents a request without valid credentials
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	// ErrForbidden represents a caller without the scope an endpoint requires
+	ErrForbidden = errors.New("insufficient scope")
+)
+
+type contextKey int
+
+const (
+	credentialsKey contextKey = iota
+	callerKey
+)
+
+// Caller represents the authenticated caller of a request, Tenant is empty unless the credentials carry one and the
+// caller then acts on behalf of the default tenant
+type Caller struct {
+	ID     string
+	Name   string
+	Tenant string
+	Scopes []string
+}
+
+// HasScope reports whether the caller is granted scope
+func (c Caller) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authenticator authenticates the credentials of a request and returns its caller, it returns ErrUnauthenticated
+// when the credentials are not valid
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials string) (Caller, error)
+}
+
+// chain tries authenticators in order
+type chain []Authenticator
+
+// Chain returns an authenticator that tries each authenticator in order until one of them accepts the credentials
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+// Authenticate returns the caller of the first authenticator accepting the credentials, errors other than
+// ErrUnauthenticated are returned right away. Authenticators return ErrUnauthenticated itself for credentials they do
+// not handle, a wrapped ErrUnauthenticated explaining why credentials they handle are rejected is kept.
+func (c chain) Authenticate(ctx context.Context, credentials string) (Caller, error) {
+	rejected := ErrUnauthenticated
+	for _, a := range c {
+		caller, err := a.Authenticate(ctx, credentials)
+		if !errors.Is(err, ErrUnauthenticated) {
+			return caller, err
+		}
+
+		if err != ErrUnauthenticated {
+			rejected = err
+		}
+	}
+
+	return Caller{}, rejected
+}
+
+// ValidScope reports whether scope is known
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HTTPToContext moves the credentials of a request to the context, they are read from the X-API-Key header or from
+// a bearer token in the Authorization header
+func HTTPToContext() kithttp.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		credentials := r.Header.Get(APIKeyHeader)
+
+		if credentials == "" {
+			header := r.Header.Get("Authorization")
+			if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+				credentials = strings.TrimSpace(header[len("Bearer "):])
+			}
+		}
+
+		if credentials == "" {
+			return ctx
+		}
+
+		return context.WithValue(ctx, credentialsKey, credentials)
+	}
+}
+
+// CallerFrom returns the caller authenticated by Middleware
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	c, ok := ctx.Value(callerKey).(Caller)
+	return c, ok
+}
+
+// WithCaller returns a copy of ctx carrying caller
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey, caller)
+}
+
+// Middleware returns an endpoint middleware that authenticates the credentials in the context and requires the
+// caller to be granted scope. The caller and its tenant are added to the context of the endpoint.
+func Middleware(a Authenticator, scope string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			credentials, _ := ctx.Value(credentialsKey).(string)
+			if credentials == "" {
+				return nil, ErrUnauthenticated
+			}
+
+			caller, err := a.Authenticate(ctx, credentials)
+			if err != nil {
+				return nil, err
+			}
+
+			if !caller.HasScope(scope) {
+				return nil, ErrForbidden
+			}
+
+			id := caller.Tenant
+			if id == "" {
+				id = tenant.Default
+			}
+
+			return next(tenant.With(WithCaller(ctx, caller), id), request)
+		}
+	}
+}
+
+// RequireTenant returns an endpoint middleware that requires the caller to act on behalf of tenant id, it must be
+// wrapped with Middleware so that the tenant of the caller is known
+func RequireTenant(id string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if caller, ok := tenant.From(ctx); !ok || caller != id {
+				return nil, fmt.Errorf("%w, only callers of the %s tenant are allowed", ErrForbidden, id)
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// fakeKeys keeps api keys by hash, the methods that are not used by the authenticator are left to the embedded
+// interface
+type fakeKeys struct {
+	postgrestore.Store
+	keys    map[string]postgrestore.APIKey
+	touched []int64
+}
+
+func (f *fakeKeys) FetchAPIKeyByHash(_ context.Context, hash string) (*postgrestore.APIKey, error) {
+	key, ok := f.keys[hash]
+	if !ok || key.RevokedAt != nil {
+		return nil, nil
+	}
+
+	return &key, nil
+}
+
+func (f *fakeKeys) TouchAPIKey(_ context.Context, id int64) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, prefix, hash, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey error = %v", err)
+	}
+
+	if !strings.HasPrefix(key, keyPrefix) || !strings.HasPrefix(key, prefix) || len(prefix) != keyDisplayLength {
+		t.Errorf("key %q with prefix %q, want a %s key identified by its first %d characters", key, prefix, keyPrefix, keyDisplayLength)
+	}
+
+	if hash != HashKey(key) || strings.Contains(hash, key) {
+		t.Errorf("hash = %q, want the hash of the key", hash)
+	}
+
+	if other, _, _, _ := GenerateKey(); other == key {
+		t.Error("GenerateKey returned the same key twice")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{name: "granted", scopes: []string{ScopeMessagesRead}, scope: ScopeMessagesRead, want: true},
+		{name: "not granted", scopes: []string{ScopeMessagesRead}, scope: ScopeMessagesWrite},
+		{name: "admin", scopes: []string{ScopeAdmin}, scope: ScopeContactsWrite, want: true},
+		{name: "no scopes", scope: ScopeContactsRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Caller{Scopes: tt.scopes}).HasScope(tt.scope); got != tt.want {
+				t.Errorf("HasScope(%s) of %v = %v, want %v", tt.scope, tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	key, prefix, hash, _ := GenerateKey()
+	revoked, _, revokedHash, _ := GenerateKey()
+
+	ps := &fakeKeys{keys: map[string]postgrestore.APIKey{
+		hash:        {ID: 1, Name: "ops", Prefix: prefix, Scopes: postgrestore.Scopes{ScopeMessagesRead}},
+		revokedHash: {ID: 2, Name: "old", Scopes: postgrestore.Scopes{ScopeAdmin}, RevokedAt: new(time.Time)},
+	}}
+
+	next := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		caller, _ := CallerFrom(ctx)
+		return caller, nil
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		scope  string
+		err    error
+	}{
+		{name: "api key header", header: APIKeyHeader, value: key, scope: ScopeMessagesRead},
+		{name: "bearer token", header: "Authorization", value: "bearer " + key, scope: ScopeMessagesRead},
+		{name: "insufficient scope", header: APIKeyHeader, value: key, scope: ScopeMessagesWrite, err: ErrForbidden},
+		{name: "revoked key", header: APIKeyHeader, value: revoked, scope: ScopeMessagesRead, err: ErrUnauthenticated},
+		{name: "unknown key", header: APIKeyHeader, value: "nhk_unknown", scope: ScopeMessagesRead, err: ErrUnauthenticated},
+		{name: "not an api key", header: "Authorization", value: "Basic b3BzOnNlY3JldA==", scope: ScopeMessagesRead, err: ErrUnauthenticated},
+		{name: "no credentials", scope: ScopeMessagesRead, err: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/messages", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+
+			ctx := HTTPToContext()(context.Background(), r)
+
+			res, err := Middleware(NewAPIKeyAuthenticator(ps), tt.scope)(next)(ctx, nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Middleware error = %v, want %v", err, tt.err)
+			}
+
+			if tt.err == nil && res.(Caller).Name != "ops" {
+				t.Errorf("caller = %+v, want ops", res)
+			}
+		})
+	}
+}
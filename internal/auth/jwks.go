@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// jwksRefetchInterval limits reading the key set again when a token is signed with an unknown key, e.g. right after
+// the identity provider rotated its keys, or when reading it failed
+const jwksRefetchInterval = time.Minute
+
+// limits of reading a key set from an url
+const (
+	jwksMaxBytes = 1 << 20
+	jwksTimeout  = 10 * time.Second
+)
+
+// jwk represents a json web key, only the members of rsa and ec public keys are read
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey represents a verification key of a key set, alg is empty when the key does not restrict its algorithm
+type publicKey struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// jwks represents a json web key set read from a file or an http(s) url. Keys are read again once refresh is over, or
+// when a token refers to a key that is not in the set. The set is read without holding mu and concurrent reads are
+// shared, so that a slow identity provider only holds up tokens of keys that are not known yet.
+type jwks struct {
+	source    string
+	hc        *http.Client
+	refresh   time.Duration
+	loads     singleflight.Group
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+	checkedAt time.Time
+}
+
+func newJWKS(ctx context.Context, source string, refresh time.Duration, hc *http.Client) (*jwks, error) {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	k := &jwks{
+		source:  source,
+		hc:      hc,
+		refresh: refresh,
+	}
+
+	if err := k.load(ctx); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// key returns the key of kid, a token without kid is accepted if the set has a single key. A known key is returned
+// right away while the set is refreshed, an unknown key waits for the set to be read again.
+func (k *jwks) key(ctx context.Context, kid string) (publicKey, error) {
+	k.mu.Lock()
+	key, ok := k.lookup(kid)
+	due := (!ok || time.Since(k.fetchedAt) > k.refresh) && time.Since(k.checkedAt) > jwksRefetchInterval
+	k.mu.Unlock()
+
+	if !due {
+		if !ok {
+			return publicKey{}, fmt.Errorf("unknown key %q", kid)
+		}
+
+		return key, nil
+	}
+
+	// the read is shared by the tokens waiting for it, it is not cancelled with the request that started it
+	loaded := k.loads.DoChan("jwks", func() (interface{}, error) {
+		return nil, k.load(context.WithoutCancel(ctx))
+	})
+
+	// a failed refresh keeps the previous keys, the identity provider may be unavailable for a while
+	if ok {
+		return key, nil
+	}
+
+	select {
+	case res := <-loaded:
+		if res.Err != nil {
+			return publicKey{}, res.Err
+		}
+	case <-ctx.Done():
+		return publicKey{}, ctx.Err()
+	}
+
+	k.mu.Lock()
+	key, ok = k.lookup(kid)
+	k.mu.Unlock()
+
+	if !ok {
+		return publicKey{}, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+// lookup returns the key of kid, mu must be held
+func (k *jwks) lookup(kid string) (publicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+
+	key, ok := k.keys[kid]
+
+	return key, ok
+}
+
+// load reads the key set and replaces the keys with it, keys that are not rsa or ec signing keys are skipped
+func (k *jwks) load(ctx context.Context) error {
+	k.mu.Lock()
+	k.checkedAt = time.Now()
+	k.mu.Unlock()
+
+	b, err := k.read(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read jwks: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(b, &set); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+
+		key, err := j.publicKey()
+		if err != nil {
+			return fmt.Errorf("failed to decode jwk %q: %w", j.Kid, err)
+		}
+
+		if key != nil {
+			keys[j.Kid] = publicKey{key: key, alg: j.Alg}
+		}
+	}
+
+	if len(keys) == 0 {
+		return errors.New("jwks has no signing keys")
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *jwks) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(k.source, "http://") && !strings.HasPrefix(k.source, "https://") {
+		return os.ReadFile(k.source)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jwksTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := k.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, jwksMaxBytes))
+}
+
+// publicKey returns the rsa or ec public key of the jwk, or nil for other key types and curves
+func (j jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, nil
+		}
+
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+		// ECDH fails unless the point is on the curve
+		if _, err := key.ECDH(); err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
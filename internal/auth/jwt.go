@@ -0,0 +1,286 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/tenant"
+)
+
+// signing algorithms accepted in tokens, symmetric algorithms and none are never accepted
+var jwtAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+}
+
+// jwtAuthenticator authenticates jwts signed by an identity provider
+type jwtAuthenticator struct {
+	keys        *jwks
+	issuer      string
+	audience    string
+	leeway      time.Duration
+	nameClaim   string
+	scopeClaim  string
+	scopeMap    map[string]string
+	tenantClaim string
+}
+
+// NewJWTAuthenticator returns an authenticator of jwts verified with the keys of cfg.JWTJWKS, the key set is read
+// before it returns so that a wrong source fails at startup. Scopes are read from cfg.JWTScopeClaim, values listed in
+// cfg.JWTScopeMap as claim=scope pairs are mapped to scopes and other values are kept if they are scopes.
+func NewJWTAuthenticator(ctx context.Context, cfg envvars.Auth, hc *http.Client) (Authenticator, error) {
+	scopeMap := make(map[string]string, len(cfg.JWTScopeMap))
+	for _, pair := range cfg.JWTScopeMap {
+		claim, scope, ok := strings.Cut(pair, "=")
+		if !ok || !ValidScope(scope) {
+			return nil, fmt.Errorf("invalid scope mapping %q, mappings are claim=scope", pair)
+		}
+
+		scopeMap[claim] = scope
+	}
+
+	keys, err := newJWKS(ctx, cfg.JWTJWKS, cfg.JWTJWKSRefresh, hc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtAuthenticator{
+		keys:        keys,
+		issuer:      cfg.JWTIssuer,
+		audience:    cfg.JWTAudience,
+		leeway:      cfg.JWTLeeway,
+		nameClaim:   cfg.JWTNameClaim,
+		scopeClaim:  cfg.JWTScopeClaim,
+		scopeMap:    scopeMap,
+		tenantClaim: cfg.JWTTenantClaim,
+	}, nil
+}
+
+// Authenticate verifies the signature, the expiry, the issuer and the audience of a token and returns its caller,
+// tokens without a subject or a tenant are rejected
+func (a *jwtAuthenticator) Authenticate(ctx context.Context, credentials string) (Caller, error) {
+	// credentials that are not shaped like a jwt are left to other authenticators
+	if strings.Count(credentials, ".") != 2 {
+		return Caller{}, ErrUnauthenticated
+	}
+
+	claims, err := a.verify(ctx, credentials)
+	if err != nil {
+		return Caller{}, fmt.Errorf("%w, %s", ErrUnauthenticated, err.Error())
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Caller{}, fmt.Errorf("%w, token has no subject", ErrUnauthenticated)
+	}
+
+	name, _ := claims[a.nameClaim].(string)
+	if name == "" {
+		name = subject
+	}
+
+	// tokens name their tenant, a token without one would act on behalf of the default tenant and its hub-wide
+	// endpoints
+	tenantID, _ := claims[a.tenantClaim].(string)
+	if tenantID == "" {
+		return Caller{}, fmt.Errorf("%w, token has no %s claim", ErrUnauthenticated, a.tenantClaim)
+	}
+
+	if !tenant.Valid(tenantID) {
+		return Caller{}, fmt.Errorf("%w, invalid tenant %q", ErrUnauthenticated, tenantID)
+	}
+
+	return Caller{
+		ID:     "jwt:" + subject,
+		Name:   name,
+		Tenant: tenantID,
+		Scopes: a.scopes(claims[a.scopeClaim]),
+	}, nil
+}
+
+func (a *jwtAuthenticator) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header, %s", err.Error())
+	}
+
+	hash, ok := jwtAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+
+	key, err := a.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.alg != "" && key.alg != header.Alg {
+		return nil, fmt.Errorf("key %q does not allow algorithm %q", header.Kid, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature, %s", err.Error())
+	}
+
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+
+	if err := verifySignature(header.Alg, key.key, hash, h.Sum(nil), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims, %s", err.Error())
+	}
+
+	if err := a.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, signature []byte) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			break
+		}
+
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, signature); err != nil {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			break
+		}
+
+		// ES256 is signed with P-256 and ES384 with P-384
+		bits := k.Curve.Params().BitSize
+		if alg != fmt.Sprintf("ES%d", bits) {
+			break
+		}
+
+		size := (bits + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("key does not match algorithm %q", alg)
+}
+
+// validateClaims checks the time claims with the leeway, exp is required, and the issuer and the audience when they
+// are configured
+func (a *jwtAuthenticator) validateClaims(claims map[string]interface{}) error {
+	now := time.Now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+
+	if now.After(time.Unix(int64(exp), 0).Add(a.leeway)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if a.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if a.audience != "" && !containsClaim(claims["aud"], a.audience) {
+		return errors.New("token is not issued for this audience")
+	}
+
+	return nil
+}
+
+// scopes returns the scopes of a scope claim, which is a space separated string or an array
+func (a *jwtAuthenticator) scopes(claim interface{}) []string {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = strings.Fields(v)
+	case []interface{}:
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var scopes []string
+	for _, value := range values {
+		if scope, ok := a.scopeMap[value]; ok {
+			scopes = append(scopes, scope)
+		} else if ValidScope(value) {
+			scopes = append(scopes, value)
+		}
+	}
+
+	return scopes
+}
+
+// containsClaim reports whether a claim that is a string or an array of strings contains value
+func containsClaim(claim interface{}, value string) bool {
+	switch v := claim.(type) {
+	case string:
+		return v == value
+	case []interface{}:
+		for _, item := range v {
+			if item == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+)
+
+// testKeys represents the signing keys of a test identity provider and the jwks file of their public keys
+type testKeys struct {
+	ec    *ecdsa.PrivateKey
+	rsa   *rsa.PrivateKey
+	other *ecdsa.PrivateKey
+	jwks  string
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ec key: %v", err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ec key: %v", err)
+	}
+
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "EC",
+				"kid": "ec",
+				"use": "sig",
+				"alg": "ES256",
+				"crv": "P-256",
+				"x":   encodeSegment(ecKey.X.FillBytes(make([]byte, 32))),
+				"y":   encodeSegment(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+			{
+				"kty": "RSA",
+				"kid": "rsa",
+				"use": "sig",
+				"n":   encodeSegment(rsaKey.N.Bytes()),
+				"e":   encodeSegment(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+		},
+	}
+
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to encode jwks: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatalf("failed to write jwks: %v", err)
+	}
+
+	return testKeys{ec: ecKey, rsa: rsaKey, other: other, jwks: path}
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signToken returns a token of claims with header, signed with key for the algorithm of the header. Keys that do not
+// match the algorithm, e.g. of alg none, leave the signature empty.
+func signToken(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	t.Helper()
+
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	input := encodeSegment(h) + "." + encodeSegment(c)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	}
+
+	return input + "." + encodeSegment(signature)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	keys := newTestKeys(t)
+
+	a, err := NewJWTAuthenticator(context.Background(), envvars.Auth{
+		JWTJWKS:        keys.jwks,
+		JWTJWKSRefresh: time.Hour,
+		JWTIssuer:      "https://id.example.com",
+		JWTAudience:    "notify-hub",
+		JWTLeeway:      time.Minute,
+		JWTNameClaim:   "name",
+		JWTScopeClaim:  "scope",
+		JWTTenantClaim: "tenant",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator error = %v", err)
+	}
+
+	now := time.Now()
+
+	// claims returns valid claims with the given changes, nil values remove a claim
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":    "jane",
+			"name":   "Jane Doe",
+			"iss":    "https://id.example.com",
+			"aud":    []string{"notify-hub", "billing"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"scope":  "messages:read messages:write",
+			"tenant": "payments",
+		}
+
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+
+			c[k] = v
+		}
+
+		return c
+	}
+
+	es256 := map[string]interface{}{"alg": "ES256", "kid": "ec", "typ": "JWT"}
+	rs256 := map[string]interface{}{"alg": "RS256", "kid": "rsa", "typ": "JWT"}
+
+	// the jwk of the ec key is what an attacker would use as the secret of a symmetric algorithm
+	publicJWK, _ := os.ReadFile(keys.jwks)
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{
+			name:  "es256",
+			token: signToken(t, es256, claims(nil), keys.ec),
+		},
+		{
+			name:  "rs256",
+			token: signToken(t, rs256, claims(nil), keys.rsa),
+		},
+		{
+			name:  "expired within leeway",
+			token: signToken(t, es256, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()}), keys.ec),
+		},
+		{
+			name:  "not a jwt",
+			token: "nhk_live_0123456789",
+			err:   ErrUnauthenticated.Error(),
+		},
+		{
+			name:  "signed by another key",
+			token: signToken(t, es256, claims(nil), keys.other),
+			err:   "invalid signature",
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				parts := strings.Split(signToken(t, es256, claims(nil), keys.ec), ".")
+				c, _ := json.Marshal(claims(map[string]interface{}{"tenant": "default"}))
+				return parts[0] + "." + encodeSegment(c) + "." + parts[2]
+			}(),
+			err: "invalid signature",
+		},
+		{
+			name:  "alg none",
+			token: signToken(t, map[string]interface{}{"alg": "none", "kid": "ec"}, claims(nil), nil),
+			err:   `unsupported algorithm "none"`,
+		},
+		{
+			name:  "hs256 signed with the public key",
+			token: signToken(t, map[string]interface{}{"alg": "HS256", "kid": "ec"}, claims(nil), publicJWK),
+			err:   `unsupported algorithm "HS256"`,
+		},
+		{
+			name:  "algorithm not allowed by the key",
+			token: signToken(t, map[string]interface{}{"alg": "RS256", "kid": "ec"}, claims(nil), keys.rsa),
+			err:   `does not allow algorithm "RS256"`,
+		},
+		{
+			name:  "algorithm of another key type",
+			token: signToken(t, map[string]interface{}{"alg": "ES256", "kid": "rsa"}, claims(nil), keys.ec),
+			err:   `key does not match algorithm "ES256"`,
+		},
+		{
+			name:  "unknown key",
+			token: signToken(t, map[string]interface{}{"alg": "ES256", "kid": "rotated"}, claims(nil), keys.ec),
+			err:   `unknown key "rotated"`,
+		},
+		{
+			name:  "expired",
+			token: signToken(t, es256, claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()}), keys.ec),
+			err:   "token is expired",
+		},
+		{
+			name:  "no expiry",
+			token: signToken(t, es256, claims(map[string]interface{}{"exp": nil}), keys.ec),
+			err:   "token has no expiry",
+		},
+		{
+			name:  "not valid yet",
+			token: signToken(t, es256, claims(map[string]interface{}{"nbf": now.Add(2 * time.Minute).Unix()}), keys.ec),
+			err:   "token is not valid yet",
+		},
+		{
+			name:  "other issuer",
+			token: signToken(t, es256, claims(map[string]interface{}{"iss": "https://evil.example.com"}), keys.ec),
+			err:   "unexpected issuer",
+		},
+		{
+			name:  "other audience",
+			token: signToken(t, es256, claims(map[string]interface{}{"aud": "billing"}), keys.ec),
+			err:   "not issued for this audience",
+		},
+		{
+			name:  "no subject",
+			token: signToken(t, es256, claims(map[string]interface{}{"sub": nil}), keys.ec),
+			err:   "token has no subject",
+		},
+		{
+			name:  "no tenant",
+			token: signToken(t, es256, claims(map[string]interface{}{"tenant": nil}), keys.ec),
+			err:   "token has no tenant claim",
+		},
+		{
+			name:  "invalid tenant",
+			token: signToken(t, es256, claims(map[string]interface{}{"tenant": "pay ments"}), keys.ec),
+			err:   `invalid tenant "pay ments"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller, err := a.Authenticate(context.Background(), tt.token)
+
+			if tt.err != "" {
+				if !errors.Is(err, ErrUnauthenticated) || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Authenticate error = %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Authenticate error = %v", err)
+			}
+
+			want := Caller{ID: "jwt:jane", Name: "Jane Doe", Tenant: "payments", Scopes: []string{ScopeMessagesRead, ScopeMessagesWrite}}
+			if caller.ID != want.ID || caller.Name != want.Name || caller.Tenant != want.Tenant || strings.Join(caller.Scopes, " ") != strings.Join(want.Scopes, " ") {
+				t.Errorf("Authenticate = %+v, want %+v", caller, want)
+			}
+		})
+	}
+}
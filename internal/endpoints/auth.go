@@ -0,0 +1,86 @@
+package endpoints
+
+import (
+	"notify-hub-backend/internal/auth"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// scopedEndpoint represents an endpoint acting on behalf of a caller with the scope it requires
+type scopedEndpoint struct {
+	endpoint *endpoint.Endpoint
+	scope    string
+}
+
+// scoped returns the endpoints of e that act on behalf of a caller, each with the scope of what it does. Health checks
+// and webhooks of the provider are not listed, they stay public and webhooks authenticate with their own key.
+func scoped(e *Endpoints) []scopedEndpoint {
+	return []scopedEndpoint{
+		{&e.CreateMessageEndpoint, auth.ScopeMessagesWrite},
+		{&e.FetchSentMessagesEndpoint, auth.ScopeMessagesRead},
+		{&e.FetchMessageEndpoint, auth.ScopeMessagesRead},
+		{&e.AddSuppressionEndpoint, auth.ScopeMessagesWrite},
+		{&e.RemoveSuppressionEndpoint, auth.ScopeMessagesWrite},
+		{&e.FetchSuppressionsEndpoint, auth.ScopeMessagesRead},
+		{&e.FetchInboundMessagesEndpoint, auth.ScopeMessagesRead},
+
+		{&e.CreateCampaignEndpoint, auth.ScopeMessagesWrite},
+		{&e.FetchCampaignEndpoint, auth.ScopeMessagesRead},
+		{&e.PauseCampaignEndpoint, auth.ScopeMessagesWrite},
+		{&e.ResumeCampaignEndpoint, auth.ScopeMessagesWrite},
+		{&e.AbortCampaignEndpoint, auth.ScopeMessagesWrite},
+
+		{&e.CreateContactEndpoint, auth.ScopeContactsWrite},
+		{&e.FetchContactsEndpoint, auth.ScopeContactsRead},
+		{&e.FetchContactEndpoint, auth.ScopeContactsRead},
+		{&e.UpdateContactEndpoint, auth.ScopeContactsWrite},
+		{&e.DeleteContactEndpoint, auth.ScopeContactsWrite},
+		{&e.CreateGroupEndpoint, auth.ScopeContactsWrite},
+		{&e.FetchGroupsEndpoint, auth.ScopeContactsRead},
+		{&e.FetchGroupEndpoint, auth.ScopeContactsRead},
+		{&e.UpdateGroupEndpoint, auth.ScopeContactsWrite},
+		{&e.DeleteGroupEndpoint, auth.ScopeContactsWrite},
+
+		{&e.SwitchAutoSendEndpoint, auth.ScopeAdmin},
+		{&e.FetchAutoSendHistoryEndpoint, auth.ScopeAdmin},
+		{&e.SetSendingStateEndpoint, auth.ScopeAdmin},
+		{&e.FetchSendingStatesEndpoint, auth.ScopeAdmin},
+		{&e.FetchSchedulesEndpoint, auth.ScopeAdmin},
+		{&e.UpdateScheduleEndpoint, auth.ScopeAdmin},
+		{&e.FetchJobRunsEndpoint, auth.ScopeAdmin},
+
+		{&e.CreateAPIKeyEndpoint, auth.ScopeAdmin},
+		{&e.FetchAPIKeysEndpoint, auth.ScopeAdmin},
+		{&e.RotateAPIKeyEndpoint, auth.ScopeAdmin},
+		{&e.RevokeAPIKeyEndpoint, auth.ScopeAdmin},
+	}
+}
+
+// hubWide returns the endpoints of e that act on state shared by every tenant, e.g. pausing sending
+func hubWide(e *Endpoints) []*endpoint.Endpoint {
+	return []*endpoint.Endpoint{
+		&e.SwitchAutoSendEndpoint,
+		&e.FetchAutoSendHistoryEndpoint,
+		&e.SetSendingStateEndpoint,
+		&e.FetchSendingStatesEndpoint,
+		&e.FetchSchedulesEndpoint,
+		&e.UpdateScheduleEndpoint,
+		&e.FetchJobRunsEndpoint,
+	}
+}
+
+// Authorize wraps the endpoints acting on behalf of a caller with auth.Middleware so that each endpoint requires the
+// scope of what it does and runs for the caller's tenant. Hub wide endpoints are left to callers of the default
+// tenant, who operate the hub.
+func Authorize(e Endpoints, a auth.Authenticator) Endpoints {
+	for _, ep := range hubWide(&e) {
+		*ep = auth.RequireTenant(tenant.Default)(*ep)
+	}
+
+	for _, se := range scoped(&e) {
+		*se.endpoint = auth.Middleware(a, se.scope)(*se.endpoint)
+	}
+
+	return e
+}
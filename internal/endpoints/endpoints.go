@@ -11,7 +11,52 @@ import (
 type Endpoints struct {
 	HealthEndpoint            endpoint.Endpoint
 	SwitchAutoSendEndpoint    endpoint.Endpoint
+	CreateMessageEndpoint     endpoint.Endpoint
 	FetchSentMessagesEndpoint endpoint.Endpoint
+	AddSuppressionEndpoint    endpoint.Endpoint
+	RemoveSuppressionEndpoint endpoint.Endpoint
+	FetchSuppressionsEndpoint endpoint.Endpoint
+
+	ReceiveInboundMessageEndpoint endpoint.Endpoint
+	FetchInboundMessagesEndpoint  endpoint.Endpoint
+
+	CreateContactEndpoint endpoint.Endpoint
+	FetchContactsEndpoint endpoint.Endpoint
+	FetchContactEndpoint  endpoint.Endpoint
+	UpdateContactEndpoint endpoint.Endpoint
+	DeleteContactEndpoint endpoint.Endpoint
+	CreateGroupEndpoint   endpoint.Endpoint
+	FetchGroupsEndpoint   endpoint.Endpoint
+	FetchGroupEndpoint    endpoint.Endpoint
+	UpdateGroupEndpoint   endpoint.Endpoint
+	DeleteGroupEndpoint   endpoint.Endpoint
+
+	CreateCampaignEndpoint endpoint.Endpoint
+	FetchCampaignEndpoint  endpoint.Endpoint
+	PauseCampaignEndpoint  endpoint.Endpoint
+	ResumeCampaignEndpoint endpoint.Endpoint
+	AbortCampaignEndpoint  endpoint.Endpoint
+
+	FetchMessageEndpoint           endpoint.Endpoint
+	ReceiveDeliveryReceiptEndpoint endpoint.Endpoint
+
+	FetchAutoSendHistoryEndpoint endpoint.Endpoint
+
+	SetSendingStateEndpoint    endpoint.Endpoint
+	FetchSendingStatesEndpoint endpoint.Endpoint
+
+	FetchSchedulesEndpoint endpoint.Endpoint
+	UpdateScheduleEndpoint endpoint.Endpoint
+
+	FetchJobRunsEndpoint endpoint.Endpoint
+
+	LivezEndpoint  endpoint.Endpoint
+	ReadyzEndpoint endpoint.Endpoint
+
+	CreateAPIKeyEndpoint endpoint.Endpoint
+	FetchAPIKeysEndpoint endpoint.Endpoint
+	RotateAPIKeyEndpoint endpoint.Endpoint
+	RevokeAPIKeyEndpoint endpoint.Endpoint
 }
 
 // MakeEndpoints makes and returns endpoints
@@ -19,7 +64,52 @@ func MakeEndpoints(s service.Service) Endpoints {
 	return Endpoints{
 		HealthEndpoint:            MakeHealthEndpoint(s),
 		SwitchAutoSendEndpoint:    MakeSwitchAutoSendEndpoint(s),
+		CreateMessageEndpoint:     MakeCreateMessageEndpoint(s),
 		FetchSentMessagesEndpoint: MakeFetchSentMessagesEndpoint(s),
+		AddSuppressionEndpoint:    MakeAddSuppressionEndpoint(s),
+		RemoveSuppressionEndpoint: MakeRemoveSuppressionEndpoint(s),
+		FetchSuppressionsEndpoint: MakeFetchSuppressionsEndpoint(s),
+
+		ReceiveInboundMessageEndpoint: MakeReceiveInboundMessageEndpoint(s),
+		FetchInboundMessagesEndpoint:  MakeFetchInboundMessagesEndpoint(s),
+
+		CreateContactEndpoint: MakeCreateContactEndpoint(s),
+		FetchContactsEndpoint: MakeFetchContactsEndpoint(s),
+		FetchContactEndpoint:  MakeFetchContactEndpoint(s),
+		UpdateContactEndpoint: MakeUpdateContactEndpoint(s),
+		DeleteContactEndpoint: MakeDeleteContactEndpoint(s),
+		CreateGroupEndpoint:   MakeCreateGroupEndpoint(s),
+		FetchGroupsEndpoint:   MakeFetchGroupsEndpoint(s),
+		FetchGroupEndpoint:    MakeFetchGroupEndpoint(s),
+		UpdateGroupEndpoint:   MakeUpdateGroupEndpoint(s),
+		DeleteGroupEndpoint:   MakeDeleteGroupEndpoint(s),
+
+		CreateCampaignEndpoint: MakeCreateCampaignEndpoint(s),
+		FetchCampaignEndpoint:  MakeFetchCampaignEndpoint(s),
+		PauseCampaignEndpoint:  MakePauseCampaignEndpoint(s),
+		ResumeCampaignEndpoint: MakeResumeCampaignEndpoint(s),
+		AbortCampaignEndpoint:  MakeAbortCampaignEndpoint(s),
+
+		FetchMessageEndpoint:           MakeFetchMessageEndpoint(s),
+		ReceiveDeliveryReceiptEndpoint: MakeReceiveDeliveryReceiptEndpoint(s),
+
+		FetchAutoSendHistoryEndpoint: MakeFetchAutoSendHistoryEndpoint(s),
+
+		SetSendingStateEndpoint:    MakeSetSendingStateEndpoint(s),
+		FetchSendingStatesEndpoint: MakeFetchSendingStatesEndpoint(s),
+
+		FetchSchedulesEndpoint: MakeFetchSchedulesEndpoint(s),
+		UpdateScheduleEndpoint: MakeUpdateScheduleEndpoint(s),
+
+		FetchJobRunsEndpoint: MakeFetchJobRunsEndpoint(s),
+
+		LivezEndpoint:  MakeLivezEndpoint(s),
+		ReadyzEndpoint: MakeReadyzEndpoint(s),
+
+		CreateAPIKeyEndpoint: MakeCreateAPIKeyEndpoint(s),
+		FetchAPIKeysEndpoint: MakeFetchAPIKeysEndpoint(s),
+		RotateAPIKeyEndpoint: MakeRotateAPIKeyEndpoint(s),
+		RevokeAPIKeyEndpoint: MakeRevokeAPIKeyEndpoint(s),
 	}
 }
 
@@ -45,6 +135,17 @@ func MakeSwitchAutoSendEndpoint(s service.Service) endpoint.Endpoint {
 	}
 }
 
+// MakeCreateMessageEndpoint makes and returns create message endpoint
+func MakeCreateMessageEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.CreateMessageRequest)
+
+		res := s.CreateMessage(ctx, *req)
+
+		return res, nil
+	}
+}
+
 // MakeFetchSentMessagesEndpoint makes and returns fetch sent messages endpoint
 func MakeFetchSentMessagesEndpoint(s service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
@@ -55,3 +156,377 @@ func MakeFetchSentMessagesEndpoint(s service.Service) endpoint.Endpoint {
 		return res, nil
 	}
 }
+
+// MakeAddSuppressionEndpoint makes and returns add suppression endpoint
+func MakeAddSuppressionEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.AddSuppressionRequest)
+
+		res := s.AddSuppression(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeRemoveSuppressionEndpoint makes and returns remove suppression endpoint
+func MakeRemoveSuppressionEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.RemoveSuppressionRequest)
+
+		res := s.RemoveSuppression(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchSuppressionsEndpoint makes and returns fetch suppressions endpoint
+func MakeFetchSuppressionsEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchSuppressionsRequest)
+
+		res := s.FetchSuppressions(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeReceiveInboundMessageEndpoint makes and returns receive inbound message endpoint
+func MakeReceiveInboundMessageEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.ReceiveInboundMessageRequest)
+
+		res := s.ReceiveInboundMessage(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchInboundMessagesEndpoint makes and returns fetch inbound messages endpoint
+func MakeFetchInboundMessagesEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchInboundMessagesRequest)
+
+		res := s.FetchInboundMessages(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeCreateContactEndpoint makes and returns create contact endpoint
+func MakeCreateContactEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.CreateContactRequest)
+
+		res := s.CreateContact(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchContactsEndpoint makes and returns fetch contacts endpoint
+func MakeFetchContactsEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchContactsRequest)
+
+		res := s.FetchContacts(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchContactEndpoint makes and returns fetch contact endpoint
+func MakeFetchContactEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchContactRequest)
+
+		res := s.FetchContact(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeUpdateContactEndpoint makes and returns update contact endpoint
+func MakeUpdateContactEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.UpdateContactRequest)
+
+		res := s.UpdateContact(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeDeleteContactEndpoint makes and returns delete contact endpoint
+func MakeDeleteContactEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.DeleteContactRequest)
+
+		res := s.DeleteContact(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeCreateGroupEndpoint makes and returns create group endpoint
+func MakeCreateGroupEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.CreateGroupRequest)
+
+		res := s.CreateGroup(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchGroupsEndpoint makes and returns fetch groups endpoint
+func MakeFetchGroupsEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchGroupsRequest)
+
+		res := s.FetchGroups(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchGroupEndpoint makes and returns fetch group endpoint
+func MakeFetchGroupEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchGroupRequest)
+
+		res := s.FetchGroup(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeUpdateGroupEndpoint makes and returns update group endpoint
+func MakeUpdateGroupEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.UpdateGroupRequest)
+
+		res := s.UpdateGroup(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeDeleteGroupEndpoint makes and returns delete group endpoint
+func MakeDeleteGroupEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.DeleteGroupRequest)
+
+		res := s.DeleteGroup(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeCreateCampaignEndpoint makes and returns create campaign endpoint
+func MakeCreateCampaignEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.CreateCampaignRequest)
+
+		res := s.CreateCampaign(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchCampaignEndpoint makes and returns fetch campaign endpoint
+func MakeFetchCampaignEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchCampaignRequest)
+
+		res := s.FetchCampaign(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakePauseCampaignEndpoint makes and returns pause campaign endpoint
+func MakePauseCampaignEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.PauseCampaignRequest)
+
+		res := s.PauseCampaign(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeResumeCampaignEndpoint makes and returns resume campaign endpoint
+func MakeResumeCampaignEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.ResumeCampaignRequest)
+
+		res := s.ResumeCampaign(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeAbortCampaignEndpoint makes and returns abort campaign endpoint
+func MakeAbortCampaignEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.AbortCampaignRequest)
+
+		res := s.AbortCampaign(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchMessageEndpoint makes and returns fetch message endpoint
+func MakeFetchMessageEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchMessageRequest)
+
+		res := s.FetchMessage(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeReceiveDeliveryReceiptEndpoint makes and returns receive delivery receipt endpoint
+func MakeReceiveDeliveryReceiptEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.ReceiveDeliveryReceiptRequest)
+
+		res := s.ReceiveDeliveryReceipt(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchAutoSendHistoryEndpoint makes and returns fetch auto send history endpoint
+func MakeFetchAutoSendHistoryEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchAutoSendHistoryRequest)
+
+		res := s.FetchAutoSendHistory(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeSetSendingStateEndpoint makes and returns set sending state endpoint
+func MakeSetSendingStateEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.SetSendingStateRequest)
+
+		res := s.SetSendingState(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchSendingStatesEndpoint makes and returns fetch sending states endpoint
+func MakeFetchSendingStatesEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchSendingStatesRequest)
+
+		res := s.FetchSendingStates(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchSchedulesEndpoint makes and returns fetch schedules endpoint
+func MakeFetchSchedulesEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchSchedulesRequest)
+
+		res := s.FetchSchedules(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeUpdateScheduleEndpoint makes and returns update schedule endpoint
+func MakeUpdateScheduleEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.UpdateScheduleRequest)
+
+		res := s.UpdateSchedule(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchJobRunsEndpoint makes and returns fetch job runs endpoint
+func MakeFetchJobRunsEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchJobRunsRequest)
+
+		res := s.FetchJobRuns(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeLivezEndpoint makes and returns livez endpoint
+func MakeLivezEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.LivezRequest)
+
+		res := s.Livez(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeReadyzEndpoint makes and returns readyz endpoint
+func MakeReadyzEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.ReadyzRequest)
+
+		res := s.Readyz(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeCreateAPIKeyEndpoint makes and returns create api key endpoint
+func MakeCreateAPIKeyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.CreateAPIKeyRequest)
+
+		res := s.CreateAPIKey(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeFetchAPIKeysEndpoint makes and returns fetch api keys endpoint
+func MakeFetchAPIKeysEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.FetchAPIKeysRequest)
+
+		res := s.FetchAPIKeys(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeRotateAPIKeyEndpoint makes and returns rotate api key endpoint
+func MakeRotateAPIKeyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.RotateAPIKeyRequest)
+
+		res := s.RotateAPIKey(ctx, *req)
+
+		return res, nil
+	}
+}
+
+// MakeRevokeAPIKeyEndpoint makes and returns revoke api key endpoint
+func MakeRevokeAPIKeyEndpoint(s service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*service.RevokeAPIKeyRequest)
+
+		res := s.RevokeAPIKey(ctx, *req)
+
+		return res, nil
+	}
+}
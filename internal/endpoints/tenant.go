@@ -0,0 +1,31 @@
+package endpoints
+
+import (
+	"context"
+
+	"notify-hub-backend/internal/limit"
+	"notify-hub-backend/internal/tenant"
+)
+
+// DefaultTenant runs the endpoints acting on behalf of a caller for the default tenant, it stands in for Authorize
+// when authentication is disabled.
+func DefaultTenant(e Endpoints) Endpoints {
+	for _, se := range scoped(&e) {
+		next := *se.endpoint
+		*se.endpoint = func(ctx context.Context, request interface{}) (interface{}, error) {
+			return next(tenant.With(ctx, tenant.Default), request)
+		}
+	}
+
+	return e
+}
+
+// Limit wraps the endpoints acting on behalf of a caller with the rate limit of the caller's tenant, the endpoints
+// must be wrapped with Authorize or DefaultTenant afterwards so that the tenant is known when the limit is checked.
+func Limit(e Endpoints, l *limit.Limiter) Endpoints {
+	for _, se := range scoped(&e) {
+		*se.endpoint = limit.Middleware(l)(*se.endpoint)
+	}
+
+	return e
+}
@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+// exit codes returned by Wait
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+)
+
+// CloseTimeout is the deadline of closing a connection, it is suggested for steps added with OnShutdownWithin
+const CloseTimeout = 5 * time.Second
+
+// step represents a named shutdown step, a step with a timeout has a deadline of its own instead of sharing the
+// shutdown timeout
+type step struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+// Manager coordinates the shutdown of the service. It waits for a termination signal or a failure, then runs the
+// shutdown steps in the order they were added. Steps share the shutdown timeout unless they have a deadline of their
+// own, so that steps closing connections still run when waiting for work used up the shutdown timeout.
+type Manager struct {
+	l       log.Logger
+	timeout time.Duration
+	mu      sync.Mutex
+	steps   []step
+	failed  chan error
+	once    sync.Once
+}
+
+// New creates and returns a manager whose shutdown steps must finish within timeout
+func New(l log.Logger, timeout time.Duration) *Manager {
+	return &Manager{
+		l:       l,
+		timeout: timeout,
+		failed:  make(chan error, 1),
+	}
+}
+
+// OnShutdown adds a shutdown step within the shared shutdown timeout, steps run one after another in the order they are
+// added
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.steps = append(m.steps, step{name: name, fn: fn})
+}
+
+// OnShutdownWithin adds a shutdown step with a deadline of timeout of its own, which starts when the step runs
+func (m *Manager) OnShutdownWithin(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.steps = append(m.steps, step{name: name, timeout: timeout, fn: fn})
+}
+
+// Fail starts the shutdown because of err, only the first failure is kept
+func (m *Manager) Fail(err error) {
+	m.once.Do(func() {
+		m.failed <- err
+	})
+}
+
+// Wait blocks until SIGINT or SIGTERM is received or Fail is called, then runs the shutdown steps and returns the
+// exit code. The code is ExitFailure if the shutdown was caused by a failure or a step failed or missed its deadline.
+func (m *Manager) Wait() int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	code := ExitOK
+
+	select {
+	case sig := <-signals:
+		_ = m.l.Log("msg", "shutting down", "signal", sig.String())
+	case err := <-m.failed:
+		_ = m.l.Log("msg", "shutting down", "error", err.Error())
+		code = ExitFailure
+	}
+
+	if m.shutdown() != nil {
+		code = ExitFailure
+	}
+
+	return code
+}
+
+// shutdown runs the shutdown steps and returns the error of the last step that failed
+func (m *Manager) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	m.mu.Lock()
+	steps := m.steps
+	m.mu.Unlock()
+
+	var failed error
+	for _, s := range steps {
+		start := time.Now()
+
+		if err := s.run(ctx); err != nil {
+			_ = m.l.Log("step", s.name, "error", err.Error())
+			failed = err
+
+			continue
+		}
+
+		_ = m.l.Log("step", s.name, "msg", "done", "took", time.Since(start).String())
+	}
+
+	return failed
+}
+
+// run runs the step. A step sharing the shutdown timeout is expected to return once the timeout is over, a step with a
+// deadline of its own is given up on at its deadline so that a close that hangs does not hold up the next steps.
+func (s step) run(shared context.Context) error {
+	if s.timeout <= 0 {
+		return s.fn(shared)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+func TestShutdownDeadlines(t *testing.T) {
+	m := New(log.NewNopLogger(), 20*time.Millisecond)
+
+	var mu sync.Mutex
+	var ran []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ran = append(ran, name)
+	}
+
+	// waiting uses up the shared timeout
+	m.OnShutdown("drain", func(ctx context.Context) error {
+		record("drain")
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	m.OnShutdown("wait", func(ctx context.Context) error {
+		record("wait")
+		return ctx.Err()
+	})
+
+	// a step ignoring its context is given up on at its deadline
+	m.OnShutdownWithin("close queue", 20*time.Millisecond, func(context.Context) error {
+		record("close queue")
+		time.Sleep(time.Second)
+
+		return nil
+	})
+	m.OnShutdownWithin("close redis", time.Second, func(ctx context.Context) error {
+		record("close redis")
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	err := m.shutdown()
+
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Errorf("shutdown took %s, want the stuck step given up on", took)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("shutdown error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []string{"drain", "wait", "close queue", "close redis"}
+	if len(ran) != len(want) {
+		t.Fatalf("ran %v, want %v", ran, want)
+	}
+
+	for i := range want {
+		if ran[i] != want[i] {
+			t.Errorf("ran %v, want %v", ran, want)
+		}
+	}
+}
+
+func TestShutdownCloseAfterTimeout(t *testing.T) {
+	m := New(log.NewNopLogger(), time.Millisecond)
+
+	var closeErr error
+	m.OnShutdown("drain", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	m.OnShutdownWithin("close redis", time.Second, func(ctx context.Context) error {
+		closeErr = ctx.Err()
+		return nil
+	})
+
+	if err := m.shutdown(); err != nil {
+		t.Fatalf("shutdown error = %v", err)
+	}
+
+	if closeErr != nil {
+		t.Errorf("close step context error = %v, want a live context after the shared timeout", closeErr)
+	}
+}
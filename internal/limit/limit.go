@@ -0,0 +1,232 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	rest "notify-hub-backend"
+	envvars "notify-hub-backend/configs/env-vars"
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// kinds of limits
+const (
+	KindRate  = "rate"
+	KindQuota = "quota"
+)
+
+// counter keys, rate counters are kept per minute and quota counters per UTC day
+const (
+	rateKey   = "ratelimit:"
+	quotaKey  = "quota:"
+	rateTTL   = 2 * time.Minute
+	quotaTTL  = 48 * time.Hour
+	dayLayout = "20060102"
+)
+
+// ErrNoTenant is returned for requests whose context carries no tenant, their limits are unknown
+var ErrNoTenant = errors.New("request has no tenant")
+
+// Exceeded represents a request of a tenant over one of its limits, RetryAfter is the time left until the limit resets
+type Exceeded struct {
+	Kind       string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *Exceeded) Error() string {
+	if e.Kind == KindQuota {
+		return fmt.Sprintf("daily send quota of %d messages exceeded", e.Limit)
+	}
+
+	return fmt.Sprintf("rate limit of %d requests per minute exceeded", e.Limit)
+}
+
+// APIError returns the api error of e, which is written with 429
+func (e *Exceeded) APIError() *rest.APIError {
+	errType := rest.ErrorTypeRateLimited
+	if e.Kind == KindQuota {
+		errType = rest.ErrorTypeQuotaExceeded
+	}
+
+	return &rest.APIError{
+		Message:    e.Error(),
+		Code:       http.StatusTooManyRequests,
+		Type:       errType,
+		RetryAfter: int(math.Ceil(e.RetryAfter.Seconds())),
+	}
+}
+
+// limits represents the limit of every tenant and the limits of single tenants, 0 disables a limit
+type limits struct {
+	all     int
+	tenants map[string]int
+}
+
+// parseLimits returns the limits of overrides given as tenant=limit pairs
+func parseLimits(all int, overrides []string) (limits, error) {
+	l := limits{all: all, tenants: make(map[string]int, len(overrides))}
+
+	for _, pair := range overrides {
+		id, value, ok := strings.Cut(pair, "=")
+		if !ok || !tenant.Valid(id) {
+			return l, fmt.Errorf("invalid limit %q, limits are tenant=limit", pair)
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return l, fmt.Errorf("invalid limit %q, limits are tenant=limit", pair)
+		}
+
+		l.tenants[id] = n
+	}
+
+	return l, nil
+}
+
+func (l limits) of(id string) int {
+	if n, ok := l.tenants[id]; ok {
+		return n
+	}
+
+	return l.all
+}
+
+// Limiter enforces the rate limit and the daily send quota of tenants with counters in redis, the store keeps a
+// counter per tenant. A counter that can not be read lets the request through so that redis being unavailable does
+// not take the api down with it.
+type Limiter struct {
+	l     log.Logger
+	rs    redisstore.Store
+	rate  limits
+	quota limits
+}
+
+// New creates and returns limiter of the tenant limits of cfg, the overrides are parsed so that a wrong one fails at
+// startup
+func New(l log.Logger, rs redisstore.Store, cfg envvars.Tenant) (*Limiter, error) {
+	rate, err := parseLimits(cfg.RateLimit, cfg.RateLimitOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err := parseLimits(cfg.DailyQuota, cfg.DailyQuotaOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		l:     l,
+		rs:    rs,
+		rate:  rate,
+		quota: quota,
+	}, nil
+}
+
+// Allow counts a request of the tenant of ctx in the current minute, it returns *Exceeded once the tenant made more
+// requests in the minute than its rate limit. Requests of a context without a tenant are refused with ErrNoTenant.
+func (l *Limiter) Allow(ctx context.Context) error {
+	id, ok := tenant.From(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+
+	limit := l.rate.of(id)
+	if limit == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	window := now.Truncate(time.Minute)
+
+	count, err := l.rs.IncrBy(ctx, rateKey+strconv.FormatInt(window.Unix(), 10), 1, rateTTL)
+	if err != nil {
+		l.log(err, "Allow")
+		return nil
+	}
+
+	if count > int64(limit) {
+		return &Exceeded{Kind: KindRate, Limit: limit, RetryAfter: window.Add(time.Minute).Sub(now)}
+	}
+
+	return nil
+}
+
+// Reserve counts n messages queued today against the daily send quota of the tenant of ctx, nothing is counted and
+// *Exceeded is returned if the messages do not fit in what is left of the quota. Messages of a context without a tenant
+// are refused with ErrNoTenant.
+func (l *Limiter) Reserve(ctx context.Context, n int) error {
+	id, ok := tenant.From(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+
+	limit := l.quota.of(id)
+	if limit == 0 || n == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	key := quotaKey + now.Format(dayLayout)
+
+	count, err := l.rs.IncrBy(ctx, key, int64(n), quotaTTL)
+	if err != nil {
+		l.log(err, "Reserve")
+		return nil
+	}
+
+	if count <= int64(limit) {
+		return nil
+	}
+
+	if _, err := l.rs.IncrBy(ctx, key, -int64(n), quotaTTL); err != nil {
+		l.log(err, "Reserve")
+	}
+
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	return &Exceeded{Kind: KindQuota, Limit: limit, RetryAfter: tomorrow.Sub(now)}
+}
+
+// Release gives n messages reserved today back to the daily send quota of the tenant of ctx, e.g. when they could not
+// be queued
+func (l *Limiter) Release(ctx context.Context, n int) {
+	id, ok := tenant.From(ctx)
+	if !ok || l.quota.of(id) == 0 || n == 0 {
+		return
+	}
+
+	key := quotaKey + time.Now().UTC().Format(dayLayout)
+	if _, err := l.rs.IncrBy(ctx, key, -int64(n), quotaTTL); err != nil {
+		l.log(err, "Release")
+	}
+}
+
+// Middleware returns an endpoint middleware that rejects requests of tenants over their rate limit, the tenant must
+// be in the context already
+func Middleware(l *Limiter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if err := l.Allow(ctx); err != nil {
+				return nil, err
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+func (l *Limiter) log(err error, method string) {
+	_ = level.Error(l.l).Log("action", "Limit", "method", method, "error", err.Error())
+}
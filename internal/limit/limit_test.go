@@ -0,0 +1,221 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+// fakeStore keeps the counters of the limiter in memory per tenant, the other methods of the store are not used
+type fakeStore struct {
+	redisstore.Store
+	counters map[string]int64
+	err      error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{counters: make(map[string]int64)}
+}
+
+func (s *fakeStore) IncrBy(ctx context.Context, key string, n int64, _ time.Duration) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	id, ok := tenant.From(ctx)
+	if !ok {
+		return 0, redisstore.ErrNoTenant
+	}
+
+	key = id + ":" + key
+	s.counters[key] += n
+
+	return s.counters[key], nil
+}
+
+func (s *fakeStore) quota(id string) int64 {
+	return s.counters[id+":"+quotaKey+time.Now().UTC().Format(dayLayout)]
+}
+
+func newTestLimiter(t *testing.T, rs redisstore.Store) *Limiter {
+	t.Helper()
+
+	l, err := New(log.NewNopLogger(), rs, envvars.Tenant{
+		RateLimit:           3,
+		RateLimitOverrides:  []string{"bulk=0"},
+		DailyQuota:          10,
+		DailyQuotaOverrides: []string{"small=4", "bulk=0"},
+	})
+	if err != nil {
+		t.Fatalf("New error = %v", err)
+	}
+
+	return l
+}
+
+func TestLimiterReserveRelease(t *testing.T) {
+	// step reserves n messages, or releases -n of them
+	type step struct {
+		n        int
+		exceeded bool
+		counted  int64
+	}
+
+	tests := []struct {
+		name   string
+		tenant string
+		steps  []step
+	}{
+		{
+			name:   "up to the quota",
+			tenant: "payments",
+			steps:  []step{{n: 6, counted: 6}, {n: 4, counted: 10}, {n: 1, exceeded: true, counted: 10}},
+		},
+		{
+			name:   "over the quota counts nothing",
+			tenant: "payments",
+			steps:  []step{{n: 8, counted: 8}, {n: 3, exceeded: true, counted: 8}, {n: 2, counted: 10}},
+		},
+		{
+			name:   "release gives messages back",
+			tenant: "payments",
+			steps:  []step{{n: 10, counted: 10}, {n: -4, counted: 6}, {n: 4, counted: 10}},
+		},
+		{
+			name:   "override",
+			tenant: "small",
+			steps:  []step{{n: 5, exceeded: true, counted: 0}, {n: 4, counted: 4}},
+		},
+		{
+			name:   "disabled by override",
+			tenant: "bulk",
+			steps:  []step{{n: 1000, counted: 0}, {n: -10, counted: 0}},
+		},
+		{
+			name:   "nothing to reserve",
+			tenant: "payments",
+			steps:  []step{{n: 0, counted: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := newFakeStore()
+			l := newTestLimiter(t, rs)
+			ctx := tenant.With(context.Background(), tt.tenant)
+
+			for i, s := range tt.steps {
+				if s.n < 0 {
+					l.Release(ctx, -s.n)
+				} else {
+					err := l.Reserve(ctx, s.n)
+
+					var exceeded *Exceeded
+					if errors.As(err, &exceeded) != s.exceeded {
+						t.Fatalf("step %d: Reserve(%d) error = %v, want exceeded %v", i, s.n, err, s.exceeded)
+					}
+
+					if exceeded != nil && (exceeded.Kind != KindQuota || exceeded.RetryAfter <= 0 || exceeded.RetryAfter > 24*time.Hour) {
+						t.Errorf("step %d: Reserve(%d) = %+v", i, s.n, *exceeded)
+					}
+				}
+
+				if got := rs.quota(tt.tenant); got != s.counted {
+					t.Fatalf("step %d: counted %d messages, want %d", i, got, s.counted)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterQuotaPerTenant(t *testing.T) {
+	rs := newFakeStore()
+	l := newTestLimiter(t, rs)
+
+	if err := l.Reserve(tenant.With(context.Background(), "payments"), 10); err != nil {
+		t.Fatalf("Reserve error = %v", err)
+	}
+
+	if err := l.Reserve(tenant.With(context.Background(), "growth"), 10); err != nil {
+		t.Fatalf("Reserve of another tenant error = %v", err)
+	}
+
+	if got := rs.quota("growth"); got != 10 {
+		t.Errorf("growth counted %d messages, want 10", got)
+	}
+
+	// a caller that lost its tenant is refused instead of counting against the quota of another tenant
+	if err := l.Reserve(context.Background(), 1); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Reserve without a tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if err := l.Allow(context.Background()); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Allow without a tenant error = %v, want %v", err, ErrNoTenant)
+	}
+
+	if got := rs.quota("payments") + rs.quota("growth"); got != 20 {
+		t.Errorf("tenants counted %d messages, want 20", got)
+	}
+}
+
+func TestLimiterFailsOpen(t *testing.T) {
+	rs := newFakeStore()
+	rs.err = errors.New("connection refused")
+	l := newTestLimiter(t, rs)
+	ctx := tenant.With(context.Background(), "payments")
+
+	if err := l.Reserve(ctx, 100); err != nil {
+		t.Errorf("Reserve error = %v, want nil while redis is unavailable", err)
+	}
+
+	if err := l.Allow(ctx); err != nil {
+		t.Errorf("Allow error = %v, want nil while redis is unavailable", err)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	rs := newFakeStore()
+	l := newTestLimiter(t, rs)
+
+	// requests are counted per minute, they are kept out of the turn of a minute
+	if next := time.Now().Truncate(time.Minute).Add(time.Minute); time.Until(next) < time.Second {
+		time.Sleep(time.Until(next))
+	}
+
+	tests := []struct {
+		tenant   string
+		requests int
+		allowed  int
+	}{
+		{tenant: "payments", requests: 5, allowed: 3},
+		{tenant: "bulk", requests: 5, allowed: 5},
+	}
+
+	for _, tt := range tests {
+		ctx := tenant.With(context.Background(), tt.tenant)
+
+		allowed := 0
+		for i := 0; i < tt.requests; i++ {
+			err := l.Allow(ctx)
+
+			var exceeded *Exceeded
+			switch {
+			case err == nil:
+				allowed++
+			case !errors.As(err, &exceeded) || exceeded.Kind != KindRate:
+				t.Fatalf("Allow error = %v, want a rate limit", err)
+			}
+		}
+
+		if allowed != tt.allowed {
+			t.Errorf("tenant %s: allowed %d of %d requests, want %d", tt.tenant, allowed, tt.requests, tt.allowed)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// e164 limits, country calling code included
+const (
+	minDigits = 8
+	maxDigits = 15
+)
+
+// minNationalDigits represents shortest accepted number without country calling code
+const minNationalDigits = 6
+
+// ErrInvalidNumber represents malformed phone number error
+var ErrInvalidNumber = errors.New("invalid phone number")
+
+// Region represents numbering plan of a region
+type Region struct {
+	CallingCode string
+	TrunkPrefix string
+}
+
+// regions contains numbering plans keyed by ISO 3166-1 alpha-2 region code
+var regions = map[string]Region{
+	"AT": {CallingCode: "43", TrunkPrefix: "0"},
+	"AU": {CallingCode: "61", TrunkPrefix: "0"},
+	"AZ": {CallingCode: "994", TrunkPrefix: "0"},
+	"BE": {CallingCode: "32", TrunkPrefix: "0"},
+	"BR": {CallingCode: "55", TrunkPrefix: "0"},
+	"CA": {CallingCode: "1", TrunkPrefix: "1"},
+	"CH": {CallingCode: "41", TrunkPrefix: "0"},
+	"DE": {CallingCode: "49", TrunkPrefix: "0"},
+	"DK": {CallingCode: "45"},
+	"ES": {CallingCode: "34"},
+	"FR": {CallingCode: "33", TrunkPrefix: "0"},
+	"GB": {CallingCode: "44", TrunkPrefix: "0"},
+	"GR": {CallingCode: "30"},
+	"IE": {CallingCode: "353", TrunkPrefix: "0"},
+	"IN": {CallingCode: "91", TrunkPrefix: "0"},
+	"IT": {CallingCode: "39"},
+	"NL": {CallingCode: "31", TrunkPrefix: "0"},
+	"NO": {CallingCode: "47"},
+	"PL": {CallingCode: "48"},
+	"PT": {CallingCode: "351"},
+	"RO": {CallingCode: "40", TrunkPrefix: "0"},
+	"SE": {CallingCode: "46", TrunkPrefix: "0"},
+	"TR": {CallingCode: "90", TrunkPrefix: "0"},
+	"UA": {CallingCode: "380", TrunkPrefix: "0"},
+	"US": {CallingCode: "1", TrunkPrefix: "1"},
+}
+
+// SupportedRegion reports whether numbering plan of region is known
+func SupportedRegion(region string) bool {
+	_, ok := regions[strings.ToUpper(region)]
+	return ok
+}
+
+// Valid reports whether number is syntactically a phone number in international or national format,
+// regardless of the region it belongs to
+func Valid(number string) bool {
+	digits, _ := clean(number)
+	return len(digits) >= minNationalDigits && len(digits) <= maxDigits && isDigits(digits)
+}
+
+// Normalize returns number in E.164 format, national numbers are prefixed with calling code of defaultRegion
+func Normalize(number, defaultRegion string) (string, error) {
+	digits, international := clean(number)
+	if !isDigits(digits) {
+		return "", fmt.Errorf("%w, %q contains characters other than digits", ErrInvalidNumber, number)
+	}
+
+	if !international {
+		r, ok := regions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", fmt.Errorf("%w, unsupported default region %q", ErrInvalidNumber, defaultRegion)
+		}
+
+		digits = r.CallingCode + strings.TrimPrefix(digits, r.TrunkPrefix)
+	}
+
+	if len(digits) < minDigits || len(digits) > maxDigits {
+		return "", fmt.Errorf("%w, %q must have between %d and %d digits including country code", ErrInvalidNumber, number, minDigits, maxDigits)
+	}
+
+	if digits[0] == '0' {
+		return "", fmt.Errorf("%w, %q has no valid country code", ErrInvalidNumber, number)
+	}
+
+	return "+" + digits, nil
+}
+
+// clean strips formatting characters and international prefixes, and reports whether number was in international format
+func clean(number string) (string, bool) {
+	number = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.', '/', '\t':
+			return -1
+		}
+		return r
+	}, number)
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+		return number[1:], true
+	case strings.HasPrefix(number, "00"):
+		return number[2:], true
+	}
+
+	return number, false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		number        string
+		defaultRegion string
+		want          string
+		err           error
+	}{
+		{name: "e164", number: "+905551234567", defaultRegion: "TR", want: "+905551234567"},
+		{name: "formatted international", number: "+90 (555) 123-45.67", defaultRegion: "TR", want: "+905551234567"},
+		{name: "double zero prefix", number: "00905551234567", defaultRegion: "US", want: "+905551234567"},
+		{name: "national with trunk prefix", number: "0555 123 45 67", defaultRegion: "TR", want: "+905551234567"},
+		{name: "national without trunk prefix", number: "5551234567", defaultRegion: "TR", want: "+905551234567"},
+		{name: "lower case region", number: "030 1234567", defaultRegion: "de", want: "+49301234567"},
+		{name: "region without trunk prefix", number: "612345678", defaultRegion: "ES", want: "+34612345678"},
+		{name: "letters", number: "+90555CALLME", defaultRegion: "TR", err: ErrInvalidNumber},
+		{name: "empty", number: "", defaultRegion: "TR", err: ErrInvalidNumber},
+		{name: "unsupported region", number: "5551234567", defaultRegion: "XX", err: ErrInvalidNumber},
+		{name: "too short", number: "+1234567", defaultRegion: "TR", err: ErrInvalidNumber},
+		{name: "too long", number: "+1234567890123456", defaultRegion: "TR", err: ErrInvalidNumber},
+		{name: "no country code", number: "+0555123456", defaultRegion: "TR", err: ErrInvalidNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.number, tt.defaultRegion)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Normalize(%q, %q) error = %v, want %v", tt.number, tt.defaultRegion, err, tt.err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Normalize(%q, %q) = %q, want %q", tt.number, tt.defaultRegion, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{number: "+905551234567", want: true},
+		{number: "0555 123 45 67", want: true},
+		{number: "123456", want: true},
+		{number: "12345", want: false},
+		{number: "+1234567890123456", want: false},
+		{number: "555-CALL-ME", want: false},
+		{number: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Valid(tt.number); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
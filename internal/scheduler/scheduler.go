@@ -0,0 +1,365 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+
+	"github.com/go-kit/log"
+	"github.com/robfig/cron/v3"
+)
+
+// lockKeyPrefix is the redis key prefix of job locks
+const lockKeyPrefix = "lock:job:"
+
+var (
+	// ErrUnknownJob represents a job name that is not added to the scheduler
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrInvalidSpec represents a schedule spec that cron can not parse
+	ErrInvalidSpec = errors.New("invalid schedule spec")
+	// ErrStopped represents a trigger of a scheduler that is stopped
+	ErrStopped = errors.New("scheduler is stopped")
+)
+
+// Job represents a function run on a schedule, it returns the number of items it processed
+type Job func(ctx context.Context) (int, error)
+
+// Run represents the outcome of a job run, Error is empty when the run succeeded
+type Run struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Processed  int
+	Error      string
+}
+
+// Entry represents a scheduled job, Next is zero until the scheduler is started
+type Entry struct {
+	Name    string
+	Spec    string
+	Next    time.Time
+	Running bool
+	LastRun *Run
+}
+
+// Scheduler runs named jobs on cron schedules that can be changed while it runs. A run is skipped while the previous
+// run of the job is still in progress, and runs of exclusive jobs are skipped while the job runs on another instance.
+type Scheduler interface {
+	Add(name, spec string, exclusive bool, job Job) error
+	Reschedule(name, spec string) error
+	Trigger(name string) error
+	Entry(name string) (Entry, error)
+	Entries() []Entry
+	Start()
+	Stop() context.Context
+}
+
+type job struct {
+	id        cron.EntryID
+	spec      string
+	exclusive bool
+	run       Job
+	running   bool
+	lastRun   *Run
+}
+
+type scheduler struct {
+	ctx      context.Context
+	l        log.Logger
+	rs       redisstore.Store
+	ps       postgrestore.Store
+	instance string
+	lockTTL  time.Duration
+	c        *cron.Cron
+	mu       sync.Mutex
+	jobs     map[string]*job
+	stopped  bool
+	triggers sync.WaitGroup
+}
+
+// New returns a scheduler running jobs with ctx. Exclusive jobs are locked in redis for lockTTL, the lock is
+// refreshed while the job runs. Each run is recorded in postgres with the name of the instance.
+func New(ctx context.Context, l log.Logger, rs redisstore.Store, ps postgrestore.Store, instance string, lockTTL time.Duration) Scheduler {
+	return &scheduler{
+		ctx:      ctx,
+		l:        l,
+		rs:       rs,
+		ps:       ps,
+		instance: instance,
+		lockTTL:  lockTTL,
+		c:        cron.New(),
+		jobs:     make(map[string]*job),
+	}
+}
+
+// ParseSpec validates a schedule spec, it accepts standard cron expressions and descriptors such as @every 2m.
+func ParseSpec(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s", ErrInvalidSpec, err.Error())
+	}
+
+	return schedule, nil
+}
+
+// Add schedules a job under name, a job already added under name is replaced.
+func (s *scheduler) Add(name, spec string, exclusive bool, run Job) error {
+	schedule, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j, ok := s.jobs[name]; ok {
+		s.c.Remove(j.id)
+	}
+
+	j := &job{spec: spec, exclusive: exclusive, run: run}
+	j.id = s.c.Schedule(schedule, s.wrap(name, j))
+	s.jobs[name] = j
+
+	return nil
+}
+
+// Reschedule changes the schedule of a job. The entry is replaced under the lock, so the job is never registered twice
+// or missing, and a run in progress is not interrupted.
+func (s *scheduler) Reschedule(name, spec string) error {
+	schedule, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w, %s", ErrUnknownJob, name)
+	}
+
+	if j.spec == spec {
+		return nil
+	}
+
+	s.c.Remove(j.id)
+	j.id = s.c.Schedule(schedule, s.wrap(name, j))
+	j.spec = spec
+
+	return nil
+}
+
+// Trigger runs a job right away and waits for it, the run is skipped like a scheduled one if the job is running. A
+// stopped scheduler is not triggered, and Stop waits for triggered runs like scheduled ones.
+func (s *scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return ErrStopped
+	}
+
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w, %s", ErrUnknownJob, name)
+	}
+
+	s.triggers.Add(1)
+	s.mu.Unlock()
+
+	defer s.triggers.Done()
+
+	s.runJob(name, j)
+
+	return nil
+}
+
+// Entry returns the scheduled job of name
+func (s *scheduler) Entry(name string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return Entry{}, fmt.Errorf("%w, %s", ErrUnknownJob, name)
+	}
+
+	return s.entry(name, j), nil
+}
+
+// Entries returns every scheduled job ordered by name
+func (s *scheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.jobs))
+	for name, j := range s.jobs {
+		entries = append(entries, s.entry(name, j))
+	}
+
+	sort.Slice(entries, func(i, k int) bool {
+		return entries[i].Name < entries[k].Name
+	})
+
+	return entries
+}
+
+// Start starts running the jobs in the background
+func (s *scheduler) Start() {
+	s.c.Start()
+}
+
+// Stop stops scheduling and triggering new runs, the returned context is done once running jobs are finished
+func (s *scheduler) Stop() context.Context {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	scheduled := s.c.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer cancel()
+
+		<-scheduled.Done()
+		s.triggers.Wait()
+	}()
+
+	return ctx
+}
+
+func (s *scheduler) entry(name string, j *job) Entry {
+	entry := Entry{
+		Name:    name,
+		Spec:    j.spec,
+		Next:    s.c.Entry(j.id).Next,
+		Running: j.running,
+	}
+
+	if j.lastRun != nil {
+		run := *j.lastRun
+		entry.LastRun = &run
+	}
+
+	return entry
+}
+
+// wrap returns a cron job running j
+func (s *scheduler) wrap(name string, j *job) cron.FuncJob {
+	return func() {
+		s.runJob(name, j)
+	}
+}
+
+// runJob runs j unless it is already running, locks exclusive jobs across instances and records the run
+func (s *scheduler) runJob(name string, j *job) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		_ = s.l.Log("job", name, "msg", "skipped, the previous run is still running")
+
+		return
+	}
+
+	j.running = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	if j.exclusive {
+		lock, err := s.rs.TryLock(ctx, lockKeyPrefix+name, s.lockTTL)
+		if err != nil {
+			_ = s.l.Log("job", name, "error", fmt.Sprintf("failed to acquire lock: %s", err.Error()))
+			return
+		}
+
+		// the job is running on another instance
+		if lock == nil {
+			return
+		}
+
+		defer s.keepLock(name, lock, cancel)()
+	}
+
+	run := Run{StartedAt: time.Now()}
+
+	processed, err := j.run(ctx)
+	if err != nil {
+		run.Error = err.Error()
+		_ = s.l.Log("job", name, "error", err.Error())
+	}
+
+	run.FinishedAt = time.Now()
+	run.Processed = processed
+
+	s.mu.Lock()
+	j.lastRun = &run
+	s.mu.Unlock()
+
+	err = s.ps.InsertJobRun(s.ctx, &postgrestore.JobRun{
+		Name:       name,
+		Instance:   s.instance,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.FinishedAt.Sub(run.StartedAt).Milliseconds(),
+		Processed:  run.Processed,
+		Error:      run.Error,
+	})
+	if err != nil {
+		_ = s.l.Log("job", name, "error", err.Error())
+	}
+}
+
+// keepLock refreshes lock until the returned function is called, which stops refreshing and releases the lock. A lock
+// that is lost, e.g. it expired and was acquired by another instance, cancels the run through cancel so that the job
+// does not run on two instances.
+func (s *scheduler) keepLock(name string, lock redisstore.Lock, cancel context.CancelFunc) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(s.lockTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := lock.Refresh(s.ctx)
+				if errors.Is(err, redisstore.ErrLockNotHeld) {
+					_ = s.l.Log("job", name, "error", "lock is lost, the run is cancelled")
+					cancel()
+
+					return
+				} else if err != nil {
+					_ = s.l.Log("job", name, "error", fmt.Sprintf("failed to refresh lock: %s", err.Error()))
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+
+		if err := lock.Release(s.ctx); err != nil {
+			_ = s.l.Log("job", name, "error", fmt.Sprintf("failed to release lock: %s", err.Error()))
+		}
+	}
+}
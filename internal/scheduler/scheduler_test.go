@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+
+	"github.com/go-kit/log"
+)
+
+// fakeRuns records job runs, the methods that are not used by the scheduler are left to the embedded interface
+type fakeRuns struct {
+	postgrestore.Store
+	mu   sync.Mutex
+	runs []postgrestore.JobRun
+}
+
+func (f *fakeRuns) InsertJobRun(_ context.Context, run *postgrestore.JobRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.runs = append(f.runs, *run)
+
+	return nil
+}
+
+// fakeLocks hands out locks, held is the lock of another instance and lost makes refreshes fail
+type fakeLocks struct {
+	redisstore.Store
+	held bool
+	lost bool
+}
+
+func (f *fakeLocks) TryLock(context.Context, string, time.Duration) (redisstore.Lock, error) {
+	if f.held {
+		return nil, nil
+	}
+
+	return &fakeLock{lost: f.lost}, nil
+}
+
+type fakeLock struct {
+	lost bool
+}
+
+func (l *fakeLock) Refresh(context.Context) error {
+	if l.lost {
+		return redisstore.ErrLockNotHeld
+	}
+
+	return nil
+}
+
+func (l *fakeLock) Release(context.Context) error {
+	return nil
+}
+
+func newTestScheduler(rs redisstore.Store, ps postgrestore.Store) *scheduler {
+	return New(context.Background(), log.NewNopLogger(), rs, ps, "test", 30*time.Millisecond).(*scheduler)
+}
+
+func TestReschedule(t *testing.T) {
+	s := newTestScheduler(&fakeLocks{}, &fakeRuns{})
+
+	if err := s.Add("send-messages", "@every 2m", true, func(context.Context) (int, error) { return 0, nil }); err != nil {
+		t.Fatalf("Add error = %v", err)
+	}
+
+	if err := s.Add("relay-outbox", "not a spec", true, func(context.Context) (int, error) { return 0, nil }); !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("Add with an invalid spec error = %v, want %v", err, ErrInvalidSpec)
+	}
+
+	tests := []struct {
+		name string
+		job  string
+		spec string
+		want string
+		err  error
+	}{
+		{name: "new spec", job: "send-messages", spec: "@every 30s", want: "@every 30s"},
+		{name: "same spec", job: "send-messages", spec: "@every 30s", want: "@every 30s"},
+		{name: "invalid spec", job: "send-messages", spec: "every minute", want: "@every 30s", err: ErrInvalidSpec},
+		{name: "unknown job", job: "relay-outbox", spec: "@every 30s", want: "@every 30s", err: ErrUnknownJob},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Reschedule(tt.job, tt.spec); !errors.Is(err, tt.err) {
+				t.Fatalf("Reschedule error = %v, want %v", err, tt.err)
+			}
+
+			entries := s.Entries()
+			if len(entries) != 1 || entries[0].Spec != tt.want {
+				t.Errorf("entries = %+v, want send-messages at %s", entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunOutcome(t *testing.T) {
+	ps := &fakeRuns{}
+	s := newTestScheduler(&fakeLocks{}, ps)
+
+	running := make(chan Entry, 1)
+	fail := errors.New("connection refused")
+
+	_ = s.Add("send-messages", "@every 2m", true, func(context.Context) (int, error) {
+		entry, _ := s.Entry("send-messages")
+		running <- entry
+
+		return 3, fail
+	})
+
+	if entry, _ := s.Entry("send-messages"); entry.LastRun != nil || entry.Running {
+		t.Fatalf("entry before any run = %+v", entry)
+	}
+
+	j := s.jobs["send-messages"]
+	s.wrap("send-messages", j)()
+
+	if entry := <-running; !entry.Running {
+		t.Error("entry during the run is not running")
+	}
+
+	entry, err := s.Entry("send-messages")
+	if err != nil {
+		t.Fatalf("Entry error = %v", err)
+	}
+
+	if entry.Running || entry.LastRun == nil || entry.LastRun.Error != fail.Error() || entry.LastRun.Processed != 3 {
+		t.Errorf("entry after the run = %+v, want the failed run recorded", entry)
+	}
+
+	if entry.LastRun.FinishedAt.Before(entry.LastRun.StartedAt) {
+		t.Errorf("last run finished at %v before it started at %v", entry.LastRun.FinishedAt, entry.LastRun.StartedAt)
+	}
+
+	if len(ps.runs) != 1 || ps.runs[0].Instance != "test" || ps.runs[0].Error != fail.Error() {
+		t.Errorf("recorded runs = %+v, want the failed run of test", ps.runs)
+	}
+}
+
+func TestRunSkipped(t *testing.T) {
+	tests := []struct {
+		name      string
+		exclusive bool
+		held      bool
+		running   bool
+	}{
+		{name: "locked by another instance", exclusive: true, held: true},
+		{name: "previous run in progress", running: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &fakeRuns{}
+			s := newTestScheduler(&fakeLocks{held: tt.held}, ps)
+
+			runs := 0
+			_ = s.Add("send-messages", "@every 2m", tt.exclusive, func(context.Context) (int, error) {
+				runs++
+				return 0, nil
+			})
+
+			s.jobs["send-messages"].running = tt.running
+
+			if err := s.Trigger("send-messages"); err != nil {
+				t.Fatalf("Trigger error = %v", err)
+			}
+
+			if runs != 0 || len(ps.runs) != 0 {
+				t.Errorf("ran %d times and recorded %+v, want the run skipped", runs, ps.runs)
+			}
+		})
+	}
+}
+
+func TestLostLockCancelsRun(t *testing.T) {
+	s := newTestScheduler(&fakeLocks{lost: true}, &fakeRuns{})
+
+	_ = s.Add("send-messages", "@every 2m", true, func(ctx context.Context) (int, error) {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return 0, nil
+		}
+	})
+
+	if err := s.Trigger("send-messages"); err != nil {
+		t.Fatalf("Trigger error = %v", err)
+	}
+
+	entry, _ := s.Entry("send-messages")
+	if entry.LastRun == nil || entry.LastRun.Error != context.Canceled.Error() {
+		t.Errorf("last run = %+v, want the run cancelled once the lock is lost", entry.LastRun)
+	}
+}
+
+func TestStopWaitsForTriggers(t *testing.T) {
+	s := newTestScheduler(&fakeLocks{}, &fakeRuns{})
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+
+	_ = s.Add("send-messages", "@every 2m", false, func(context.Context) (int, error) {
+		close(started)
+		<-finish
+
+		return 0, nil
+	})
+
+	go func() {
+		_ = s.Trigger("send-messages")
+	}()
+
+	<-started
+
+	stopped := s.Stop()
+
+	select {
+	case <-stopped.Done():
+		t.Fatal("Stop is done while a triggered run is in progress")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(finish)
+	<-stopped.Done()
+
+	if err := s.Trigger("send-messages"); !errors.Is(err, ErrStopped) {
+		t.Errorf("Trigger after Stop error = %v, want %v", err, ErrStopped)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	rest "notify-hub-backend"
+	"notify-hub-backend/internal/auth"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+const (
+	apiKeyNotFoundError = "api key not found"
+	invalidScopeError   = "validation failed, field: scopes, unknown scope "
+)
+
+// CreateAPIKey returns create api key
+// swagger:operation POST /api-keys createAPIKeyRequest
+// ---
+// summary: Create API Key
+// description: Creates an api key of the caller's tenant with the given scopes. The key is only returned in this response, it is stored hashed.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/createAPIKeyResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) CreateAPIKey(ctx context.Context, req rest.CreateAPIKeyRequest) rest.CreateAPIKeyResponse {
+	res := rest.CreateAPIKeyResponse{}
+
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(scope) {
+			res.Result = &rest.APIError{
+				Message: invalidScopeError + scope,
+				Code:    http.StatusBadRequest,
+				Type:    rest.ErrorTypeValidation,
+			}
+			return res
+		}
+	}
+
+	key, prefix, hash, err := auth.GenerateKey()
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateAPIKey",
+			"method": "GenerateKey",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+		return res
+	}
+
+	apiKey := postgrestore.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    req.Scopes,
+		CreatedBy: operator(ctx, req.Operator),
+	}
+
+	if err := s.ps.InsertAPIKey(ctx, &apiKey); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateAPIKey",
+			"method": "InsertAPIKey",
+		})
+
+		res.Result = storeError(err, apiKeyNotFoundError)
+		return res
+	}
+
+	res.Data = toAPIKeyData(apiKey)
+	res.Data.Key = key
+
+	return res
+}
+
+// FetchAPIKeys returns fetch api keys
+// swagger:operation GET /api-keys fetchAPIKeysRequest
+// ---
+// summary: Fetch API Keys
+// description: Returns every api key of the caller's tenant with its prefix, revoked keys included. Keys themselves are not returned.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchAPIKeysResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchAPIKeys(ctx context.Context, _ rest.FetchAPIKeysRequest) rest.FetchAPIKeysResponse {
+	res := rest.FetchAPIKeysResponse{}
+
+	keys, err := s.ps.FetchAPIKeys(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchAPIKeys",
+			"method": "FetchAPIKeys",
+		})
+
+		res.Result = storeError(err, apiKeyNotFoundError)
+		return res
+	}
+
+	data := make([]rest.APIKeyData, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, *toAPIKeyData(key))
+	}
+
+	res.Data = &rest.FetchAPIKeysData{
+		Keys: data,
+	}
+
+	return res
+}
+
+// RotateAPIKey returns rotate api key
+// swagger:operation POST /api-keys/{id}/rotate rotateAPIKeyRequest
+// ---
+// summary: Rotate API Key
+// description: Replaces the key of an api key that is not revoked, keeping its name and scopes. The previous key stops working right away and the new key is only returned in this response.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/rotateAPIKeyResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) RotateAPIKey(ctx context.Context, req rest.RotateAPIKeyRequest) rest.RotateAPIKeyResponse {
+	res := rest.RotateAPIKeyResponse{}
+
+	key, prefix, hash, err := auth.GenerateKey()
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RotateAPIKey",
+			"method": "GenerateKey",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+		return res
+	}
+
+	apiKey, err := s.ps.RotateAPIKey(ctx, req.ID, prefix, hash)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RotateAPIKey",
+			"method": "RotateAPIKey",
+		})
+
+		res.Result = storeError(err, apiKeyNotFoundError)
+		return res
+	}
+
+	res.Data = toAPIKeyData(*apiKey)
+	res.Data.Key = key
+
+	return res
+}
+
+// RevokeAPIKey returns revoke api key
+// swagger:operation DELETE /api-keys/{id} revokeAPIKeyRequest
+// ---
+// summary: Revoke API Key
+// description: Revokes an api key, requests with the key are rejected from then on. Revoked keys stay listed.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/revokeAPIKeyResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) RevokeAPIKey(ctx context.Context, req rest.RevokeAPIKeyRequest) rest.RevokeAPIKeyResponse {
+	res := rest.RevokeAPIKeyResponse{}
+
+	apiKey, err := s.ps.RevokeAPIKey(ctx, req.ID)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RevokeAPIKey",
+			"method": "RevokeAPIKey",
+		})
+
+		res.Result = storeError(err, apiKeyNotFoundError)
+		return res
+	}
+
+	res.Data = toAPIKeyData(*apiKey)
+
+	return res
+}
+
+// operator returns the operator given in the request, or the name of the authenticated caller if none is given
+func operator(ctx context.Context, op string) string {
+	if op != "" {
+		return op
+	}
+
+	if caller, ok := auth.CallerFrom(ctx); ok {
+		return caller.Name
+	}
+
+	return ""
+}
+
+func toAPIKeyData(key postgrestore.APIKey) *rest.APIKeyData {
+	return &rest.APIKeyData{
+		ID:         key.ID,
+		Name:       key.Name,
+		Tenant:     key.TenantID,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		RotatedAt:  key.RotatedAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	rest "notify-hub-backend"
+	"notify-hub-backend/internal/auth"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		code   int
+	}{
+		{name: "valid", scopes: []string{auth.ScopeMessagesRead, auth.ScopeContactsWrite}},
+		{name: "unknown scope", scopes: []string{auth.ScopeMessagesRead, "messages:delete"}, code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+			// the operator defaults to the caller
+			ctx := auth.WithCaller(context.Background(), auth.Caller{Name: "ops"})
+
+			res := s.CreateAPIKey(ctx, rest.CreateAPIKeyRequest{Name: "reporting", Scopes: tt.scopes})
+			if tt.code != 0 {
+				if res.Result == nil || res.Result.Code != tt.code || len(ps.apiKeys) != 0 {
+					t.Errorf("CreateAPIKey result = %+v with %d keys stored, want code %d", res.Result, len(ps.apiKeys), tt.code)
+				}
+
+				return
+			}
+
+			if res.Result != nil {
+				t.Fatalf("CreateAPIKey result = %+v", res.Result)
+			}
+
+			if !strings.HasPrefix(res.Data.Key, res.Data.Prefix) || res.Data.CreatedBy != "ops" {
+				t.Errorf("CreateAPIKey = %+v, want the key created by ops", res.Data)
+			}
+
+			// only the hash of the key is stored
+			if ps.apiKeys[0].KeyHash != auth.HashKey(res.Data.Key) || strings.Contains(ps.apiKeys[0].KeyHash, res.Data.Key) {
+				t.Errorf("stored %+v, want the hash of the key", ps.apiKeys[0])
+			}
+
+			keys := s.FetchAPIKeys(ctx, rest.FetchAPIKeysRequest{})
+			if len(keys.Data.Keys) != 1 || keys.Data.Keys[0].Key != "" {
+				t.Errorf("FetchAPIKeys = %+v, want the key listed without the key itself", keys.Data.Keys)
+			}
+		})
+	}
+}
+
+func TestRotateAndRevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	authenticator := auth.NewAPIKeyAuthenticator(ps)
+
+	created := s.CreateAPIKey(ctx, rest.CreateAPIKeyRequest{Name: "reporting", Scopes: []string{auth.ScopeMessagesRead}})
+	if created.Result != nil {
+		t.Fatalf("CreateAPIKey result = %+v", created.Result)
+	}
+
+	rotated := s.RotateAPIKey(ctx, rest.RotateAPIKeyRequest{ID: created.Data.ID})
+	if rotated.Result != nil || rotated.Data.Key == created.Data.Key || rotated.Data.RotatedAt == nil {
+		t.Fatalf("RotateAPIKey = %+v, %+v, want a new key", rotated.Data, rotated.Result)
+	}
+
+	if _, err := authenticator.Authenticate(ctx, created.Data.Key); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Authenticate with the rotated key error = %v, want %v", err, auth.ErrUnauthenticated)
+	}
+
+	if caller, err := authenticator.Authenticate(ctx, rotated.Data.Key); err != nil || caller.Name != "reporting" {
+		t.Errorf("Authenticate with the new key = %+v, %v, want reporting", caller, err)
+	}
+
+	revoked := s.RevokeAPIKey(ctx, rest.RevokeAPIKeyRequest{ID: created.Data.ID})
+	if revoked.Result != nil || revoked.Data.RevokedAt == nil {
+		t.Fatalf("RevokeAPIKey = %+v, %+v, want the key revoked", revoked.Data, revoked.Result)
+	}
+
+	if _, err := authenticator.Authenticate(ctx, rotated.Data.Key); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Authenticate with the revoked key error = %v, want %v", err, auth.ErrUnauthenticated)
+	}
+
+	// a revoked key can not be rotated back to life
+	if res := s.RotateAPIKey(ctx, rest.RotateAPIKeyRequest{ID: created.Data.ID}); res.Result == nil || res.Result.Code != http.StatusNotFound {
+		t.Errorf("RotateAPIKey of a revoked key result = %+v, want code %d", res.Result, http.StatusNotFound)
+	}
+
+	if res := s.RevokeAPIKey(ctx, rest.RevokeAPIKeyRequest{ID: 42}); res.Result == nil || res.Result.Code != http.StatusNotFound {
+		t.Errorf("RevokeAPIKey of an unknown key result = %+v, want code %d", res.Result, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func TestSwitchAutoSend(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(newFakePostgres(), newFakeRedis(), &fakeHook{})
+
+	off := s.SwitchAutoSend(ctx, rest.SwitchAutoSendRequest{Operator: "ayse", Reason: "provider outage"})
+	if off.Result != nil {
+		t.Fatalf("SwitchAutoSend result = %+v", off.Result)
+	}
+
+	if off.Data.AutoSendOn || off.Data.ChangedBy != "ayse" || off.Data.Reason != "provider outage" {
+		t.Errorf("first switch = %+v, want auto send off by ayse", off.Data)
+	}
+
+	if on := s.SwitchAutoSend(ctx, rest.SwitchAutoSendRequest{Operator: "mehmet", Reason: "resolved"}); !on.Data.AutoSendOn {
+		t.Errorf("second switch = %+v, want auto send on", on.Data)
+	}
+
+	history := s.FetchAutoSendHistory(ctx, rest.FetchAutoSendHistoryRequest{})
+	if history.Result != nil {
+		t.Fatalf("FetchAutoSendHistory result = %+v", history.Result)
+	}
+
+	if len(history.Data.Changes) != 2 || history.Data.Changes[0].ChangedBy != "mehmet" || history.Data.Changes[1].ChangedBy != "ayse" {
+		t.Errorf("history = %+v, want both switches newest first", history.Data.Changes)
+	}
+}
+
+func TestCronSendMessageAutoSendOff(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: postgrestore.MessageStatusQueued}
+	hc := &fakeHook{}
+	s := newTestService(ps, newFakeRedis(), hc)
+
+	// the switch is read from postgres by every run
+	_, _ = ps.ToggleSendingState(ctx, postgrestore.SendingScopeGlobal, "ayse", "maintenance")
+
+	if _, err := s.CronSendMessage(ctx); err != nil {
+		t.Fatalf("CronSendMessage error = %v", err)
+	}
+
+	if ps.claims != 0 || len(hc.sent) != 0 {
+		t.Errorf("claimed %d times and sent %d chunks while auto send is off, want none", ps.claims, len(hc.sent))
+	}
+
+	_, _ = ps.ToggleSendingState(ctx, postgrestore.SendingScopeGlobal, "ayse", "done")
+
+	if _, err := s.CronSendMessage(ctx); err != nil {
+		t.Fatalf("CronSendMessage error = %v", err)
+	}
+
+	if ps.messages[1].Status != postgrestore.MessageStatusSent {
+		t.Errorf("status = %q, want %q once auto send is on", ps.messages[1].Status, postgrestore.MessageStatusSent)
+	}
+}
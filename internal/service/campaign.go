@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// campaign states derived from campaign status and progress
+const (
+	campaignStateScheduled = "scheduled"
+	campaignStateCompleted = "completed"
+)
+
+const campaignNotFoundError = "campaign not found"
+
+// CreateCampaign returns create campaign
+// swagger:operation POST /campaigns createCampaignRequest
+// ---
+// summary: Create Campaign
+// description: Creates a broadcast campaign and queues a message per audience recipient, sending starts at startAt and is limited by throttlePerMinute. Queued messages count against the daily send quota of the caller's tenant.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/createCampaignResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) CreateCampaign(ctx context.Context, req rest.CreateCampaignRequest) rest.CreateCampaignResponse {
+	res := rest.CreateCampaignResponse{}
+
+	messages, apiErr := s.buildMessages(ctx, req.Content, req.Recipients, req.GroupID, req.Template)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	startAt := req.StartAt
+	if startAt.IsZero() {
+		startAt = time.Now()
+	}
+
+	campaign := postgrestore.Campaign{
+		Name:              req.Name,
+		Content:           req.Content,
+		AudienceSize:      len(messages),
+		Status:            postgrestore.CampaignStatusActive,
+		StartAt:           startAt,
+		ThrottlePerMinute: req.ThrottlePerMinute,
+	}
+
+	if req.GroupID != 0 {
+		campaign.GroupID = &req.GroupID
+	}
+
+	if apiErr := s.reserveQuota(ctx, len(messages)); apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	if err := s.ps.InsertCampaign(ctx, &campaign, messages); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateCampaign",
+			"method": "InsertCampaign",
+		})
+
+		s.limits.Release(ctx, len(messages))
+
+		res.Result = storeError(err, campaignNotFoundError)
+		return res
+	}
+
+	res.Data, res.Result = s.campaignData(ctx, campaign)
+
+	return res
+}
+
+// FetchCampaign returns fetch campaign
+// swagger:operation GET /campaigns/{id} fetchCampaignRequest
+// ---
+// summary: Fetch Campaign
+// description: Returns a campaign with its progress
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchCampaignResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchCampaign(ctx context.Context, req rest.FetchCampaignRequest) rest.FetchCampaignResponse {
+	res := rest.FetchCampaignResponse{}
+
+	campaign, err := s.ps.FetchCampaign(ctx, req.ID)
+	if err != nil || campaign == nil {
+		res.Result = storeError(err, campaignNotFoundError)
+		return res
+	}
+
+	res.Data, res.Result = s.campaignData(ctx, *campaign)
+
+	return res
+}
+
+// PauseCampaign returns pause campaign
+// swagger:operation POST /campaigns/{id}/pause pauseCampaignRequest
+// ---
+// summary: Pause Campaign
+// description: Pauses an active campaign, its queued messages are not sent until it is resumed
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/pauseCampaignResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) PauseCampaign(ctx context.Context, req rest.PauseCampaignRequest) rest.PauseCampaignResponse {
+	data, _, apiErr := s.updateCampaignStatus(ctx, req.ID, postgrestore.CampaignStatusPaused, postgrestore.CampaignStatusActive)
+
+	return rest.PauseCampaignResponse{
+		Data:   data,
+		Result: apiErr,
+	}
+}
+
+// ResumeCampaign returns resume campaign
+// swagger:operation POST /campaigns/{id}/resume resumeCampaignRequest
+// ---
+// summary: Resume Campaign
+// description: Resumes a paused campaign
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/resumeCampaignResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) ResumeCampaign(ctx context.Context, req rest.ResumeCampaignRequest) rest.ResumeCampaignResponse {
+	data, _, apiErr := s.updateCampaignStatus(ctx, req.ID, postgrestore.CampaignStatusActive, postgrestore.CampaignStatusPaused)
+
+	return rest.ResumeCampaignResponse{
+		Data:   data,
+		Result: apiErr,
+	}
+}
+
+// AbortCampaign returns abort campaign
+// swagger:operation POST /campaigns/{id}/abort abortCampaignRequest
+// ---
+// summary: Abort Campaign
+// description: Aborts an active or paused campaign, its queued messages are cancelled and their number is returned, messages being sent stop before their next chunk
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/abortCampaignResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) AbortCampaign(ctx context.Context, req rest.AbortCampaignRequest) rest.AbortCampaignResponse {
+	data, cancelled, apiErr := s.updateCampaignStatus(ctx, req.ID, postgrestore.CampaignStatusAborted, postgrestore.CampaignStatusActive, postgrestore.CampaignStatusPaused)
+	if apiErr != nil {
+		return rest.AbortCampaignResponse{Result: apiErr}
+	}
+
+	return rest.AbortCampaignResponse{
+		Data: &rest.AbortCampaignData{
+			CampaignData:      *data,
+			CancelledMessages: cancelled,
+		},
+	}
+}
+
+// updateCampaignStatus moves campaign to status if its current status is one of from, it returns the number of
+// messages cancelled by the move
+func (s *RestService) updateCampaignStatus(ctx context.Context, id int64, status string, from ...string) (*rest.CampaignData, int, *rest.APIError) {
+	campaign, err := s.ps.FetchCampaign(ctx, id)
+	if err != nil || campaign == nil {
+		return nil, 0, storeError(err, campaignNotFoundError)
+	}
+
+	if !slices.Contains(from, campaign.Status) {
+		return nil, 0, &rest.APIError{
+			Message: fmt.Sprintf("campaign is %s, it can not be moved to %s", campaign.Status, status),
+			Code:    http.StatusConflict,
+			Type:    rest.ErrorTypeConflict,
+		}
+	}
+
+	cancelled, err := s.ps.UpdateCampaignStatus(ctx, id, status, from)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "UpdateCampaignStatus",
+			"method": "UpdateCampaignStatus",
+		})
+
+		return nil, 0, storeError(err, campaignNotFoundError)
+	}
+
+	campaign.Status = status
+
+	data, apiErr := s.campaignData(ctx, *campaign)
+
+	return data, cancelled, apiErr
+}
+
+func (s *RestService) campaignData(ctx context.Context, campaign postgrestore.Campaign) (*rest.CampaignData, *rest.APIError) {
+	progress, err := s.ps.FetchCampaignProgress(ctx, campaign.ID)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CampaignData",
+			"method": "FetchCampaignProgress",
+		})
+
+		return nil, storeError(err, campaignNotFoundError)
+	}
+
+	data := &rest.CampaignData{
+		ID:                campaign.ID,
+		Name:              campaign.Name,
+		Content:           campaign.Content,
+		Status:            campaignState(campaign, progress),
+		GroupID:           campaign.GroupID,
+		AudienceSize:      campaign.AudienceSize,
+		StartAt:           campaign.StartAt,
+		ThrottlePerMinute: campaign.ThrottlePerMinute,
+		Progress: rest.CampaignProgressData{
+			Queued:     progress[postgrestore.MessageStatusQueued] + progress[postgrestore.MessageStatusSending],
+			Sent:       progress[postgrestore.MessageStatusSent],
+			Failed:     progress[postgrestore.MessageStatusFailed],
+			Delivered:  progress[postgrestore.MessageStatusDelivered],
+			Suppressed: progress[postgrestore.MessageStatusSuppressed],
+			Cancelled:  progress[postgrestore.MessageStatusCancelled],
+		},
+		CreatedAt: campaign.CreatedAt,
+	}
+
+	for _, count := range progress {
+		data.Progress.Total += count
+	}
+
+	return data, nil
+}
+
+// campaignState returns status of campaign, active campaigns are reported as scheduled before
+// their start time and as completed when they have no queued messages left
+func campaignState(campaign postgrestore.Campaign, progress postgrestore.CampaignProgress) string {
+	if campaign.Status != postgrestore.CampaignStatusActive {
+		return campaign.Status
+	}
+
+	if campaign.StartAt.After(time.Now()) {
+		return campaignStateScheduled
+	}
+
+	if progress[postgrestore.MessageStatusQueued]+progress[postgrestore.MessageStatusSending] == 0 {
+		return campaignStateCompleted
+	}
+
+	return campaign.Status
+}
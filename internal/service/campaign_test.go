@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func newCampaignPostgres(status string) *fakePostgres {
+	campaignID := int64(1)
+
+	ps := newFakePostgres()
+	ps.campaigns[campaignID] = postgrestore.Campaign{ID: campaignID, Status: status, StartAt: time.Now().Add(-time.Minute)}
+	ps.messages[1] = postgrestore.Message{ID: 1, CampaignID: &campaignID, Status: postgrestore.MessageStatusSent}
+	ps.messages[2] = postgrestore.Message{ID: 2, CampaignID: &campaignID, Status: postgrestore.MessageStatusQueued}
+	ps.messages[3] = postgrestore.Message{ID: 3, CampaignID: &campaignID, Status: postgrestore.MessageStatusQueued}
+
+	return ps
+}
+
+func TestAbortCampaign(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		code      int
+		cancelled int
+	}{
+		{name: "active", status: postgrestore.CampaignStatusActive, cancelled: 2},
+		{name: "paused", status: postgrestore.CampaignStatusPaused, cancelled: 2},
+		{name: "aborted", status: postgrestore.CampaignStatusAborted, code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newCampaignPostgres(tt.status)
+			s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+			res := s.AbortCampaign(context.Background(), rest.AbortCampaignRequest{ID: 1})
+			if tt.code != 0 {
+				if res.Result == nil || res.Result.Code != tt.code {
+					t.Fatalf("AbortCampaign result = %+v, want code %d", res.Result, tt.code)
+				}
+
+				return
+			}
+
+			if res.Result != nil {
+				t.Fatalf("AbortCampaign result = %+v", res.Result)
+			}
+
+			if res.Data.CancelledMessages != tt.cancelled {
+				t.Errorf("cancelled messages = %d, want %d", res.Data.CancelledMessages, tt.cancelled)
+			}
+
+			if res.Data.Status != postgrestore.CampaignStatusAborted {
+				t.Errorf("status = %q, want %q", res.Data.Status, postgrestore.CampaignStatusAborted)
+			}
+
+			if res.Data.Progress.Cancelled != tt.cancelled || res.Data.Progress.Sent != 1 {
+				t.Errorf("progress = %+v, want %d cancelled and 1 sent", res.Data.Progress, tt.cancelled)
+			}
+		})
+	}
+}
+
+func TestPauseCampaignConflict(t *testing.T) {
+	s := newTestService(newCampaignPostgres(postgrestore.CampaignStatusPaused), newFakeRedis(), &fakeHook{})
+
+	res := s.PauseCampaign(context.Background(), rest.PauseCampaignRequest{ID: 1})
+	if res.Result == nil || res.Result.Code != http.StatusConflict {
+		t.Fatalf("PauseCampaign result = %+v, want code %d", res.Result, http.StatusConflict)
+	}
+
+	if res := s.PauseCampaign(context.Background(), rest.PauseCampaignRequest{ID: 2}); res.Result == nil || res.Result.Code != http.StatusNotFound {
+		t.Fatalf("PauseCampaign result = %+v, want code %d", res.Result, http.StatusNotFound)
+	}
+}
+
+func TestProcessSendingMessageStopsWhenCancelled(t *testing.T) {
+	ps := newCampaignPostgres(postgrestore.CampaignStatusActive)
+	message := ps.messages[2]
+	message.Recipient = "+905325008081"
+	message.Content = strings.Repeat("a", 250)
+	message.Status = postgrestore.MessageStatusSending
+	ps.messages[message.ID] = message
+
+	// the campaign is aborted once the first chunk is sent
+	hc := &fakeHook{}
+	hc.onSend = func() {
+		if len(hc.sent) == 1 {
+			_, _ = ps.UpdateCampaignStatus(context.Background(), 1, postgrestore.CampaignStatusAborted, []string{postgrestore.CampaignStatusActive})
+		}
+	}
+
+	s := newTestService(ps, newFakeRedis(), hc)
+	s.processSendingMessage(context.Background(), message)
+
+	if len(hc.sent) != 1 {
+		t.Errorf("sent %d chunks, want 1", len(hc.sent))
+	}
+
+	if status, ok := ps.statuses[message.ID]; ok {
+		t.Errorf("status updated to %q, want the cancelled message left as is", status)
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+const contactNotFoundError = "contact not found"
+
+// CreateContact returns create contact
+// swagger:operation POST /contacts createContactRequest
+// ---
+// summary: Create Contact
+// description: Creates a contact, attributes are available as template variables of messages
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/createContactResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) CreateContact(ctx context.Context, req rest.CreateContactRequest) rest.CreateContactResponse {
+	res := rest.CreateContactResponse{}
+
+	phone, apiErr := s.normalizeRecipient(req.Phone)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	contact := postgrestore.Contact{
+		Name:       req.Name,
+		Phone:      phone,
+		Attributes: req.Attributes,
+	}
+
+	if err := s.ps.InsertContact(ctx, &contact); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateContact",
+			"method": "InsertContact",
+		})
+
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	res.Data = toContactData(contact)
+
+	return res
+}
+
+// UpdateContact returns update contact
+// swagger:operation PUT /contacts/{id} updateContactRequest
+// ---
+// summary: Update Contact
+// description: Updates name, phone and attributes of a contact
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/updateContactResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) UpdateContact(ctx context.Context, req rest.UpdateContactRequest) rest.UpdateContactResponse {
+	res := rest.UpdateContactResponse{}
+
+	phone, apiErr := s.normalizeRecipient(req.Phone)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	contact := postgrestore.Contact{
+		ID:         req.ID,
+		Name:       req.Name,
+		Phone:      phone,
+		Attributes: req.Attributes,
+	}
+
+	if err := s.ps.UpdateContact(ctx, &contact); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "UpdateContact",
+			"method": "UpdateContact",
+		})
+
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	updated, err := s.ps.FetchContact(ctx, req.ID)
+	if err != nil || updated == nil {
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	res.Data = toContactData(*updated)
+
+	return res
+}
+
+// DeleteContact returns delete contact
+// swagger:operation DELETE /contacts/{id} deleteContactRequest
+// ---
+// summary: Delete Contact
+// description: Deletes a contact and removes it from its groups
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/deleteContactResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) DeleteContact(ctx context.Context, req rest.DeleteContactRequest) rest.DeleteContactResponse {
+	res := rest.DeleteContactResponse{}
+
+	if err := s.ps.DeleteContact(ctx, req.ID); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "DeleteContact",
+			"method": "DeleteContact",
+		})
+
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	res.Data = &rest.DeleteContactData{
+		ID: req.ID,
+	}
+
+	return res
+}
+
+// FetchContact returns fetch contact
+// swagger:operation GET /contacts/{id} fetchContactRequest
+// ---
+// summary: Fetch Contact
+// description: Returns a contact with its attributes
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchContactResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchContact(ctx context.Context, req rest.FetchContactRequest) rest.FetchContactResponse {
+	res := rest.FetchContactResponse{}
+
+	contact, err := s.ps.FetchContact(ctx, req.ID)
+	if err != nil || contact == nil {
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	res.Data = toContactData(*contact)
+
+	return res
+}
+
+// FetchContacts returns fetch contacts
+// swagger:operation GET /contacts fetchContactsRequest
+// ---
+// summary: Fetch Contacts
+// description: Returns all contacts with their attributes
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchContactsResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchContacts(ctx context.Context, _ rest.FetchContactsRequest) rest.FetchContactsResponse {
+	res := rest.FetchContactsResponse{}
+
+	contacts, err := s.ps.FetchContacts(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchContacts",
+			"method": "FetchContacts",
+		})
+
+		res.Result = storeError(err, contactNotFoundError)
+		return res
+	}
+
+	res.Data = &rest.FetchContactsData{
+		Contacts: toContactsData(contacts),
+	}
+
+	return res
+}
+
+// storeError returns api error of store error, a nil error stands for a missing record reported with notFoundMessage
+func storeError(err error, notFoundMessage string) *rest.APIError {
+	switch {
+	case err == nil || err == postgrestore.ErrNotFound:
+		return &rest.APIError{
+			Message: notFoundMessage,
+			Code:    http.StatusNotFound,
+			Type:    rest.ErrorTypeNotFound,
+		}
+	case errors.Is(err, postgrestore.ErrNotFound):
+		return &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+			Type:    rest.ErrorTypeNotFound,
+		}
+	case errors.Is(err, postgrestore.ErrAlreadyExists):
+		return &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusConflict,
+			Type:    rest.ErrorTypeConflict,
+		}
+	}
+
+	return &rest.APIError{
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+		Type:    rest.ErrorTypeInternal,
+	}
+}
+
+func toContactData(contact postgrestore.Contact) *rest.ContactData {
+	return &rest.ContactData{
+		ID:         contact.ID,
+		Name:       contact.Name,
+		Phone:      contact.Phone,
+		Attributes: contact.Attributes,
+		CreatedAt:  contact.CreatedAt,
+		UpdatedAt:  contact.UpdatedAt,
+	}
+}
+
+func toContactsData(contacts []postgrestore.Contact) []rest.ContactData {
+	data := make([]rest.ContactData, 0, len(contacts))
+	for _, contact := range contacts {
+		data = append(data, *toContactData(contact))
+	}
+
+	return data
+}
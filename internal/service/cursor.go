@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// sort options of fetch sent messages, a leading dash means descending order
+const (
+	sortByID     = "id"
+	sortBySentAt = "sentAt"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// messageCursor represents the opaque keyset cursor of message pages
+type messageCursor struct {
+	Sort string `json:"sort"`
+	postgrestore.MessageCursor
+}
+
+// parseSort returns store sort key and direction of sort option
+func parseSort(sort string) (string, bool) {
+	descending := strings.HasPrefix(sort, "-")
+
+	switch strings.TrimPrefix(sort, "-") {
+	case sortBySentAt:
+		return postgrestore.MessageSortSentAt, descending
+	default:
+		return postgrestore.MessageSortID, descending
+	}
+}
+
+func encodeCursor(sort string, message postgrestore.Message) string {
+	c := messageCursor{
+		Sort: sort,
+		MessageCursor: postgrestore.MessageCursor{
+			ID: message.ID,
+		},
+	}
+
+	if message.SentAt != nil {
+		c.SentAt = *message.SentAt
+	}
+
+	b, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns keyset position of cursor, cursors are only valid with the sort option they were created for
+func decodeCursor(cursor, sort string) (*postgrestore.MessageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var c messageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errInvalidCursor
+	}
+
+	if c.Sort != sort {
+		return nil, errors.New("cursor does not match sort")
+	}
+
+	return &c.MessageCursor, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	sentAt := time.Date(2024, 5, 17, 9, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name    string
+		sort    string
+		message postgrestore.Message
+		want    postgrestore.MessageCursor
+	}{
+		{
+			name:    "by id",
+			sort:    "id",
+			message: postgrestore.Message{ID: 42},
+			want:    postgrestore.MessageCursor{ID: 42},
+		},
+		{
+			name:    "by sending time descending",
+			sort:    "-sentAt",
+			message: postgrestore.Message{ID: 7, SentAt: &sentAt},
+			want:    postgrestore.MessageCursor{ID: 7, SentAt: sentAt},
+		},
+		{
+			name:    "unsent message by sending time",
+			sort:    "sentAt",
+			message: postgrestore.Message{ID: 3},
+			want:    postgrestore.MessageCursor{ID: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(encodeCursor(tt.sort, tt.message), tt.sort)
+			if err != nil {
+				t.Fatalf("decodeCursor error = %v", err)
+			}
+
+			if got.ID != tt.want.ID || !got.SentAt.Equal(tt.want.SentAt) {
+				t.Errorf("decodeCursor = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+		err    error
+	}{
+		{name: "not base64", cursor: "not a cursor!", sort: "id", err: errInvalidCursor},
+		{name: "not json", cursor: base64.RawURLEncoding.EncodeToString([]byte("42")), sort: "id", err: errInvalidCursor},
+		{name: "other sort", cursor: encodeCursor("id", postgrestore.Message{ID: 42}), sort: "-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.cursor, tt.sort)
+			if err == nil {
+				t.Fatalf("decodeCursor = %+v, want an error", *got)
+			}
+
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("decodeCursor error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort       string
+		key        string
+		descending bool
+	}{
+		{sort: "", key: postgrestore.MessageSortID},
+		{sort: "id", key: postgrestore.MessageSortID},
+		{sort: "-id", key: postgrestore.MessageSortID, descending: true},
+		{sort: "sentAt", key: postgrestore.MessageSortSentAt},
+		{sort: "-sentAt", key: postgrestore.MessageSortSentAt, descending: true},
+	}
+
+	for _, tt := range tests {
+		key, descending := parseSort(tt.sort)
+		if key != tt.key || descending != tt.descending {
+			t.Errorf("parseSort(%q) = %q, %v, want %q, %v", tt.sort, key, descending, tt.key, tt.descending)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	"notify-hub-backend/internal/tenant"
+)
+
+const messageNotFoundError = "message not found"
+
+// FetchMessage returns fetch message
+// swagger:operation GET /messages/{id} fetchMessageRequest
+// ---
+// summary: Fetch Message
+// description: Returns a message with its send attempts, chunks accepted by the provider and delivery receipts
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchMessageResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchMessage(ctx context.Context, req rest.FetchMessageRequest) rest.FetchMessageResponse {
+	res := rest.FetchMessageResponse{}
+
+	message, err := s.ps.FetchMessage(ctx, req.ID)
+	if err != nil || message == nil {
+		res.Result = storeError(err, messageNotFoundError)
+		return res
+	}
+
+	attempts, err := s.ps.FetchMessageAttempts(ctx, message.ID)
+	if err != nil {
+		res.Result = s.fetchMessageError(err, "FetchMessageAttempts")
+		return res
+	}
+
+	deliveries, err := s.ps.FetchMessageDeliveries(ctx, message.ID)
+	if err != nil {
+		res.Result = s.fetchMessageError(err, "FetchMessageDeliveries")
+		return res
+	}
+
+	receipts, err := s.ps.FetchDeliveryReceipts(ctx, message.ID)
+	if err != nil {
+		res.Result = s.fetchMessageError(err, "FetchDeliveryReceipts")
+		return res
+	}
+
+	data := &rest.FetchMessageData{
+		ID:         message.ID,
+		Recipient:  message.Recipient,
+		Content:    message.Content,
+		Status:     message.Status,
+		GroupID:    message.GroupID,
+		CampaignID: message.CampaignID,
+		SentAt:     message.SentAt,
+		Attempts:   make([]rest.MessageAttemptData, 0, len(attempts)),
+		Chunks:     make([]rest.MessageChunkData, 0, len(deliveries)),
+		Receipts:   make([]rest.DeliveryReceiptData, 0, len(receipts)),
+	}
+
+	for _, attempt := range attempts {
+		data.Attempts = append(data.Attempts, rest.MessageAttemptData{
+			StartedAt:  attempt.StartedAt,
+			FinishedAt: attempt.FinishedAt,
+			ChunksSent: attempt.ChunksSent,
+			Error:      attempt.Error,
+		})
+	}
+
+	for _, delivery := range deliveries {
+		data.Chunks = append(data.Chunks, rest.MessageChunkData{
+			Index:       delivery.ChunkIndex,
+			MessageId:   delivery.ProviderMessageID,
+			SendingTime: delivery.SentAt,
+			Content:     delivery.Content,
+		})
+	}
+
+	for _, receipt := range receipts {
+		data.Receipts = append(data.Receipts, rest.DeliveryReceiptData{
+			MessageId:  receipt.ProviderMessageID,
+			Status:     receipt.Status,
+			Error:      receipt.Error,
+			ReceivedAt: receipt.ReceivedAt,
+		})
+	}
+
+	res.Data = data
+
+	return res
+}
+
+// ReceiveDeliveryReceipt returns receive delivery receipt
+// swagger:operation POST /delivery-receipts receiveDeliveryReceiptRequest
+// ---
+// summary: Receive Delivery Receipt
+// description: Records a provider delivery report of a chunk, a message is marked as delivered once each of its chunks is delivered
+// security: []
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/receiveDeliveryReceiptResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) ReceiveDeliveryReceipt(ctx context.Context, req rest.ReceiveDeliveryReceiptRequest) rest.ReceiveDeliveryReceiptResponse {
+	res := rest.ReceiveDeliveryReceiptResponse{}
+
+	if !s.validInboundAuthKey(req.AuthKey) {
+		res.Result = &rest.APIError{
+			Message: invalidInboundAuthKeyError,
+			Code:    http.StatusUnauthorized,
+			Type:    rest.ErrorTypeUnauthorized,
+		}
+
+		return res
+	}
+
+	receivedAt := req.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	receipt := postgrestore.DeliveryReceipt{
+		ProviderMessageID: req.MessageID,
+		Status:            req.Status,
+		Error:             req.Error,
+		ReceivedAt:        receivedAt,
+	}
+
+	// receipts of the provider carry no tenant, their chunk is looked up across tenants
+	if err := s.ps.RecordDeliveryReceipt(tenant.HubWide(ctx), &receipt); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ReceiveDeliveryReceipt",
+			"method": "RecordDeliveryReceipt",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	res.Data = &rest.ReceiveDeliveryReceiptData{
+		ID:     receipt.MessageID,
+		Status: receipt.Status,
+	}
+
+	return res
+}
+
+func (s *RestService) fetchMessageError(err error, method string) *rest.APIError {
+	s.log(err, map[string]interface{}{
+		"action": "FetchMessage",
+		"method": method,
+	})
+
+	return &rest.APIError{
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+		Type:    rest.ErrorTypeInternal,
+	}
+}
+
+// recordAttempt stores a send attempt of a message, failures are only logged since the attempt history is informational
+func (s *RestService) recordAttempt(ctx context.Context, attempt postgrestore.MessageAttempt) {
+	attempt.FinishedAt = time.Now()
+
+	if err := s.ps.InsertMessageAttempt(ctx, &attempt); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CronSendMessage",
+			"method": "InsertMessageAttempt",
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+)
+
+func TestProcessSendingMessageRecordsDelivery(t *testing.T) {
+	tests := []struct {
+		name       string
+		hookErr    error
+		status     string
+		chunks     int
+		attemptErr string
+	}{
+		{name: "sent", status: postgrestore.MessageStatusSent, chunks: 3},
+		{name: "failed", hookErr: errors.New("provider is down"), chunks: 0, attemptErr: "provider is down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: strings.Repeat("a", 250), Status: postgrestore.MessageStatusSending}
+			s := newTestService(ps, newFakeRedis(), &fakeHook{err: tt.hookErr})
+			s.maxAttempts = 3
+
+			s.processSendingMessage(context.Background(), ps.messages[1])
+
+			if ps.statuses[1] != tt.status {
+				t.Errorf("status = %q, want %q", ps.statuses[1], tt.status)
+			}
+
+			if len(ps.deliveries) != tt.chunks {
+				t.Errorf("stored %d chunks, want %d", len(ps.deliveries), tt.chunks)
+			}
+
+			for i, delivery := range ps.deliveries {
+				if delivery.ChunkIndex != i || delivery.ProviderMessageID == "" {
+					t.Errorf("chunk %d = %+v", i, delivery)
+				}
+			}
+
+			if failures := ps.messages[1].Attempts; (tt.hookErr != nil) != (failures == 1) {
+				t.Errorf("recorded %d failures, want one only when the send fails", failures)
+			}
+
+			if len(ps.attempts) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(ps.attempts))
+			}
+
+			if ps.attempts[0].ChunksSent != tt.chunks || ps.attempts[0].Error != tt.attemptErr {
+				t.Errorf("attempt = %+v, want %d chunks and error %q", ps.attempts[0], tt.chunks, tt.attemptErr)
+			}
+		})
+	}
+}
+
+func TestFetchMessage(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: postgrestore.MessageStatusSending}
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.inbound = newTestInbound()
+
+	s.processSendingMessage(ctx, ps.messages[1])
+
+	res := s.ReceiveDeliveryReceipt(ctx, rest.ReceiveDeliveryReceiptRequest{AuthKey: "secret", MessageID: "provider-1", Status: postgrestore.ReceiptStatusDelivered})
+	if res.Result != nil {
+		t.Fatalf("ReceiveDeliveryReceipt result = %+v", res.Result)
+	}
+
+	if res.Data.ID == nil || *res.Data.ID != 1 {
+		t.Errorf("receipt message id = %v, want 1", res.Data.ID)
+	}
+
+	got := s.FetchMessage(ctx, rest.FetchMessageRequest{ID: 1})
+	if got.Result != nil {
+		t.Fatalf("FetchMessage result = %+v", got.Result)
+	}
+
+	if len(got.Data.Attempts) != 1 || len(got.Data.Chunks) != 1 || len(got.Data.Receipts) != 1 {
+		t.Errorf("got %d attempts, %d chunks and %d receipts, want one of each", len(got.Data.Attempts), len(got.Data.Chunks), len(got.Data.Receipts))
+	}
+
+	if got.Data.Chunks[0].MessageId != "provider-1" {
+		t.Errorf("chunk message id = %q, want provider-1", got.Data.Chunks[0].MessageId)
+	}
+
+	if missing := s.FetchMessage(ctx, rest.FetchMessageRequest{ID: 2}); missing.Result == nil || missing.Result.Code != http.StatusNotFound {
+		t.Errorf("FetchMessage of a missing message result = %+v, want code %d", missing.Result, http.StatusNotFound)
+	}
+}
+
+func TestReceiveDeliveryReceiptUnauthorized(t *testing.T) {
+	ps := newFakePostgres()
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.inbound = newTestInbound()
+
+	res := s.ReceiveDeliveryReceipt(context.Background(), rest.ReceiveDeliveryReceiptRequest{AuthKey: "other", MessageID: "provider-1", Status: postgrestore.ReceiptStatusDelivered})
+	if res.Result == nil || res.Result.Code != http.StatusUnauthorized {
+		t.Fatalf("ReceiveDeliveryReceipt result = %+v, want code %d", res.Result, http.StatusUnauthorized)
+	}
+
+	if len(ps.receipts) != 0 {
+		t.Errorf("recorded %d receipts, want none", len(ps.receipts))
+	}
+}
+
+func TestSentMessageContents(t *testing.T) {
+	sentAt := time.Now()
+	ps := newFakePostgres()
+	ps.deliveries = []postgrestore.MessageDelivery{
+		{MessageID: 2, ChunkIndex: 0, ProviderMessageID: "provider-2", Content: "stored", SentAt: sentAt},
+	}
+
+	rs := newFakeRedis()
+	_ = rs.Set(context.Background(), messageKey(1), redisstore.RedisMessage{Contents: []redisstore.RedisMessageContent{{MessageId: "provider-1", Content: "cached"}}})
+
+	s := newTestService(ps, rs, &fakeHook{})
+	messages := []postgrestore.Message{{ID: 1, SentAt: &sentAt}, {ID: 2, SentAt: &sentAt}, {ID: 3}}
+
+	for i := 0; i < 2; i++ {
+		contents, err := s.sentMessageContents(context.Background(), messages)
+		if err != nil {
+			t.Fatalf("sentMessageContents error = %v", err)
+		}
+
+		if len(contents[1]) != 1 || contents[1][0].Content != "cached" {
+			t.Errorf("contents of the cached message = %+v", contents[1])
+		}
+
+		if len(contents[2]) != 1 || contents[2][0].Content != "stored" {
+			t.Errorf("contents of the stored message = %+v", contents[2])
+		}
+
+		if len(contents[3]) != 0 {
+			t.Errorf("contents of the unsent message = %+v, want none", contents[3])
+		}
+	}
+
+	// the chunks loaded from postgres are cached by the first read
+	if !slices.Equal(ps.deliveryReads, []int64{2}) {
+		t.Errorf("read chunks of messages %v from postgres, want [2]", ps.deliveryReads)
+	}
+}
+
+func TestSentMessageContentsRoundTrips(t *testing.T) {
+	sentAt := time.Now()
+	ps := newFakePostgres()
+	ps.deliveries = []postgrestore.MessageDelivery{
+		{MessageID: 2, ChunkIndex: 0, ProviderMessageID: "provider-2", Content: "stored", SentAt: sentAt},
+		{MessageID: 3, ChunkIndex: 0, ProviderMessageID: "provider-3", Content: "stored", SentAt: sentAt},
+	}
+
+	rs := newFakeRedis()
+	_ = rs.Set(context.Background(), messageKey(1), redisstore.RedisMessage{Contents: []redisstore.RedisMessageContent{{MessageId: "provider-1", Content: "cached"}}})
+
+	s := newTestService(ps, rs, &fakeHook{})
+	messages := []postgrestore.Message{{ID: 1, SentAt: &sentAt}, {ID: 2, SentAt: &sentAt}, {ID: 3, SentAt: &sentAt}}
+
+	if _, err := s.sentMessageContents(context.Background(), messages); err != nil {
+		t.Fatalf("sentMessageContents error = %v", err)
+	}
+
+	// the page is read with one redis read and its missing messages are cached with one write
+	if rs.mgets != 1 || rs.msets != 1 {
+		t.Errorf("made %d redis reads and %d writes, want 1 of each", rs.mgets, rs.msets)
+	}
+
+	if !slices.Equal(ps.deliveryReads, []int64{2, 3}) {
+		t.Errorf("read chunks of messages %v from postgres, want [2 3]", ps.deliveryReads)
+	}
+}
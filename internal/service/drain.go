@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"notify-hub-backend/internal/tenant"
+)
+
+// DrainReleaseTimeout limits waiting for cancelled sends and releasing the claims of their messages once the drain
+// timeout is over
+const DrainReleaseTimeout = 5 * time.Second
+
+// inflight tracks sending work of the instance and the messages it claimed until their sends finish
+type inflight struct {
+	mu       sync.Mutex
+	draining bool
+	wg       sync.WaitGroup
+	ids      map[int64]struct{}
+	aborted  context.Context
+	abort    context.CancelFunc
+}
+
+func newInflight() *inflight {
+	aborted, abort := context.WithCancel(context.Background())
+
+	return &inflight{
+		ids:     make(map[int64]struct{}),
+		aborted: aborted,
+		abort:   abort,
+	}
+}
+
+// sendContext returns a context of parent for provider calls, it is not cancelled with parent but once the drain
+// aborts the sends in progress
+func (f *inflight) sendContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(f.aborted, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// start registers sending work, it returns false once the service is draining and no new work may be claimed
+func (f *inflight) start() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.draining {
+		return false
+	}
+
+	f.wg.Add(1)
+
+	return true
+}
+
+// done unregisters sending work registered by start
+func (f *inflight) done() {
+	f.wg.Done()
+}
+
+func (f *inflight) add(id int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ids[id] = struct{}{}
+}
+
+func (f *inflight) remove(id int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.ids, id)
+}
+
+func (f *inflight) isDraining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.draining
+}
+
+// drain stops new work and returns a channel closed once the registered work is done
+func (f *inflight) drain() <-chan struct{} {
+	f.mu.Lock()
+	f.draining = true
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	return done
+}
+
+func (f *inflight) claimed() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]int64, 0, len(f.ids))
+	for id := range f.ids {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Drain stops claiming messages and waits for the sends in progress until ctx is done. Sends that did not finish by
+// then are cancelled and waited for, and their messages are queued again so that another instance sends them without
+// waiting for their lease to expire, chunks already accepted by the provider are not sent again. Messages are released
+// only once nothing sends them anymore, a send that does not stop in time keeps its claim until its lease expires.
+func (s *RestService) Drain(ctx context.Context) error {
+	done := s.inflight.drain()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	interrupted := s.inflight.claimed()
+	s.inflight.abort()
+
+	// ctx is already done, waiting for the cancelled sends and releasing get their own deadlines
+	waitCtx, cancelWait := context.WithTimeout(context.WithoutCancel(ctx), DrainReleaseTimeout)
+	defer cancelWait()
+
+	select {
+	case <-done:
+	case <-waitCtx.Done():
+	}
+
+	running := make(map[int64]bool)
+	for _, id := range s.inflight.claimed() {
+		running[id] = true
+	}
+
+	ids := make([]int64, 0, len(interrupted))
+	for _, id := range interrupted {
+		if !running[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > 0 {
+		releaseCtx, cancel := context.WithTimeout(tenant.HubWide(context.WithoutCancel(ctx)), DrainReleaseTimeout)
+		defer cancel()
+
+		if err := s.ps.ReleaseMessages(releaseCtx, ids); err != nil {
+			s.log(err, map[string]interface{}{
+				"action": "Drain",
+				"method": "ReleaseMessages",
+			})
+
+			return err
+		}
+	}
+
+	if len(running) > 0 {
+		return fmt.Errorf("drain timed out, claims of %d interrupted messages are released and %d messages are still sending", len(ids), len(running))
+	}
+
+	return fmt.Errorf("drain timed out, claims of %d interrupted messages are released", len(ids))
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hookclient "notify-hub-backend/internal/client/hook"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// blockingHook blocks sends until their context is cancelled
+type blockingHook struct {
+	started chan struct{}
+}
+
+func (c *blockingHook) SendMessage(ctx context.Context, _ hookclient.Message) (*hookclient.Response, error) {
+	close(c.started)
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestDrain(t *testing.T) {
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: postgrestore.MessageStatusQueued}
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+	hc := &blockingHook{started: make(chan struct{})}
+	s.hc = hc
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := make(chan struct{})
+
+	go func() {
+		defer close(sent)
+
+		// cancelling the job does not cancel the send in progress
+		_, _ = s.CronSendMessage(ctx)
+	}()
+
+	<-hc.started
+	cancel()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelDrain()
+
+	if err := s.Drain(drainCtx); err == nil {
+		t.Error("Drain error = nil, want the drain timed out")
+	}
+
+	<-sent
+
+	if status := ps.messages[1].Status; status != postgrestore.MessageStatusQueued {
+		t.Errorf("status = %q, want the aborted message queued again", status)
+	}
+
+	if len(ps.attempts) != 0 {
+		t.Errorf("attempts = %+v, want the aborted send not counted", ps.attempts)
+	}
+
+	if sent, _ := s.CronSendMessage(context.Background()); sent != 0 || ps.claims != 1 {
+		t.Errorf("sent %d with %d claims after the drain, want nothing claimed", sent, ps.claims)
+	}
+}
+
+func TestDrainFinishedSends(t *testing.T) {
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: postgrestore.MessageStatusQueued}
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+	if _, err := s.CronSendMessage(context.Background()); err != nil {
+		t.Fatalf("CronSendMessage error = %v", err)
+	}
+
+	if err := s.Drain(context.Background()); err != nil {
+		t.Errorf("Drain error = %v, want nil once nothing is sending", err)
+	}
+
+	if status := ps.messages[1].Status; status != postgrestore.MessageStatusSent {
+		t.Errorf("status = %q, want %q", status, postgrestore.MessageStatusSent)
+	}
+}
@@ -0,0 +1,732 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	hookclient "notify-hub-backend/internal/client/hook"
+	"notify-hub-backend/internal/limit"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+// fakePostgres keeps the rows of a test in memory, methods the tests do not use are left to the embedded nil store
+type fakePostgres struct {
+	postgrestore.Store
+	statuses         map[int64]string
+	suppressions     map[string]postgrestore.Suppression
+	suppressionReads int
+	inbound          []postgrestore.InboundMessage
+	contacts         []postgrestore.Contact
+	contactReads     int
+	groups           map[int64]postgrestore.Group
+	messages         map[int64]postgrestore.Message
+	campaigns        map[int64]postgrestore.Campaign
+	attempts         []postgrestore.MessageAttempt
+	deliveries       []postgrestore.MessageDelivery
+	receipts         []postgrestore.DeliveryReceipt
+	deliveryReads    []int64
+	outbox           []postgrestore.OutboxEvent
+	sendingStates    map[string]postgrestore.SendingState
+	schedules        []postgrestore.JobSchedule
+	jobRuns          []postgrestore.JobRun
+	pingErr          error
+	insertErr        error
+	apiKeys          []postgrestore.APIKey
+	sendingChanges   []postgrestore.SendingStateChange
+	claims           int
+}
+
+func newFakePostgres() *fakePostgres {
+	return &fakePostgres{
+		statuses:      make(map[int64]string),
+		suppressions:  make(map[string]postgrestore.Suppression),
+		groups:        make(map[int64]postgrestore.Group),
+		messages:      make(map[int64]postgrestore.Message),
+		campaigns:     make(map[int64]postgrestore.Campaign),
+		sendingStates: make(map[string]postgrestore.SendingState),
+	}
+}
+
+func (s *fakePostgres) FetchMessage(_ context.Context, id int64) (*postgrestore.Message, error) {
+	message, ok := s.messages[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &message, nil
+}
+
+func (s *fakePostgres) InsertMessages(ctx context.Context, messages []postgrestore.Message) error {
+	if s.insertErr != nil {
+		return s.insertErr
+	}
+
+	for i := range messages {
+		messages[i].TenantID, _ = tenant.From(ctx)
+		messages[i].ID = int64(len(s.messages) + 1)
+		s.messages[messages[i].ID] = messages[i]
+	}
+
+	return nil
+}
+
+func (s *fakePostgres) Ping(context.Context) error {
+	return s.pingErr
+}
+
+func (s *fakePostgres) CountMessages(_ context.Context, status string) (int64, error) {
+	var count int64
+	for _, message := range s.messages {
+		if message.Status == status {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *fakePostgres) FetchLastSentAt(context.Context) (*time.Time, error) {
+	var last *time.Time
+	for _, message := range s.messages {
+		if message.SentAt != nil && (last == nil || message.SentAt.After(*last)) {
+			last = message.SentAt
+		}
+	}
+
+	return last, nil
+}
+
+func (s *fakePostgres) InsertAPIKey(_ context.Context, key *postgrestore.APIKey) error {
+	key.ID = int64(len(s.apiKeys) + 1)
+	key.CreatedAt = time.Now()
+	s.apiKeys = append(s.apiKeys, *key)
+
+	return nil
+}
+
+func (s *fakePostgres) FetchAPIKeys(context.Context) ([]postgrestore.APIKey, error) {
+	return s.apiKeys, nil
+}
+
+func (s *fakePostgres) FetchAPIKeyByHash(_ context.Context, hash string) (*postgrestore.APIKey, error) {
+	for _, key := range s.apiKeys {
+		if key.KeyHash == hash && key.RevokedAt == nil {
+			return &key, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *fakePostgres) RotateAPIKey(_ context.Context, id int64, prefix, hash string) (*postgrestore.APIKey, error) {
+	for i, key := range s.apiKeys {
+		if key.ID == id && key.RevokedAt == nil {
+			now := time.Now()
+			s.apiKeys[i].Prefix, s.apiKeys[i].KeyHash, s.apiKeys[i].RotatedAt = prefix, hash, &now
+
+			return &s.apiKeys[i], nil
+		}
+	}
+
+	return nil, postgrestore.ErrNotFound
+}
+
+func (s *fakePostgres) RevokeAPIKey(_ context.Context, id int64) (*postgrestore.APIKey, error) {
+	for i, key := range s.apiKeys {
+		if key.ID == id {
+			if key.RevokedAt == nil {
+				now := time.Now()
+				s.apiKeys[i].RevokedAt = &now
+			}
+
+			return &s.apiKeys[i], nil
+		}
+	}
+
+	return nil, postgrestore.ErrNotFound
+}
+
+func (s *fakePostgres) TouchAPIKey(context.Context, int64) error {
+	return nil
+}
+
+func (s *fakePostgres) ReleaseMessages(_ context.Context, ids []int64) error {
+	for _, id := range ids {
+		if message, ok := s.messages[id]; ok && message.Status == postgrestore.MessageStatusSending {
+			message.Status = postgrestore.MessageStatusQueued
+			s.messages[id] = message
+		}
+	}
+
+	return nil
+}
+
+func (s *fakePostgres) ClaimSendableMessages(_ context.Context, limit int, _ time.Duration, _ postgrestore.ClaimFilter) ([]postgrestore.Message, error) {
+	s.claims++
+
+	ids := make([]int64, 0, len(s.messages))
+	for id, message := range s.messages {
+		if message.Status == postgrestore.MessageStatusQueued {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	var messages []postgrestore.Message
+	for _, id := range ids {
+		if len(messages) == limit {
+			break
+		}
+
+		message := s.messages[id]
+		message.Status = postgrestore.MessageStatusSending
+		s.messages[id] = message
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (s *fakePostgres) ClaimMessage(_ context.Context, id int64, _ time.Duration) (*postgrestore.Message, error) {
+	message, ok := s.messages[id]
+	if !ok || message.CampaignID != nil || message.Status != postgrestore.MessageStatusQueued {
+		return nil, nil
+	}
+
+	message.Status = postgrestore.MessageStatusSending
+	s.messages[id] = message
+
+	return &message, nil
+}
+
+func (s *fakePostgres) UpdateMessageStatus(_ context.Context, id int64, status string) error {
+	if message, ok := s.messages[id]; ok {
+		if message.Status != postgrestore.MessageStatusSending {
+			return nil
+		}
+
+		message.Status = status
+		s.messages[id] = message
+	}
+
+	s.statuses[id] = status
+	return nil
+}
+
+func (s *fakePostgres) RecordMessageFailure(_ context.Context, id int64, maxAttempts int) error {
+	message, ok := s.messages[id]
+	if !ok || message.Status != postgrestore.MessageStatusSending {
+		return nil
+	}
+
+	message.Attempts++
+	message.Status = postgrestore.MessageStatusQueued
+	if message.Attempts >= maxAttempts {
+		message.Status = postgrestore.MessageStatusFailed
+	}
+
+	s.messages[id] = message
+	return nil
+}
+
+func (s *fakePostgres) InsertMessageAttempt(_ context.Context, attempt *postgrestore.MessageAttempt) error {
+	s.attempts = append(s.attempts, *attempt)
+	return nil
+}
+
+func (s *fakePostgres) MarkMessageSent(_ context.Context, id int64, deliveries []postgrestore.MessageDelivery) error {
+	if message, ok := s.messages[id]; ok {
+		if message.Status != postgrestore.MessageStatusSending {
+			return nil
+		}
+
+		message.Status = postgrestore.MessageStatusSent
+		s.messages[id] = message
+	}
+
+	payload, err := json.Marshal(deliveries)
+	if err != nil {
+		return err
+	}
+
+	s.statuses[id] = postgrestore.MessageStatusSent
+	s.deliveries = append(s.deliveries, deliveries...)
+	s.outbox = append(s.outbox, postgrestore.OutboxEvent{
+		ID:          int64(len(s.outbox) + 1),
+		Topic:       postgrestore.OutboxTopicMessageSent,
+		AggregateID: id,
+		Payload:     payload,
+	})
+
+	return nil
+}
+
+func (s *fakePostgres) FetchOutboxEvents(_ context.Context, limit int) ([]postgrestore.OutboxEvent, error) {
+	var events []postgrestore.OutboxEvent
+	for _, event := range s.outbox {
+		if event.ProcessedAt == nil && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (s *fakePostgres) MarkOutboxEventsProcessed(_ context.Context, ids []int64) error {
+	now := time.Now()
+	for i, event := range s.outbox {
+		if slices.Contains(ids, event.ID) {
+			s.outbox[i].ProcessedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (s *fakePostgres) FetchDeliveriesOfMessages(_ context.Context, messageIDs []int64) ([]postgrestore.MessageDelivery, error) {
+	s.deliveryReads = append(s.deliveryReads, messageIDs...)
+
+	var deliveries []postgrestore.MessageDelivery
+	for _, delivery := range s.deliveries {
+		if slices.Contains(messageIDs, delivery.MessageID) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (s *fakePostgres) FetchMessageAttempts(_ context.Context, messageID int64) ([]postgrestore.MessageAttempt, error) {
+	var attempts []postgrestore.MessageAttempt
+	for _, attempt := range s.attempts {
+		if attempt.MessageID == messageID {
+			attempts = append(attempts, attempt)
+		}
+	}
+
+	return attempts, nil
+}
+
+func (s *fakePostgres) FetchMessageDeliveries(_ context.Context, messageID int64) ([]postgrestore.MessageDelivery, error) {
+	var deliveries []postgrestore.MessageDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.MessageID == messageID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (s *fakePostgres) FetchDeliveryReceipts(_ context.Context, messageID int64) ([]postgrestore.DeliveryReceipt, error) {
+	var receipts []postgrestore.DeliveryReceipt
+	for _, receipt := range s.receipts {
+		if receipt.MessageID != nil && *receipt.MessageID == messageID {
+			receipts = append(receipts, receipt)
+		}
+	}
+
+	return receipts, nil
+}
+
+func (s *fakePostgres) RecordDeliveryReceipt(_ context.Context, receipt *postgrestore.DeliveryReceipt) error {
+	for _, delivery := range s.deliveries {
+		if delivery.ProviderMessageID == receipt.ProviderMessageID {
+			receipt.MessageID = &delivery.MessageID
+		}
+	}
+
+	s.receipts = append(s.receipts, *receipt)
+	return nil
+}
+
+func (s *fakePostgres) FetchCampaign(_ context.Context, id int64) (*postgrestore.Campaign, error) {
+	campaign, ok := s.campaigns[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &campaign, nil
+}
+
+func (s *fakePostgres) UpdateCampaignStatus(_ context.Context, id int64, status string, from []string) (int, error) {
+	campaign := s.campaigns[id]
+	if !slices.Contains(from, campaign.Status) {
+		return 0, postgrestore.ErrNotFound
+	}
+
+	campaign.Status = status
+	s.campaigns[id] = campaign
+
+	if status != postgrestore.CampaignStatusAborted {
+		return 0, nil
+	}
+
+	cancelled := 0
+	for messageID, message := range s.messages {
+		if message.CampaignID != nil && *message.CampaignID == id &&
+			(message.Status == postgrestore.MessageStatusQueued || message.Status == postgrestore.MessageStatusSending) {
+			message.Status = postgrestore.MessageStatusCancelled
+			s.messages[messageID] = message
+			cancelled++
+		}
+	}
+
+	return cancelled, nil
+}
+
+func (s *fakePostgres) FetchCampaignProgress(_ context.Context, id int64) (postgrestore.CampaignProgress, error) {
+	progress := make(postgrestore.CampaignProgress)
+	for _, message := range s.messages {
+		if message.CampaignID != nil && *message.CampaignID == id {
+			progress[message.Status]++
+		}
+	}
+
+	return progress, nil
+}
+
+func (s *fakePostgres) AddSuppression(_ context.Context, suppression postgrestore.Suppression) (*postgrestore.Suppression, error) {
+	s.suppressions[suppression.Recipient] = suppression
+	return &suppression, nil
+}
+
+func (s *fakePostgres) RemoveSuppression(_ context.Context, recipient string) error {
+	delete(s.suppressions, recipient)
+	return nil
+}
+
+func (s *fakePostgres) FetchSuppression(_ context.Context, recipient string) (*postgrestore.Suppression, error) {
+	s.suppressionReads++
+
+	suppression, ok := s.suppressions[recipient]
+	if !ok {
+		return nil, nil
+	}
+
+	return &suppression, nil
+}
+
+func (s *fakePostgres) FetchSuppressions(context.Context) ([]postgrestore.Suppression, error) {
+	suppressions := make([]postgrestore.Suppression, 0, len(s.suppressions))
+	for _, suppression := range s.suppressions {
+		suppressions = append(suppressions, suppression)
+	}
+
+	sort.Slice(suppressions, func(i, j int) bool { return suppressions[i].Recipient < suppressions[j].Recipient })
+
+	return suppressions, nil
+}
+
+func (s *fakePostgres) RecordInboundMessage(ctx context.Context, message *postgrestore.InboundMessage, suppression *postgrestore.Suppression, resubscribe bool) (bool, error) {
+	tenantID, ok := tenant.From(ctx)
+	if !ok {
+		return false, postgrestore.ErrNoTenant
+	}
+
+	message.TenantID = tenantID
+
+	for _, inbound := range s.inbound {
+		if message.ProviderMessageID != "" && inbound.ProviderMessageID == message.ProviderMessageID && inbound.TenantID == tenantID {
+			return false, nil
+		}
+	}
+
+	s.inbound = append(s.inbound, *message)
+
+	if suppression != nil {
+		s.suppressions[suppression.Recipient] = *suppression
+	}
+
+	if existing, ok := s.suppressions[message.Recipient]; ok && resubscribe && existing.Source == postgrestore.SuppressionSourceKeyword {
+		delete(s.suppressions, message.Recipient)
+	}
+
+	return true, nil
+}
+
+func (s *fakePostgres) FetchRecipientTenants(_ context.Context, recipient string) ([]string, error) {
+	var tenants []string
+	for _, message := range s.messages {
+		if message.Recipient == recipient && !slices.Contains(tenants, message.TenantID) {
+			tenants = append(tenants, message.TenantID)
+		}
+	}
+
+	if len(tenants) == 0 {
+		return []string{tenant.Default}, nil
+	}
+
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+func (s *fakePostgres) FetchContactsByPhones(_ context.Context, phones []string) ([]postgrestore.Contact, error) {
+	s.contactReads++
+
+	var contacts []postgrestore.Contact
+	for _, contact := range s.contacts {
+		for _, phone := range phones {
+			if contact.Phone == phone {
+				contacts = append(contacts, contact)
+				break
+			}
+		}
+	}
+
+	return contacts, nil
+}
+
+func (s *fakePostgres) FetchGroup(_ context.Context, id int64) (*postgrestore.Group, error) {
+	group, ok := s.groups[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &group, nil
+}
+
+func (s *fakePostgres) FetchSendingState(_ context.Context, scope string) (*postgrestore.SendingState, error) {
+	state, ok := s.sendingStates[scope]
+	if !ok {
+		state = postgrestore.SendingState{Scope: scope, Enabled: true}
+	}
+
+	return &state, nil
+}
+
+func (s *fakePostgres) FetchSendingStates(context.Context) ([]postgrestore.SendingState, error) {
+	states := make([]postgrestore.SendingState, 0, len(s.sendingStates))
+	for _, state := range s.sendingStates {
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (s *fakePostgres) SetSendingState(_ context.Context, state postgrestore.SendingState) (*postgrestore.SendingState, error) {
+	state.ChangedAt = time.Now()
+	s.sendingStates[state.Scope] = state
+	s.sendingChanges = append(s.sendingChanges, postgrestore.SendingStateChange{
+		ID:        int64(len(s.sendingChanges) + 1),
+		Scope:     state.Scope,
+		Enabled:   state.Enabled,
+		Reason:    state.Reason,
+		ResumeAt:  state.ResumeAt,
+		ChangedBy: state.ChangedBy,
+		ChangedAt: state.ChangedAt,
+	})
+
+	return &state, nil
+}
+
+func (s *fakePostgres) ToggleSendingState(ctx context.Context, scope, changedBy, reason string) (*postgrestore.SendingState, error) {
+	state, _ := s.FetchSendingState(ctx, scope)
+
+	return s.SetSendingState(ctx, postgrestore.SendingState{Scope: scope, Enabled: !state.Enabled, Reason: reason, ChangedBy: changedBy})
+}
+
+func (s *fakePostgres) FetchSendingStateChanges(_ context.Context, scope string, limit int) ([]postgrestore.SendingStateChange, error) {
+	var changes []postgrestore.SendingStateChange
+	for i := len(s.sendingChanges) - 1; i >= 0 && len(changes) < limit; i-- {
+		if s.sendingChanges[i].Scope == scope {
+			changes = append(changes, s.sendingChanges[i])
+		}
+	}
+
+	return changes, nil
+}
+
+func (s *fakePostgres) FetchJobSchedules(context.Context) ([]postgrestore.JobSchedule, error) {
+	return s.schedules, nil
+}
+
+func (s *fakePostgres) SetJobSchedule(_ context.Context, schedule *postgrestore.JobSchedule) error {
+	schedule.ChangedAt = time.Now()
+	s.schedules = append(s.schedules, *schedule)
+
+	return nil
+}
+
+func (s *fakePostgres) InsertJobRun(_ context.Context, run *postgrestore.JobRun) error {
+	run.ID = int64(len(s.jobRuns) + 1)
+	s.jobRuns = append(s.jobRuns, *run)
+
+	return nil
+}
+
+func (s *fakePostgres) FetchJobRuns(_ context.Context, name string, limit int) ([]postgrestore.JobRun, error) {
+	var runs []postgrestore.JobRun
+	for i := len(s.jobRuns) - 1; i >= 0 && len(runs) < limit; i-- {
+		if s.jobRuns[i].Name == name {
+			runs = append(runs, s.jobRuns[i])
+		}
+	}
+
+	return runs, nil
+}
+
+func (s *fakePostgres) DeleteJobRuns(_ context.Context, before time.Time) (int, error) {
+	var kept []postgrestore.JobRun
+	for _, run := range s.jobRuns {
+		if !run.StartedAt.Before(before) {
+			kept = append(kept, run)
+		}
+	}
+
+	deleted := len(s.jobRuns) - len(kept)
+	s.jobRuns = kept
+
+	return deleted, nil
+}
+
+// fakeRedis keeps values encoded as json like the redis store and counts the round trips of multi key reads and writes
+type fakeRedis struct {
+	redisstore.Store
+	values   map[string][]byte
+	counters map[string]int64
+	mgets    int
+	msets    int
+	pingErr  error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string][]byte), counters: make(map[string]int64)}
+}
+
+func (s *fakeRedis) Ping(context.Context) error {
+	return s.pingErr
+}
+
+func (s *fakeRedis) Set(_ context.Context, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.values[key] = b
+	return nil
+}
+
+func (s *fakeRedis) Get(_ context.Context, key string, dest interface{}) error {
+	b, ok := s.values[key]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(b, dest)
+}
+
+func (s *fakeRedis) MGet(_ context.Context, keys []string, dests []interface{}) ([]bool, error) {
+	s.mgets++
+
+	found := make([]bool, len(keys))
+	for i, key := range keys {
+		b, ok := s.values[key]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(b, dests[i]); err != nil {
+			return nil, err
+		}
+
+		found[i] = true
+	}
+
+	return found, nil
+}
+
+func (s *fakeRedis) MSet(ctx context.Context, values map[string]interface{}) error {
+	s.msets++
+
+	for key, value := range values {
+		if err := s.Set(ctx, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeRedis) Del(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(s.values, key)
+	}
+
+	return nil
+}
+
+// IncrBy keeps a counter per tenant like the store
+func (s *fakeRedis) IncrBy(ctx context.Context, key string, n int64, _ time.Duration) (int64, error) {
+	id, ok := tenant.From(ctx)
+	if !ok {
+		return 0, redisstore.ErrNoTenant
+	}
+
+	s.counters[id+":"+key] += n
+	return s.counters[id+":"+key], nil
+}
+
+// fakeQueue records the messages published to the stream and the acknowledged entries
+type fakeQueue struct {
+	redisstore.Queue
+	published []int64
+	acked     []string
+}
+
+func (q *fakeQueue) Publish(_ context.Context, messageIDs ...int64) error {
+	q.published = append(q.published, messageIDs...)
+	return nil
+}
+
+func (q *fakeQueue) Ack(_ context.Context, ids ...string) error {
+	q.acked = append(q.acked, ids...)
+	return nil
+}
+
+// fakeHook records the messages sent to the provider, err fails every send and onSend runs after each send
+type fakeHook struct {
+	sent   []hookclient.Message
+	err    error
+	onSend func()
+}
+
+func (c *fakeHook) SendMessage(_ context.Context, req hookclient.Message) (*hookclient.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	c.sent = append(c.sent, req)
+
+	if c.onSend != nil {
+		c.onSend()
+	}
+
+	return &hookclient.Response{MessageID: fmt.Sprintf("provider-%d", len(c.sent))}, nil
+}
+
+// newTestService returns a service of the fakes whose tenants are not limited
+func newTestService(ps *fakePostgres, rs *fakeRedis, hc *fakeHook) *RestService {
+	limits, _ := limit.New(log.NewNopLogger(), rs, envvars.Tenant{})
+
+	return &RestService{
+		l:        log.NewNopLogger(),
+		ps:       ps,
+		rs:       rs,
+		hc:       hc,
+		limits:   limits,
+		region:   "TR",
+		inflight: newInflight(),
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+const groupNotFoundError = "group not found"
+
+// CreateGroup returns create group
+// swagger:operation POST /groups createGroupRequest
+// ---
+// summary: Create Group
+// description: Creates a recipient group with its member contacts
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/createGroupResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) CreateGroup(ctx context.Context, req rest.CreateGroupRequest) rest.CreateGroupResponse {
+	res := rest.CreateGroupResponse{}
+
+	group := postgrestore.Group{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := s.ps.InsertGroup(ctx, &group, req.ContactIDs); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateGroup",
+			"method": "InsertGroup",
+		})
+
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	res.Data = toGroupData(group)
+
+	return res
+}
+
+// UpdateGroup returns update group
+// swagger:operation PUT /groups/{id} updateGroupRequest
+// ---
+// summary: Update Group
+// description: Updates name and description of a group and replaces its member contacts
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/updateGroupResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) UpdateGroup(ctx context.Context, req rest.UpdateGroupRequest) rest.UpdateGroupResponse {
+	res := rest.UpdateGroupResponse{}
+
+	group := postgrestore.Group{
+		ID:          req.ID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := s.ps.UpdateGroup(ctx, &group, req.ContactIDs); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "UpdateGroup",
+			"method": "UpdateGroup",
+		})
+
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	updated, err := s.ps.FetchGroup(ctx, req.ID)
+	if err != nil || updated == nil {
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	res.Data = toGroupData(*updated)
+
+	return res
+}
+
+// DeleteGroup returns delete group
+// swagger:operation DELETE /groups/{id} deleteGroupRequest
+// ---
+// summary: Delete Group
+// description: Deletes a group, its member contacts are kept
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/deleteGroupResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) DeleteGroup(ctx context.Context, req rest.DeleteGroupRequest) rest.DeleteGroupResponse {
+	res := rest.DeleteGroupResponse{}
+
+	if err := s.ps.DeleteGroup(ctx, req.ID); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "DeleteGroup",
+			"method": "DeleteGroup",
+		})
+
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	res.Data = &rest.DeleteGroupData{
+		ID: req.ID,
+	}
+
+	return res
+}
+
+// FetchGroup returns fetch group
+// swagger:operation GET /groups/{id} fetchGroupRequest
+// ---
+// summary: Fetch Group
+// description: Returns a group with its member contacts
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchGroupResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchGroup(ctx context.Context, req rest.FetchGroupRequest) rest.FetchGroupResponse {
+	res := rest.FetchGroupResponse{}
+
+	group, err := s.ps.FetchGroup(ctx, req.ID)
+	if err != nil || group == nil {
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	res.Data = toGroupData(*group)
+
+	return res
+}
+
+// FetchGroups returns fetch groups
+// swagger:operation GET /groups fetchGroupsRequest
+// ---
+// summary: Fetch Groups
+// description: Returns all groups without their member contacts
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchGroupsResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchGroups(ctx context.Context, _ rest.FetchGroupsRequest) rest.FetchGroupsResponse {
+	res := rest.FetchGroupsResponse{}
+
+	groups, err := s.ps.FetchGroups(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchGroups",
+			"method": "FetchGroups",
+		})
+
+		res.Result = storeError(err, groupNotFoundError)
+		return res
+	}
+
+	data := make([]rest.GroupData, 0, len(groups))
+	for _, group := range groups {
+		data = append(data, *toGroupData(group))
+	}
+
+	res.Data = &rest.FetchGroupsData{
+		Groups: data,
+	}
+
+	return res
+}
+
+func toGroupData(group postgrestore.Group) *rest.GroupData {
+	data := &rest.GroupData{
+		ID:          group.ID,
+		Name:        group.Name,
+		Description: group.Description,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+
+	if group.Contacts != nil {
+		data.Contacts = toContactsData(group.Contacts)
+	}
+
+	return data
+}
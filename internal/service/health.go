@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	"notify-hub-backend/internal/tenant"
+)
+
+// readiness dependencies
+const (
+	DependencyPostgres = "postgres"
+	DependencyRedis    = "redis"
+	DependencyQueue    = "queue"
+)
+
+// Livez returns liveness
+// swagger:operation GET /livez livezRequest
+// ---
+// summary: Liveness
+// description: Returns up while the process is able to serve requests, dependencies are not checked
+// security: []
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/livezResponse"
+func (s *RestService) Livez(_ context.Context, _ rest.LivezRequest) rest.LivezResponse {
+	return rest.LivezResponse{Status: rest.HealthStatusUp}
+}
+
+// Readyz returns readiness
+// swagger:operation GET /readyz readyzRequest
+// ---
+// summary: Readiness
+// description: Pings postgres and redis, and the stream queue when it is enabled, and reports the sending backlog and the last sending time. It responds with 503 if a dependency is down or the service is shutting down
+// security: []
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/readyzResponse"
+//	  503:
+//		  $ref: "#/responses/readyzResponse"
+func (s *RestService) Readyz(ctx context.Context, _ rest.ReadyzRequest) rest.ReadyzResponse {
+	ctx, cancel := context.WithTimeout(ctx, s.readiness)
+	defer cancel()
+
+	checks := map[string]func(context.Context) error{
+		DependencyPostgres: s.ps.Ping,
+		DependencyRedis:    s.rs.Ping,
+	}
+
+	if s.queue != nil {
+		checks[DependencyQueue] = func(ctx context.Context) error {
+			_, err := s.queue.Len(ctx)
+			return err
+		}
+	}
+
+	res := rest.ReadyzResponse{
+		Status:       rest.HealthStatusUp,
+		Draining:     s.inflight.isDraining(),
+		Dependencies: make(map[string]string, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range checks {
+		wg.Add(1)
+
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+
+			status := s.dependencyStatus(ctx, name, check)
+
+			mu.Lock()
+			res.Dependencies[name] = status
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	for _, status := range res.Dependencies {
+		if status != rest.DependencyOK {
+			res.Status = rest.HealthStatusDown
+		}
+	}
+
+	if res.Draining {
+		res.Status = rest.HealthStatusDown
+	}
+
+	// the backlog is informational, it does not affect readiness
+	if res.Dependencies[DependencyPostgres] == rest.DependencyOK {
+		res.Backlog, res.LastSentAt = s.sendingBacklog(ctx)
+	}
+
+	return res
+}
+
+// dependencyStatus runs the check of a dependency, the error is logged and not returned because the readiness endpoint
+// is not authenticated
+func (s *RestService) dependencyStatus(ctx context.Context, name string, check func(context.Context) error) string {
+	start := time.Now()
+
+	if err := check(ctx); err != nil {
+		s.log(err, map[string]interface{}{
+			"action":     "Readyz",
+			"dependency": name,
+			"latency":    time.Since(start).String(),
+		})
+
+		return rest.DependencyUnavailable
+	}
+
+	return rest.DependencyOK
+}
+
+// sendingBacklog counts messages waiting to be sent and reads the last sending time, values that can not be read are
+// left nil
+func (s *RestService) sendingBacklog(ctx context.Context) (rest.BacklogData, *time.Time) {
+	var backlog rest.BacklogData
+
+	// the backlog is the one of the hub, messages of every tenant are counted
+	ctx = tenant.HubWide(ctx)
+
+	if queued, err := s.ps.CountMessages(ctx, postgrestore.MessageStatusQueued); err == nil {
+		backlog.Queued = &queued
+	}
+
+	if sending, err := s.ps.CountMessages(ctx, postgrestore.MessageStatusSending); err == nil {
+		backlog.Sending = &sending
+	}
+
+	if s.queue != nil {
+		if stream, err := s.queue.Len(ctx); err == nil {
+			backlog.Stream = &stream
+		}
+	}
+
+	lastSentAt, err := s.ps.FetchLastSentAt(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "Readyz",
+			"method": "FetchLastSentAt",
+		})
+	}
+
+	return backlog, lastSentAt
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func TestReadyz(t *testing.T) {
+	sentAt := time.Date(2024, 9, 9, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		redisErr error
+		drain    bool
+		deps     map[string]string
+		code     int
+	}{
+		{
+			name: "ready",
+			deps: map[string]string{DependencyPostgres: rest.DependencyOK, DependencyRedis: rest.DependencyOK},
+			code: http.StatusOK,
+		},
+		{
+			name:     "redis down",
+			redisErr: errors.New("dial tcp 10.0.0.5:6379: connect: connection refused"),
+			deps:     map[string]string{DependencyPostgres: rest.DependencyOK, DependencyRedis: rest.DependencyUnavailable},
+			code:     http.StatusServiceUnavailable,
+		},
+		{
+			name:  "draining",
+			drain: true,
+			deps:  map[string]string{DependencyPostgres: rest.DependencyOK, DependencyRedis: rest.DependencyOK},
+			code:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			ps.messages[1] = postgrestore.Message{ID: 1, Status: postgrestore.MessageStatusQueued}
+			ps.messages[2] = postgrestore.Message{ID: 2, Status: postgrestore.MessageStatusSent, SentAt: &sentAt}
+			rs := newFakeRedis()
+			rs.pingErr = tt.redisErr
+
+			s := newTestService(ps, rs, &fakeHook{})
+			s.readiness = time.Second
+
+			if tt.drain {
+				_ = s.Drain(context.Background())
+			}
+
+			res := s.Readyz(context.Background(), rest.ReadyzRequest{})
+			if res.StatusCode() != tt.code || res.Draining != tt.drain {
+				t.Errorf("Readyz = %+v, want code %d", res, tt.code)
+			}
+
+			if len(res.Dependencies) != len(tt.deps) {
+				t.Fatalf("dependencies = %v, want %v", res.Dependencies, tt.deps)
+			}
+
+			for name, status := range tt.deps {
+				if res.Dependencies[name] != status {
+					t.Errorf("%s = %q, want %q", name, res.Dependencies[name], status)
+				}
+			}
+
+			// dependency errors are only logged
+			for _, status := range res.Dependencies {
+				if strings.Contains(status, "connection refused") {
+					t.Errorf("dependencies = %v, want no error details", res.Dependencies)
+				}
+			}
+
+			if *res.Backlog.Queued != 1 || *res.Backlog.Sending != 0 || res.LastSentAt == nil || !res.LastSentAt.Equal(sentAt) {
+				t.Errorf("backlog = %+v and last sent at %v, want 1 queued and sent at %v", res.Backlog, res.LastSentAt, sentAt)
+			}
+		})
+	}
+}
+
+func TestReadyzPostgresDown(t *testing.T) {
+	ps := newFakePostgres()
+	ps.pingErr = errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.readiness = time.Second
+
+	res := s.Readyz(context.Background(), rest.ReadyzRequest{})
+	if res.StatusCode() != http.StatusServiceUnavailable || res.Dependencies[DependencyPostgres] != rest.DependencyUnavailable {
+		t.Errorf("Readyz = %+v, want postgres unavailable", res)
+	}
+
+	// the backlog is not read from a database that is down
+	if res.Backlog.Queued != nil || res.LastSentAt != nil {
+		t.Errorf("backlog = %+v, want nothing read", res.Backlog)
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	rest "notify-hub-backend"
+	hookclient "notify-hub-backend/internal/client/hook"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	"notify-hub-backend/internal/tenant"
+)
+
+const (
+	FetchInboundMessagesLimit = 100
+)
+
+// inbound message actions
+const (
+	inboundActionNone         = "none"
+	inboundActionSuppressed   = "suppressed"
+	inboundActionResubscribed = "resubscribed"
+	inboundActionHelp         = "help"
+)
+
+const invalidInboundAuthKeyError = "invalid inbound webhook auth key"
+
+// ReceiveInboundMessage returns receive inbound message
+// swagger:operation POST /inbound-messages receiveInboundMessageRequest
+// ---
+// summary: Receive Inbound Message
+// description: Records a recipient reply and updates suppression state for STOP/START keywords. The reply is recorded for every tenant that sent a message to the recipient, or for the default tenant, and STOP/START applies to each of them.
+// security: []
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/receiveInboundMessageResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) ReceiveInboundMessage(ctx context.Context, req rest.ReceiveInboundMessageRequest) rest.ReceiveInboundMessageResponse {
+	res := rest.ReceiveInboundMessageResponse{}
+
+	if !s.validInboundAuthKey(req.AuthKey) {
+		res.Result = &rest.APIError{
+			Message: invalidInboundAuthKeyError,
+			Code:    http.StatusUnauthorized,
+			Type:    rest.ErrorTypeUnauthorized,
+		}
+
+		return res
+	}
+
+	recipient, apiErr := s.normalizeRecipient(req.From)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	// the provider posts replies of every tenant to the number they share, a reply belongs to each tenant that messaged
+	// its sender so that STOP suppresses the sender for all of them
+	tenants, err := s.ps.FetchRecipientTenants(tenant.HubWide(ctx), recipient)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ReceiveInboundMessage",
+			"method": "FetchRecipientTenants",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	receivedAt := req.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	action, keyword := s.inboundAction(req.Content)
+
+	// a reply redelivered by the provider is recorded once per tenant and its keyword is not applied again
+	recorded := false
+	for _, tenantID := range tenants {
+		ok, err := s.recordInboundMessage(tenant.With(ctx, tenantID), postgrestore.InboundMessage{
+			Recipient:         recipient,
+			Content:           req.Content,
+			Keyword:           keyword,
+			ProviderMessageID: req.MessageID,
+			ReceivedAt:        receivedAt,
+		}, action)
+		if err != nil {
+			res.Result = &rest.APIError{
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+				Type:    rest.ErrorTypeInternal,
+			}
+
+			return res
+		}
+
+		recorded = recorded || ok
+	}
+
+	res.Data = &rest.ReceiveInboundMessageData{
+		Recipient: recipient,
+		Keyword:   keyword,
+		Action:    action,
+		Replied:   recorded && s.autoReply(ctx, recipient, action),
+	}
+
+	return res
+}
+
+// FetchInboundMessages returns fetch inbound messages
+// swagger:operation GET /inbound-messages fetchInboundMessagesRequest
+// ---
+// summary: Fetch Inbound Messages
+// description: Returns replies received from a recipient, newest first
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchInboundMessagesResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchInboundMessages(ctx context.Context, req rest.FetchInboundMessagesRequest) rest.FetchInboundMessagesResponse {
+	res := rest.FetchInboundMessagesResponse{}
+
+	recipient, apiErr := s.normalizeRecipient(req.Recipient)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = FetchInboundMessagesLimit
+	}
+
+	messages, err := s.ps.FetchInboundMessages(ctx, recipient, limit)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchInboundMessages",
+			"method": "FetchInboundMessages",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	inboundMessages := make([]rest.InboundMessage, 0, len(messages))
+	for _, message := range messages {
+		inboundMessages = append(inboundMessages, rest.InboundMessage{
+			Recipient:  message.Recipient,
+			Content:    message.Content,
+			Keyword:    message.Keyword,
+			MessageID:  message.ProviderMessageID,
+			ReceivedAt: message.ReceivedAt,
+		})
+	}
+
+	res.Data = &rest.FetchInboundMessagesData{
+		InboundMessages: inboundMessages,
+	}
+
+	return res
+}
+
+// recordInboundMessage stores a reply for the tenant of ctx and applies the action of its keyword to the suppression list
+// of the tenant in one transaction, STOP suppresses the recipient and START removes only suppressions added by a keyword so that
+// suppressions added through the api (e.g. legal blocks) are kept. It reports whether the reply was not recorded before.
+func (s *RestService) recordInboundMessage(ctx context.Context, message postgrestore.InboundMessage, action string) (bool, error) {
+	var suppression *postgrestore.Suppression
+	if action == inboundActionSuppressed {
+		suppression = &postgrestore.Suppression{
+			Recipient: message.Recipient,
+			Reason:    "received " + message.Keyword + " keyword",
+			Source:    postgrestore.SuppressionSourceKeyword,
+		}
+	}
+
+	recorded, err := s.ps.RecordInboundMessage(ctx, &message, suppression, action == inboundActionResubscribed)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ReceiveInboundMessage",
+			"method": "RecordInboundMessage",
+		})
+
+		return false, err
+	}
+
+	if recorded && (action == inboundActionSuppressed || action == inboundActionResubscribed) {
+		s.invalidateSuppression(ctx, message.Recipient)
+	}
+
+	return recorded, nil
+}
+
+// inboundAction returns the action and the matched keyword of a reply content
+func (s *RestService) inboundAction(content string) (string, string) {
+	if keyword := matchKeyword(content, s.inbound.StopKeywords); keyword != "" {
+		return inboundActionSuppressed, keyword
+	}
+
+	if keyword := matchKeyword(content, s.inbound.StartKeywords); keyword != "" {
+		return inboundActionResubscribed, keyword
+	}
+
+	if keyword := matchKeyword(content, s.inbound.HelpKeywords); keyword != "" {
+		return inboundActionHelp, keyword
+	}
+
+	return inboundActionNone, ""
+}
+
+func (s *RestService) autoReply(ctx context.Context, recipient, action string) bool {
+	if !s.inbound.AutoReplyEnabled {
+		return false
+	}
+
+	var content string
+	switch action {
+	case inboundActionSuppressed:
+		content = s.inbound.StopReply
+	case inboundActionResubscribed:
+		content = s.inbound.StartReply
+	case inboundActionHelp:
+		content = s.inbound.HelpReply
+	}
+
+	if content == "" {
+		return false
+	}
+
+	_, err := s.hc.SendMessage(ctx, hookclient.Message{
+		To:      recipient,
+		Content: content,
+	})
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ReceiveInboundMessage",
+			"method": "SendMessage",
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// validInboundAuthKey reports whether authKey is the webhook secret, webhooks are open only when no secret is set and
+// they are explicitly allowed to be
+func (s *RestService) validInboundAuthKey(authKey string) bool {
+	if s.inbound.WebhookSecret == "" {
+		return s.inbound.WebhookInsecure
+	}
+
+	return subtle.ConstantTimeCompare([]byte(authKey), []byte(s.inbound.WebhookSecret)) == 1
+}
+
+// matchKeyword returns the keyword matching the first word of content, or an empty string
+func matchKeyword(content string, keywords []string) string {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	word := strings.Trim(fields[0], ".,;:!?")
+	for _, keyword := range keywords {
+		if strings.EqualFold(word, strings.TrimSpace(keyword)) {
+			return strings.ToUpper(strings.TrimSpace(keyword))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	rest "notify-hub-backend"
+	envvars "notify-hub-backend/configs/env-vars"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func newTestInbound() envvars.Inbound {
+	return envvars.Inbound{
+		WebhookSecret:    "secret",
+		StopKeywords:     []string{"STOP", "IPTAL"},
+		StartKeywords:    []string{"START"},
+		HelpKeywords:     []string{"HELP"},
+		AutoReplyEnabled: true,
+		StopReply:        "unsubscribed",
+		StartReply:       "resubscribed",
+		HelpReply:        "help",
+	}
+}
+
+func TestMatchKeyword(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "STOP", want: "STOP"},
+		{content: "  stop please", want: "STOP"},
+		{content: "iptal.", want: "IPTAL"},
+		{content: "please stop", want: ""},
+		{content: "STOPPED", want: ""},
+		{content: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := matchKeyword(tt.content, []string{"STOP", " iptal "}); got != tt.want {
+			t.Errorf("matchKeyword(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestValidInboundAuthKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		insecure bool
+		authKey  string
+		want     bool
+	}{
+		{name: "matching secret", secret: "secret", authKey: "secret", want: true},
+		{name: "wrong secret", secret: "secret", authKey: "other", want: false},
+		{name: "missing key", secret: "secret", want: false},
+		{name: "no secret", want: false},
+		{name: "no secret and insecure", insecure: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(newFakePostgres(), newFakeRedis(), &fakeHook{})
+			s.inbound = envvars.Inbound{WebhookSecret: tt.secret, WebhookInsecure: tt.insecure}
+
+			if got := s.validInboundAuthKey(tt.authKey); got != tt.want {
+				t.Errorf("validInboundAuthKey = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiveInboundMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		existing   *postgrestore.Suppression
+		action     string
+		suppressed bool
+		reply      string
+	}{
+		{name: "stop", content: "stop", action: inboundActionSuppressed, suppressed: true, reply: "unsubscribed"},
+		{
+			name:     "start after keyword",
+			content:  "START",
+			existing: &postgrestore.Suppression{Recipient: "+905325008081", Source: postgrestore.SuppressionSourceKeyword},
+			action:   inboundActionResubscribed,
+			reply:    "resubscribed",
+		},
+		{
+			name:       "start keeps api suppression",
+			content:    "START",
+			existing:   &postgrestore.Suppression{Recipient: "+905325008081", Source: postgrestore.SuppressionSourceAPI},
+			action:     inboundActionResubscribed,
+			suppressed: true,
+			reply:      "resubscribed",
+		},
+		{name: "help", content: "help", action: inboundActionHelp, reply: "help"},
+		{name: "other", content: "thanks", action: inboundActionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ps := newFakePostgres()
+			hc := &fakeHook{}
+			s := newTestService(ps, newFakeRedis(), hc)
+			s.inbound = newTestInbound()
+
+			if tt.existing != nil {
+				ps.suppressions[tt.existing.Recipient] = *tt.existing
+			}
+
+			// reads the suppression before the reply so that a stale cache would be served afterwards
+			if _, err := s.isSuppressed(ctx, "+905325008081"); err != nil {
+				t.Fatalf("isSuppressed error = %v", err)
+			}
+
+			res := s.ReceiveInboundMessage(ctx, rest.ReceiveInboundMessageRequest{
+				AuthKey:   "secret",
+				From:      "+905325008081",
+				Content:   tt.content,
+				MessageID: "provider-inbound-1",
+			})
+			if res.Result != nil {
+				t.Fatalf("ReceiveInboundMessage result = %+v", res.Result)
+			}
+
+			if res.Data.Action != tt.action {
+				t.Errorf("action = %q, want %q", res.Data.Action, tt.action)
+			}
+
+			if suppressed, _ := s.isSuppressed(ctx, "+905325008081"); suppressed != tt.suppressed {
+				t.Errorf("isSuppressed = %v, want %v", suppressed, tt.suppressed)
+			}
+
+			if res.Data.Replied != (tt.reply != "") {
+				t.Errorf("replied = %v, want %v", res.Data.Replied, tt.reply != "")
+			}
+
+			if tt.reply != "" && (len(hc.sent) != 1 || hc.sent[0].Content != tt.reply) {
+				t.Errorf("sent replies = %+v, want %q", hc.sent, tt.reply)
+			}
+
+			if len(ps.inbound) != 1 {
+				t.Errorf("recorded %d replies, want 1", len(ps.inbound))
+			}
+		})
+	}
+}
+
+func TestReceiveInboundMessageRedelivered(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	hc := &fakeHook{}
+	s := newTestService(ps, newFakeRedis(), hc)
+	s.inbound = newTestInbound()
+
+	req := rest.ReceiveInboundMessageRequest{AuthKey: "secret", From: "+905325008081", Content: "STOP", MessageID: "provider-inbound-1"}
+	for i := 0; i < 2; i++ {
+		if res := s.ReceiveInboundMessage(ctx, req); res.Result != nil {
+			t.Fatalf("ReceiveInboundMessage result = %+v", res.Result)
+		}
+	}
+
+	if len(ps.inbound) != 1 {
+		t.Errorf("recorded %d replies, want 1", len(ps.inbound))
+	}
+
+	if len(hc.sent) != 1 {
+		t.Errorf("sent %d replies, want 1", len(hc.sent))
+	}
+}
+
+func TestReceiveInboundMessageTenants(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	hc := &fakeHook{}
+	s := newTestService(ps, newFakeRedis(), hc)
+	s.inbound = newTestInbound()
+
+	ps.messages[1] = postgrestore.Message{ID: 1, TenantID: "payments", Recipient: "+905325008081"}
+	ps.messages[2] = postgrestore.Message{ID: 2, TenantID: "growth", Recipient: "+905325008081"}
+	ps.messages[3] = postgrestore.Message{ID: 3, TenantID: "growth", Recipient: "+905325008081"}
+	ps.messages[4] = postgrestore.Message{ID: 4, TenantID: "support", Recipient: "+905325008082"}
+
+	// the provider redelivers the reply, it is recorded once for each tenant that messaged its sender
+	req := rest.ReceiveInboundMessageRequest{AuthKey: "secret", From: "+905325008081", Content: "STOP", MessageID: "provider-inbound-1"}
+	for i := 0; i < 2; i++ {
+		if res := s.ReceiveInboundMessage(ctx, req); res.Result != nil {
+			t.Fatalf("ReceiveInboundMessage result = %+v", res.Result)
+		}
+	}
+
+	var tenants []string
+	for _, inbound := range ps.inbound {
+		tenants = append(tenants, inbound.TenantID)
+	}
+
+	if !slices.Equal(tenants, []string{"growth", "payments"}) {
+		t.Errorf("recorded replies of tenants %v, want [growth payments]", tenants)
+	}
+
+	if len(hc.sent) != 1 {
+		t.Errorf("sent %d replies, want 1", len(hc.sent))
+	}
+}
+
+func TestReceiveInboundMessageUnauthorized(t *testing.T) {
+	ps := newFakePostgres()
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.inbound = newTestInbound()
+
+	res := s.ReceiveInboundMessage(context.Background(), rest.ReceiveInboundMessageRequest{AuthKey: "other", From: "+905325008081", Content: "STOP"})
+	if res.Result == nil || res.Result.Code != http.StatusUnauthorized {
+		t.Fatalf("ReceiveInboundMessage result = %+v, want %d", res.Result, http.StatusUnauthorized)
+	}
+
+	if len(ps.inbound) != 0 || len(ps.suppressions) != 0 {
+		t.Errorf("recorded %d replies and %d suppressions, want none", len(ps.inbound), len(ps.suppressions))
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+)
+
+const (
+	RelayOutboxLimit = 100
+)
+
+// RelayOutbox represents service's scheduled job that projects outbox events into redis, an event is marked as
+// processed only after its projection is written so a crash in between projects it again. It returns the number of
+// events it projected.
+func (s *RestService) RelayOutbox(ctx context.Context) (int, error) {
+	// events of every tenant are relayed, each is projected for its own tenant
+	ctx = tenant.HubWide(ctx)
+
+	events, err := s.ps.FetchOutboxEvents(ctx, RelayOutboxLimit)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RelayOutbox",
+			"method": "FetchOutboxEvents",
+		})
+
+		return 0, err
+	}
+
+	processed := make([]int64, 0, len(events))
+
+	for _, event := range events {
+		if err := s.projectOutboxEvent(ctx, event); err != nil {
+			s.log(err, map[string]interface{}{
+				"action": "RelayOutbox",
+				"method": "ProjectOutboxEvent",
+				"event":  event.ID,
+			})
+
+			continue
+		}
+
+		processed = append(processed, event.ID)
+	}
+
+	if err := s.ps.MarkOutboxEventsProcessed(ctx, processed); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RelayOutbox",
+			"method": "MarkOutboxEventsProcessed",
+		})
+
+		return 0, err
+	}
+
+	return len(processed), nil
+}
+
+// projectOutboxEvent writes the redis state of an outbox event for the tenant of the event, projections overwrite so
+// replaying an event is harmless
+func (s *RestService) projectOutboxEvent(ctx context.Context, event postgrestore.OutboxEvent) error {
+	ctx = tenant.With(ctx, event.TenantID)
+
+	switch event.Topic {
+	case postgrestore.OutboxTopicMessageSent:
+		var deliveries []postgrestore.MessageDelivery
+		if err := json.Unmarshal(event.Payload, &deliveries); err != nil {
+			return fmt.Errorf("failed to decode outbox event: %w", err)
+		}
+
+		contents := make([]redisstore.RedisMessageContent, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			contents = append(contents, redisstore.RedisMessageContent{
+				MessageId:   delivery.ProviderMessageID,
+				SendingTime: delivery.SentAt,
+				Content:     delivery.Content,
+			})
+		}
+
+		return s.rs.Set(ctx, messageKey(event.AggregateID), redisstore.RedisMessage{Contents: contents})
+	}
+
+	return nil
+}
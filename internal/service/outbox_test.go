@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+)
+
+func TestProcessSendingMessageResumes(t *testing.T) {
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: strings.Repeat("a", 250), Status: postgrestore.MessageStatusSending}
+
+	// the first chunk was accepted by the provider in a run that stopped before the message was marked as sent
+	rs := newFakeRedis()
+	_ = rs.Set(context.Background(), sendingKey(1), redisstore.RedisMessage{Contents: []redisstore.RedisMessageContent{{MessageId: "earlier-1", Content: strings.Repeat("a", 100)}}})
+
+	hc := &fakeHook{}
+	s := newTestService(ps, rs, hc)
+	s.processSendingMessage(context.Background(), ps.messages[1])
+
+	if len(hc.sent) != 2 {
+		t.Errorf("sent %d chunks, want the remaining 2", len(hc.sent))
+	}
+
+	if ps.messages[1].Status != postgrestore.MessageStatusSent {
+		t.Errorf("status = %q, want %q", ps.messages[1].Status, postgrestore.MessageStatusSent)
+	}
+
+	want := []string{"earlier-1", "provider-1", "provider-2"}
+	if len(ps.deliveries) != len(want) {
+		t.Fatalf("stored %d chunks, want %d", len(ps.deliveries), len(want))
+	}
+
+	for i, delivery := range ps.deliveries {
+		if delivery.ChunkIndex != i || delivery.ProviderMessageID != want[i] {
+			t.Errorf("chunk %d = %+v, want provider message id %q", i, delivery, want[i])
+		}
+	}
+
+	if _, ok := rs.values[sendingKey(1)]; ok {
+		t.Error("sending progress is kept after the message is marked as sent")
+	}
+}
+
+func TestProcessSendingMessageKeepsProgress(t *testing.T) {
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: strings.Repeat("a", 250), Status: postgrestore.MessageStatusSending}
+	rs := newFakeRedis()
+
+	// the provider accepts the first chunk and fails the others
+	hc := &fakeHook{}
+	hc.onSend = func() { hc.err = errors.New("provider is down") }
+
+	s := newTestService(ps, rs, hc)
+	s.maxAttempts = 3
+	s.processSendingMessage(context.Background(), ps.messages[1])
+
+	if ps.messages[1].Status != postgrestore.MessageStatusQueued {
+		t.Errorf("status = %q, want %q", ps.messages[1].Status, postgrestore.MessageStatusQueued)
+	}
+
+	if len(ps.deliveries) != 0 {
+		t.Errorf("stored %d chunks, want none before the message is sent", len(ps.deliveries))
+	}
+
+	var sending redisstore.RedisMessage
+	_ = rs.Get(context.Background(), sendingKey(1), &sending)
+	if len(sending.Contents) != 1 || sending.Contents[0].MessageId != "provider-1" {
+		t.Errorf("sending progress = %+v, want the first chunk", sending.Contents)
+	}
+}
+
+func TestRelayOutbox(t *testing.T) {
+	ctx := context.Background()
+	ps := newFakePostgres()
+	ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: postgrestore.MessageStatusSending}
+	rs := newFakeRedis()
+	s := newTestService(ps, rs, &fakeHook{})
+
+	s.processSendingMessage(ctx, ps.messages[1])
+
+	if relayed, err := s.RelayOutbox(ctx); err != nil || relayed != 1 {
+		t.Fatalf("RelayOutbox = %d, %v, want 1 event relayed", relayed, err)
+	}
+
+	var cached redisstore.RedisMessage
+	if err := rs.Get(ctx, messageKey(1), &cached); err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+
+	if len(cached.Contents) != 1 || cached.Contents[0].MessageId != "provider-1" || cached.Contents[0].Content != "hello" {
+		t.Errorf("projected contents = %+v", cached.Contents)
+	}
+
+	if events, _ := ps.FetchOutboxEvents(ctx, RelayOutboxLimit); len(events) != 0 {
+		t.Errorf("%d outbox events left unprocessed, want none", len(events))
+	}
+}
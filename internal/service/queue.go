@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+)
+
+const (
+	QueueConsumeCount = 10
+)
+
+// ConsumeQueue represents service's stream worker that sends messages as soon as they are published, entries left
+// pending by a crashed worker are reclaimed before new ones are read. It blocks until ctx is done.
+func (s *RestService) ConsumeQueue(ctx context.Context, consumer string) error {
+	if s.queue == nil {
+		return errors.New("queue backend is not enabled")
+	}
+
+	for ctx.Err() == nil {
+		// workers only send transactional messages, a failed read of the sending state pauses them until the next check
+		if pause, err := s.sendingPause(ctx); err != nil || pause.all || pause.filter.SkipTransactional {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+
+			continue
+		}
+
+		entries, err := s.queue.Reclaim(ctx, consumer, QueueConsumeCount)
+		if err != nil {
+			s.log(err, map[string]interface{}{
+				"action": "ConsumeQueue",
+				"method": "Reclaim",
+			})
+		}
+
+		if len(entries) == 0 {
+			entries, err = s.queue.Consume(ctx, consumer, QueueConsumeCount)
+			if err != nil && ctx.Err() == nil {
+				s.log(err, map[string]interface{}{
+					"action": "ConsumeQueue",
+					"method": "Consume",
+				})
+
+				time.Sleep(time.Second)
+				continue
+			}
+		}
+
+		for _, entry := range entries {
+			s.processQueueEntry(ctx, entry)
+		}
+	}
+
+	return nil
+}
+
+// processQueueEntry sends the message of an entry and acknowledges it, a message that is not claimable is already
+// handled by the cron or another worker. Failed sends are queued again and retried by the cron. Once the service is
+// draining, entries are left pending for another worker. A send in progress is not cancelled with ctx, Drain waits
+// for it and cancels it once the drain times out.
+func (s *RestService) processQueueEntry(ctx context.Context, entry redisstore.QueueEntry) {
+	if !s.inflight.start() {
+		return
+	}
+	defer s.inflight.done()
+
+	// entries carry no tenant, the message is claimed across tenants and sent on behalf of its tenant
+	ctx = tenant.HubWide(context.WithoutCancel(ctx))
+
+	message, err := s.ps.ClaimMessage(ctx, entry.MessageID, s.lease)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ConsumeQueue",
+			"method": "ClaimMessage",
+		})
+
+		return
+	}
+
+	if message != nil {
+		s.inflight.add(message.ID)
+		s.processSendingMessage(ctx, *message)
+		s.inflight.remove(message.ID)
+	}
+
+	if err := s.queue.Ack(ctx, entry.ID); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "ConsumeQueue",
+			"method": "Ack",
+		})
+	}
+}
+
+// publishMessages publishes messages to the stream when the queue backend is enabled, a failure is only logged
+// since unpublished messages are still sent by the cron
+func (s *RestService) publishMessages(ctx context.Context, ids ...int64) {
+	if s.queue == nil {
+		return
+	}
+
+	if err := s.queue.Publish(ctx, ids...); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "PublishMessages",
+			"method": "Publish",
+		})
+	}
+}
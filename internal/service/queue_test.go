@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
+)
+
+func TestCreateMessagePublishes(t *testing.T) {
+	ps := newFakePostgres()
+	queue := &fakeQueue{}
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.queue = queue
+
+	res := s.CreateMessage(tenant.With(context.Background(), tenant.Default), rest.CreateMessageRequest{Recipient: "05325008081", Content: "hello"})
+	if res.Result != nil {
+		t.Fatalf("CreateMessage result = %+v", res.Result)
+	}
+
+	if len(res.Data.Messages) != 1 || !slices.Equal(queue.published, []int64{res.Data.Messages[0].ID}) {
+		t.Errorf("published %v, want the created message", queue.published)
+	}
+}
+
+func TestProcessQueueEntry(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		sent   int
+		want   string
+	}{
+		{name: "queued", status: postgrestore.MessageStatusQueued, sent: 1, want: postgrestore.MessageStatusSent},
+		{name: "sent by the cron", status: postgrestore.MessageStatusSent, want: postgrestore.MessageStatusSent},
+		{name: "cancelled", status: postgrestore.MessageStatusCancelled, want: postgrestore.MessageStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			ps.messages[1] = postgrestore.Message{ID: 1, Recipient: "+905325008081", Content: "hello", Status: tt.status}
+			queue := &fakeQueue{}
+			hc := &fakeHook{}
+			s := newTestService(ps, newFakeRedis(), hc)
+			s.queue = queue
+
+			s.processQueueEntry(context.Background(), redisstore.QueueEntry{ID: "1-0", MessageID: 1})
+
+			if len(hc.sent) != tt.sent {
+				t.Errorf("sent %d chunks, want %d", len(hc.sent), tt.sent)
+			}
+
+			if ps.messages[1].Status != tt.want {
+				t.Errorf("status = %q, want %q", ps.messages[1].Status, tt.want)
+			}
+
+			// entries are acknowledged whether or not their message is still claimable
+			if !slices.Equal(queue.acked, []string{"1-0"}) {
+				t.Errorf("acknowledged %v, want [1-0]", queue.acked)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	rest "notify-hub-backend"
+	"notify-hub-backend/internal/limit"
+)
+
+// reserveQuota counts n messages against the daily send quota of the caller's tenant, it returns the 429 error of a
+// quota the messages do not fit in
+func (s *RestService) reserveQuota(ctx context.Context, n int) *rest.APIError {
+	err := s.limits.Reserve(ctx, n)
+	if err == nil {
+		return nil
+	}
+
+	var exceeded *limit.Exceeded
+	if errors.As(err, &exceeded) {
+		return exceeded.APIError()
+	}
+
+	s.log(err, map[string]interface{}{
+		"action": "ReserveQuota",
+		"method": "Reserve",
+	})
+
+	return &rest.APIError{
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+		Type:    rest.ErrorTypeInternal,
+	}
+}
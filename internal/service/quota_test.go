@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	rest "notify-hub-backend"
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/limit"
+	"notify-hub-backend/internal/tenant"
+
+	"github.com/go-kit/log"
+)
+
+func TestCreateMessageQuota(t *testing.T) {
+	ps := newFakePostgres()
+	rs := newFakeRedis()
+	s := newTestService(ps, rs, &fakeHook{})
+
+	limits, err := limit.New(log.NewNopLogger(), rs, envvars.Tenant{DailyQuota: 2})
+	if err != nil {
+		t.Fatalf("limit.New error = %v", err)
+	}
+
+	s.limits = limits
+
+	payments := tenant.With(context.Background(), "payments")
+	growth := tenant.With(context.Background(), "growth")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		insertErr error
+		code      int
+		errType   string
+		messages  int
+	}{
+		{name: "within the quota", ctx: payments, messages: 1},
+		{name: "up to the quota", ctx: payments, messages: 2},
+		{name: "over the quota", ctx: payments, code: http.StatusTooManyRequests, errType: rest.ErrorTypeQuotaExceeded, messages: 2},
+		{name: "quota of another tenant", ctx: growth, messages: 3},
+		{name: "failed insert gives the quota back", ctx: growth, insertErr: errors.New("connection refused"), code: http.StatusInternalServerError, errType: rest.ErrorTypeInternal, messages: 3},
+		{name: "quota given back", ctx: growth, messages: 4},
+		{name: "no tenant", ctx: context.Background(), code: http.StatusInternalServerError, errType: rest.ErrorTypeInternal, messages: 4},
+	}
+
+	for _, tt := range tests {
+		ps.insertErr = tt.insertErr
+
+		res := s.CreateMessage(tt.ctx, rest.CreateMessageRequest{Recipient: "05325008081", Content: "hello"})
+		switch {
+		case tt.code == 0 && res.Result != nil:
+			t.Fatalf("%s: CreateMessage result = %+v", tt.name, res.Result)
+		case tt.code != 0 && (res.Result == nil || res.Result.Code != tt.code || res.Result.Type != tt.errType):
+			t.Fatalf("%s: CreateMessage result = %+v, want %d %s", tt.name, res.Result, tt.code, tt.errType)
+		}
+
+		if len(ps.messages) != tt.messages {
+			t.Errorf("%s: %d messages queued, want %d", tt.name, len(ps.messages), tt.messages)
+		}
+	}
+}
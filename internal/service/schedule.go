@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	rest "notify-hub-backend"
+	"notify-hub-backend/internal/scheduler"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// job names of the scheduler
+const (
+	JobSendMessages  = "send-messages"
+	JobRelayOutbox   = "relay-outbox"
+	JobSyncSchedules = "sync-schedules"
+	JobPruneJobRuns  = "prune-job-runs"
+)
+
+const FetchJobRunsLimit = 100
+
+const scheduleNotFoundError = "schedule not found"
+
+// SyncSchedules represents service's cron job that applies schedules changed at runtime, so that a change made on one
+// instance reaches every instance and survives restarts. Jobs without a stored schedule keep their configured one. It
+// returns the number of stored schedules.
+func (s *RestService) SyncSchedules(ctx context.Context) (int, error) {
+	schedules, err := s.ps.FetchJobSchedules(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "SyncSchedules",
+			"method": "FetchJobSchedules",
+		})
+
+		return 0, err
+	}
+
+	for _, schedule := range schedules {
+		err := s.scheduler.Reschedule(schedule.Name, schedule.Spec)
+		if err != nil && !errors.Is(err, scheduler.ErrUnknownJob) {
+			s.log(err, map[string]interface{}{
+				"action": "SyncSchedules",
+				"method": "Reschedule",
+			})
+		}
+	}
+
+	return len(schedules), nil
+}
+
+// FetchSchedules returns fetch schedules
+// swagger:operation GET /schedules fetchSchedulesRequest
+// ---
+// summary: Fetch Schedules
+// description: Returns the schedule of each job with its next planned run and the outcome of its last run on this instance
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchSchedulesResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchSchedules(ctx context.Context, _ rest.FetchSchedulesRequest) rest.FetchSchedulesResponse {
+	res := rest.FetchSchedulesResponse{}
+
+	stored, err := s.ps.FetchJobSchedules(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSchedules",
+			"method": "FetchJobSchedules",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	changes := make(map[string]postgrestore.JobSchedule, len(stored))
+	for _, schedule := range stored {
+		changes[schedule.Name] = schedule
+	}
+
+	entries := s.scheduler.Entries()
+	data := &rest.FetchSchedulesData{
+		Schedules: make([]rest.ScheduleData, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		var change *postgrestore.JobSchedule
+		if schedule, ok := changes[entry.Name]; ok {
+			change = &schedule
+		}
+
+		data.Schedules = append(data.Schedules, scheduleData(entry, change))
+	}
+
+	res.Data = data
+
+	return res
+}
+
+// UpdateSchedule returns update schedule
+// swagger:operation PUT /schedules/{name} updateScheduleRequest
+// ---
+// summary: Update Schedule
+// description: Changes the schedule of a job, the job is rescheduled right away on this instance and on the next schedule sync on other instances
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/updateScheduleResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) UpdateSchedule(ctx context.Context, req rest.UpdateScheduleRequest) rest.UpdateScheduleResponse {
+	res := rest.UpdateScheduleResponse{}
+
+	if _, err := scheduler.ParseSpec(req.Spec); err != nil {
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+
+		return res
+	}
+
+	if _, err := s.scheduler.Entry(req.Name); err != nil {
+		res.Result = &rest.APIError{
+			Message: scheduleNotFoundError,
+			Code:    http.StatusNotFound,
+			Type:    rest.ErrorTypeNotFound,
+		}
+
+		return res
+	}
+
+	schedule := postgrestore.JobSchedule{
+		Name:      req.Name,
+		Spec:      req.Spec,
+		ChangedBy: operator(ctx, req.Operator),
+	}
+
+	if err := s.ps.SetJobSchedule(ctx, &schedule); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "UpdateSchedule",
+			"method": "SetJobSchedule",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	if err := s.scheduler.Reschedule(req.Name, req.Spec); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "UpdateSchedule",
+			"method": "Reschedule",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	entry, err := s.scheduler.Entry(req.Name)
+	if err != nil {
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	data := scheduleData(entry, &schedule)
+	res.Data = &data
+
+	return res
+}
+
+// FetchJobRuns returns fetch job runs
+// swagger:operation GET /schedules/{name}/runs fetchJobRunsRequest
+// ---
+// summary: Fetch Job Runs
+// description: Returns runs of a job on every instance with their duration, processed items and errors, newest first
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchJobRunsResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchJobRuns(ctx context.Context, req rest.FetchJobRunsRequest) rest.FetchJobRunsResponse {
+	res := rest.FetchJobRunsResponse{}
+
+	if _, err := s.scheduler.Entry(req.Name); err != nil {
+		res.Result = &rest.APIError{
+			Message: scheduleNotFoundError,
+			Code:    http.StatusNotFound,
+			Type:    rest.ErrorTypeNotFound,
+		}
+
+		return res
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = FetchJobRunsLimit
+	}
+
+	runs, err := s.ps.FetchJobRuns(ctx, req.Name, limit)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchJobRuns",
+			"method": "FetchJobRuns",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	data := &rest.FetchJobRunsData{
+		Runs: make([]rest.JobRunData, 0, len(runs)),
+	}
+
+	for _, run := range runs {
+		data.Runs = append(data.Runs, rest.JobRunData{
+			ID:         run.ID,
+			Instance:   run.Instance,
+			StartedAt:  run.StartedAt,
+			FinishedAt: run.FinishedAt,
+			DurationMs: run.DurationMs,
+			Processed:  run.Processed,
+			Error:      run.Error,
+		})
+	}
+
+	res.Data = data
+
+	return res
+}
+
+// PruneJobRuns represents service's cron job that deletes job runs older than the retention, it returns the number
+// of deleted runs.
+func (s *RestService) PruneJobRuns(ctx context.Context) (int, error) {
+	deleted, err := s.ps.DeleteJobRuns(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "PruneJobRuns",
+			"method": "DeleteJobRuns",
+		})
+
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// scheduleData converts a scheduled job, change is nil if the schedule was not changed at runtime
+func scheduleData(entry scheduler.Entry, change *postgrestore.JobSchedule) rest.ScheduleData {
+	data := rest.ScheduleData{
+		Name:    entry.Name,
+		Spec:    entry.Spec,
+		Running: entry.Running,
+	}
+
+	if !entry.Next.IsZero() {
+		next := entry.Next
+		data.NextRunAt = &next
+	}
+
+	if entry.LastRun != nil {
+		data.LastRun = &rest.ScheduleRunData{
+			StartedAt:  entry.LastRun.StartedAt,
+			FinishedAt: entry.LastRun.FinishedAt,
+			Processed:  entry.LastRun.Processed,
+			Error:      entry.LastRun.Error,
+		}
+	}
+
+	if change != nil {
+		data.ChangedBy = change.ChangedBy
+		data.ChangedAt = &change.ChangedAt
+	}
+
+	return data
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	rest "notify-hub-backend"
+	"notify-hub-backend/internal/scheduler"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+
+	"github.com/go-kit/log"
+)
+
+func TestUpdateSchedule(t *testing.T) {
+	tests := []struct {
+		name string
+		req  rest.UpdateScheduleRequest
+		code int
+		spec string
+	}{
+		{name: "valid", req: rest.UpdateScheduleRequest{Operator: "ayse", Name: JobSendMessages, Spec: "@every 30s"}, spec: "@every 30s"},
+		{name: "invalid spec", req: rest.UpdateScheduleRequest{Name: JobSendMessages, Spec: "every minute"}, code: http.StatusBadRequest, spec: "@every 2m"},
+		{name: "unknown job", req: rest.UpdateScheduleRequest{Name: "cleanup", Spec: "@every 30s"}, code: http.StatusNotFound, spec: "@every 2m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			s := newTestService(ps, newFakeRedis(), &fakeHook{})
+			s.scheduler = scheduler.New(context.Background(), log.NewNopLogger(), newFakeRedis(), ps, "test", time.Minute)
+			_ = s.scheduler.Add(JobSendMessages, "@every 2m", true, s.CronSendMessage)
+
+			res := s.UpdateSchedule(context.Background(), tt.req)
+			if tt.code != 0 {
+				if res.Result == nil || res.Result.Code != tt.code {
+					t.Fatalf("UpdateSchedule result = %+v, want code %d", res.Result, tt.code)
+				}
+
+				if len(ps.schedules) != 0 {
+					t.Errorf("stored %+v, want nothing", ps.schedules)
+				}
+			} else if res.Result != nil {
+				t.Fatalf("UpdateSchedule result = %+v", res.Result)
+			} else if res.Data.ChangedBy != "ayse" || res.Data.Spec != tt.spec {
+				t.Errorf("UpdateSchedule = %+v, want %s changed by ayse", res.Data, tt.spec)
+			}
+
+			if entry, _ := s.scheduler.Entry(JobSendMessages); entry.Spec != tt.spec {
+				t.Errorf("send-messages spec = %q, want %q", entry.Spec, tt.spec)
+			}
+		})
+	}
+}
+
+func TestSyncSchedules(t *testing.T) {
+	ps := newFakePostgres()
+	ps.schedules = []postgrestore.JobSchedule{
+		{Name: JobSendMessages, Spec: "@every 30s"},
+		// a job that is not scheduled on this instance is skipped
+		{Name: "cleanup", Spec: "@every 1h"},
+	}
+
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.scheduler = scheduler.New(context.Background(), log.NewNopLogger(), newFakeRedis(), ps, "test", time.Minute)
+	_ = s.scheduler.Add(JobSendMessages, "@every 2m", true, s.CronSendMessage)
+	_ = s.scheduler.Add(JobRelayOutbox, "@every 10s", true, s.RelayOutbox)
+
+	if _, err := s.SyncSchedules(context.Background()); err != nil {
+		t.Fatalf("SyncSchedules error = %v", err)
+	}
+
+	want := map[string]string{JobRelayOutbox: "@every 10s", JobSendMessages: "@every 30s"}
+	for _, entry := range s.scheduler.Entries() {
+		if entry.Spec != want[entry.Name] {
+			t.Errorf("%s spec = %q, want %q", entry.Name, entry.Spec, want[entry.Name])
+		}
+	}
+}
+
+func TestFetchJobRuns(t *testing.T) {
+	ps := newFakePostgres()
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.scheduler = scheduler.New(context.Background(), log.NewNopLogger(), newFakeRedis(), ps, "test", time.Minute)
+	_ = s.scheduler.Add(JobSendMessages, "@every 2m", false, s.CronSendMessage)
+
+	// the runs of both instances are returned
+	_ = ps.InsertJobRun(context.Background(), &postgrestore.JobRun{Name: JobSendMessages, Instance: "api-1", Processed: 2})
+	_ = ps.InsertJobRun(context.Background(), &postgrestore.JobRun{Name: JobRelayOutbox, Instance: "api-1"})
+	_ = ps.InsertJobRun(context.Background(), &postgrestore.JobRun{Name: JobSendMessages, Instance: "api-2", Error: "connection refused"})
+
+	res := s.FetchJobRuns(context.Background(), rest.FetchJobRunsRequest{Name: JobSendMessages})
+	if res.Result != nil {
+		t.Fatalf("FetchJobRuns result = %+v", res.Result)
+	}
+
+	if len(res.Data.Runs) != 2 || res.Data.Runs[0].Instance != "api-2" || res.Data.Runs[1].Processed != 2 {
+		t.Errorf("runs = %+v, want the send-messages runs newest first", res.Data.Runs)
+	}
+
+	res = s.FetchJobRuns(context.Background(), rest.FetchJobRunsRequest{Name: "cleanup"})
+	if res.Result == nil || res.Result.Code != http.StatusNotFound {
+		t.Errorf("FetchJobRuns of an unknown job result = %+v, want code %d", res.Result, http.StatusNotFound)
+	}
+}
+
+func TestPruneJobRuns(t *testing.T) {
+	ps := newFakePostgres()
+	s := newTestService(ps, newFakeRedis(), &fakeHook{})
+	s.retention = time.Hour
+
+	ps.jobRuns = []postgrestore.JobRun{
+		{ID: 1, Name: JobSendMessages, StartedAt: time.Now().Add(-2 * time.Hour)},
+		{ID: 2, Name: JobSendMessages, StartedAt: time.Now()},
+	}
+
+	deleted, err := s.PruneJobRuns(context.Background())
+	if err != nil {
+		t.Fatalf("PruneJobRuns error = %v", err)
+	}
+
+	if deleted != 1 || len(ps.jobRuns) != 1 || ps.jobRuns[0].ID != 2 {
+		t.Errorf("deleted %d and kept %+v, want the run older than the retention deleted", deleted, ps.jobRuns)
+	}
+}
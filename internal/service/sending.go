@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+const (
+	invalidSendingScopeError = "invalid sending scope, use global, channel:marketing, channel:transactional, provider:hook or campaign:<id>"
+	invalidResumeAtError     = "resumeAt must be in the future and is only allowed when pausing"
+)
+
+// SetSendingState returns set sending state
+// swagger:operation PUT /sending/state setSendingStateRequest
+// ---
+// summary: Set Sending State
+// description: Pauses or resumes sending of a scope, setting the current state again changes nothing. A paused scope is resumed automatically at resumeAt
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/setSendingStateResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) SetSendingState(ctx context.Context, req rest.SetSendingStateRequest) rest.SetSendingStateResponse {
+	res := rest.SetSendingStateResponse{}
+
+	scope := req.Scope
+	if scope == "" {
+		scope = postgrestore.SendingScopeGlobal
+	}
+
+	if !validSendingScope(scope) {
+		res.Result = &rest.APIError{
+			Message: invalidSendingScopeError,
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+
+		return res
+	}
+
+	if req.ResumeAt != nil && (*req.Enabled || !req.ResumeAt.After(time.Now())) {
+		res.Result = &rest.APIError{
+			Message: invalidResumeAtError,
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+
+		return res
+	}
+
+	state, err := s.ps.SetSendingState(ctx, postgrestore.SendingState{
+		Scope:     scope,
+		Enabled:   *req.Enabled,
+		Reason:    req.Reason,
+		ResumeAt:  req.ResumeAt,
+		ChangedBy: operator(ctx, req.Operator),
+	})
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "SetSendingState",
+			"method": "SetSendingState",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	data := sendingStateData(*state, time.Now())
+	res.Data = &data
+
+	return res
+}
+
+// FetchSendingStates returns fetch sending states
+// swagger:operation GET /sending/state fetchSendingStatesRequest
+// ---
+// summary: Fetch Sending States
+// description: Returns the sending state of every scope that was paused or resumed, scopes that are not listed are enabled
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchSendingStatesResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchSendingStates(ctx context.Context, _ rest.FetchSendingStatesRequest) rest.FetchSendingStatesResponse {
+	res := rest.FetchSendingStatesResponse{}
+
+	states, err := s.ps.FetchSendingStates(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSendingStates",
+			"method": "FetchSendingStates",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	now := time.Now()
+	data := &rest.FetchSendingStatesData{
+		States: make([]rest.SendingStateData, 0, len(states)),
+	}
+
+	for _, state := range states {
+		data.States = append(data.States, sendingStateData(state, now))
+	}
+
+	res.Data = data
+
+	return res
+}
+
+// sendingPause represents what is paused for a sending run, all is set when every message is paused.
+type sendingPause struct {
+	all    bool
+	filter postgrestore.ClaimFilter
+}
+
+// sendingPause reads the sending states, the states are read from postgres on every run so that a change applies to
+// every instance right away and survives restarts
+func (s *RestService) sendingPause(ctx context.Context) (sendingPause, error) {
+	states, err := s.ps.FetchSendingStates(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "SendingPause",
+			"method": "FetchSendingStates",
+		})
+
+		return sendingPause{}, err
+	}
+
+	var pause sendingPause
+	now := time.Now()
+
+	for _, state := range states {
+		if state.EnabledAt(now) {
+			continue
+		}
+
+		switch state.Scope {
+		case postgrestore.SendingScopeGlobal, postgrestore.SendingScopeHookProvider:
+			pause.all = true
+		case postgrestore.SendingScopeMarketing:
+			pause.filter.SkipMarketing = true
+		case postgrestore.SendingScopeTransactional:
+			pause.filter.SkipTransactional = true
+		default:
+			if id, ok := sendingScopeCampaignID(state.Scope); ok {
+				pause.filter.PausedCampaignIDs = append(pause.filter.PausedCampaignIDs, id)
+			}
+		}
+	}
+
+	return pause, nil
+}
+
+func validSendingScope(scope string) bool {
+	switch scope {
+	case postgrestore.SendingScopeGlobal,
+		postgrestore.SendingScopeMarketing,
+		postgrestore.SendingScopeTransactional,
+		postgrestore.SendingScopeHookProvider:
+		return true
+	}
+
+	_, ok := sendingScopeCampaignID(scope)
+
+	return ok
+}
+
+func sendingScopeCampaignID(scope string) (int64, bool) {
+	value, ok := strings.CutPrefix(scope, postgrestore.SendingScopeCampaignPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// sendingStateData converts a sending state, a paused state whose resume time has passed is reported as enabled
+func sendingStateData(state postgrestore.SendingState, now time.Time) rest.SendingStateData {
+	return rest.SendingStateData{
+		Scope:     state.Scope,
+		Enabled:   state.EnabledAt(now),
+		Reason:    state.Reason,
+		ResumeAt:  state.ResumeAt,
+		ChangedBy: state.ChangedBy,
+		ChangedAt: state.ChangedAt,
+	}
+}
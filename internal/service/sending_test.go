@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+
+	"github.com/go-kit/log"
+)
+
+// fakeSendingStore keeps sending states in memory, the other methods of the store are not used
+type fakeSendingStore struct {
+	postgrestore.Store
+	states []postgrestore.SendingState
+	err    error
+}
+
+func (s *fakeSendingStore) FetchSendingStates(context.Context) ([]postgrestore.SendingState, error) {
+	return s.states, s.err
+}
+
+func (s *fakeSendingStore) SetSendingState(_ context.Context, state postgrestore.SendingState) (*postgrestore.SendingState, error) {
+	state.ChangedAt = time.Now()
+	s.states = append(s.states, state)
+
+	return &state, nil
+}
+
+func TestSendingPause(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		states []postgrestore.SendingState
+		want   sendingPause
+	}{
+		{
+			name: "nothing paused",
+		},
+		{
+			name: "enabled scopes",
+			states: []postgrestore.SendingState{
+				{Scope: postgrestore.SendingScopeGlobal, Enabled: true},
+				{Scope: postgrestore.SendingScopeMarketing, Enabled: true},
+			},
+		},
+		{
+			name:   "global",
+			states: []postgrestore.SendingState{{Scope: postgrestore.SendingScopeGlobal}},
+			want:   sendingPause{all: true},
+		},
+		{
+			name:   "provider",
+			states: []postgrestore.SendingState{{Scope: postgrestore.SendingScopeHookProvider, ResumeAt: &future}},
+			want:   sendingPause{all: true},
+		},
+		{
+			name:   "resume time passed",
+			states: []postgrestore.SendingState{{Scope: postgrestore.SendingScopeGlobal, ResumeAt: &past}},
+		},
+		{
+			name: "channels and campaigns",
+			states: []postgrestore.SendingState{
+				{Scope: postgrestore.SendingScopeMarketing},
+				{Scope: postgrestore.SendingScopeTransactional},
+				{Scope: "campaign:7"},
+				{Scope: "campaign:9", Enabled: true},
+				{Scope: "campaign:12", ResumeAt: &future},
+			},
+			want: sendingPause{filter: postgrestore.ClaimFilter{
+				SkipMarketing:     true,
+				SkipTransactional: true,
+				PausedCampaignIDs: []int64{7, 12},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &RestService{l: log.NewNopLogger(), ps: &fakeSendingStore{states: tt.states}}
+
+			got, err := s.sendingPause(context.Background())
+			if err != nil {
+				t.Fatalf("sendingPause error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sendingPause = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendingPauseFetchFails(t *testing.T) {
+	s := &RestService{l: log.NewNopLogger(), ps: &fakeSendingStore{err: errors.New("connection refused")}}
+
+	if _, err := s.sendingPause(context.Background()); err == nil {
+		t.Error("sendingPause error = nil, want the error of the store so that the run sends nothing")
+	}
+}
+
+func TestSetSendingState(t *testing.T) {
+	enabled, disabled := true, false
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		req     rest.SetSendingStateRequest
+		code    int
+		message string
+		scope   string
+	}{
+		{
+			name:  "pause global by default",
+			req:   rest.SetSendingStateRequest{Enabled: &disabled},
+			scope: postgrestore.SendingScopeGlobal,
+		},
+		{
+			name:  "pause campaign until resume time",
+			req:   rest.SetSendingStateRequest{Scope: "campaign:42", Enabled: &disabled, ResumeAt: &future},
+			scope: "campaign:42",
+		},
+		{
+			name:  "resume channel",
+			req:   rest.SetSendingStateRequest{Scope: postgrestore.SendingScopeMarketing, Enabled: &enabled},
+			scope: postgrestore.SendingScopeMarketing,
+		},
+		{
+			name:    "unknown scope",
+			req:     rest.SetSendingStateRequest{Scope: "channel:email", Enabled: &disabled},
+			code:    http.StatusBadRequest,
+			message: invalidSendingScopeError,
+		},
+		{
+			name:    "campaign without id",
+			req:     rest.SetSendingStateRequest{Scope: "campaign:0", Enabled: &disabled},
+			code:    http.StatusBadRequest,
+			message: invalidSendingScopeError,
+		},
+		{
+			name:    "resume time in the past",
+			req:     rest.SetSendingStateRequest{Enabled: &disabled, ResumeAt: &past},
+			code:    http.StatusBadRequest,
+			message: invalidResumeAtError,
+		},
+		{
+			name:    "resume time when resuming",
+			req:     rest.SetSendingStateRequest{Enabled: &enabled, ResumeAt: &future},
+			code:    http.StatusBadRequest,
+			message: invalidResumeAtError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &fakeSendingStore{}
+			s := &RestService{l: log.NewNopLogger(), ps: ps}
+
+			res := s.SetSendingState(context.Background(), tt.req)
+
+			if tt.code != 0 {
+				if res.Result == nil || res.Result.Code != tt.code || res.Result.Message != tt.message {
+					t.Fatalf("SetSendingState result = %+v, want %d %q", res.Result, tt.code, tt.message)
+				}
+
+				if len(ps.states) != 0 {
+					t.Errorf("SetSendingState stored %+v, want nothing", ps.states)
+				}
+
+				return
+			}
+
+			if res.Result != nil {
+				t.Fatalf("SetSendingState result = %+v", res.Result)
+			}
+
+			if res.Data.Scope != tt.scope || res.Data.Enabled != *tt.req.Enabled {
+				t.Errorf("SetSendingState = %+v, want scope %s enabled %v", *res.Data, tt.scope, *tt.req.Enabled)
+			}
+		})
+	}
+}
+
+func TestSendingStateData(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Second)
+	future := now.Add(time.Second)
+
+	tests := []struct {
+		name  string
+		state postgrestore.SendingState
+		want  bool
+	}{
+		{name: "enabled", state: postgrestore.SendingState{Enabled: true}, want: true},
+		{name: "paused", state: postgrestore.SendingState{}, want: false},
+		{name: "paused until later", state: postgrestore.SendingState{ResumeAt: &future}, want: false},
+		{name: "resume time passed", state: postgrestore.SendingState{ResumeAt: &past}, want: true},
+		{name: "resume time now", state: postgrestore.SendingState{ResumeAt: &now}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := sendingStateData(tt.state, now).Enabled; got != tt.want {
+			t.Errorf("%s: enabled = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
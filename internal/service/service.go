@@ -8,16 +8,23 @@ import (
 	"time"
 
 	rest "notify-hub-backend"
+	envvars "notify-hub-backend/configs/env-vars"
 	hookclient "notify-hub-backend/internal/client/hook"
+	"notify-hub-backend/internal/limit"
+	"notify-hub-backend/internal/phone"
+	"notify-hub-backend/internal/scheduler"
 	postgrestore "notify-hub-backend/internal/store/postgres"
 	redisstore "notify-hub-backend/internal/store/redis"
+	"notify-hub-backend/internal/tenant"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 )
 
 const (
-	FetchUnsentMessagesLimit = 2
+	FetchUnsentMessagesLimit  = 2
+	FetchSentMessagesLimit    = 100
+	FetchAutoSendHistoryLimit = 100
 )
 
 // compile-time proofs of service interface implementation
@@ -25,23 +32,42 @@ var _ rest.Service = (*RestService)(nil)
 
 // RestService represents service
 type RestService struct {
-	l          log.Logger
-	rs         redisstore.Store
-	ps         postgrestore.Store
-	hc         hookclient.Client
-	env        string
-	autoSendOn bool
+	l           log.Logger
+	rs          redisstore.Store
+	queue       redisstore.Queue
+	ps          postgrestore.Store
+	hc          hookclient.Client
+	limits      *limit.Limiter
+	env         string
+	region      string
+	maxAttempts int
+	lease       time.Duration
+	inbound     envvars.Inbound
+	scheduler   scheduler.Scheduler
+	retention   time.Duration
+	readiness   time.Duration
+	inflight    *inflight
 }
 
-// NewService creates and returns service
-func NewService(l log.Logger, rs redisstore.Store, ps postgrestore.Store, hc hookclient.Client, env string) rest.Service {
+// NewService creates and returns service, queue is nil unless the stream queue backend is enabled. Messages queued by a
+// tenant count against its daily send quota in limits.
+func NewService(l log.Logger, rs redisstore.Store, queue redisstore.Queue, ps postgrestore.Store, hc hookclient.Client, limits *limit.Limiter, sc scheduler.Scheduler, cfg envvars.Service, inbound envvars.Inbound) rest.Service {
 	return &RestService{
-		l:          l,
-		rs:         rs,
-		ps:         ps,
-		hc:         hc,
-		env:        env,
-		autoSendOn: true,
+		l:           l,
+		rs:          rs,
+		queue:       queue,
+		ps:          ps,
+		hc:          hc,
+		limits:      limits,
+		env:         cfg.Environment,
+		region:      cfg.DefaultPhoneRegion,
+		maxAttempts: cfg.MaxSendAttempts,
+		lease:       cfg.SendingLease,
+		inbound:     inbound,
+		scheduler:   sc,
+		retention:   cfg.JobRunRetention,
+		readiness:   cfg.ReadinessTimeout,
+		inflight:    newInflight(),
 	}
 }
 
@@ -54,108 +80,421 @@ func (s *RestService) Health(_ context.Context, _ rest.HealthRequest) rest.Healt
 // swagger:operation POST /switch-auto-send switchAutoSendRequest
 // ---
 // summary: Switch Auto Send
-// description: Returns response of switch auto send result
+// description: Toggles auto send for every instance and records who switched it and why
 // responses:
 //
 //	  200:
 //		  $ref: "#/responses/switchAutoSendResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
 func (s *RestService) SwitchAutoSend(ctx context.Context, req rest.SwitchAutoSendRequest) rest.SwitchAutoSendResponse {
-	s.autoSendOn = !s.autoSendOn
+	res := rest.SwitchAutoSendResponse{}
 
-	return rest.SwitchAutoSendResponse{
-		Data: &rest.SwitchAutoSendData{
-			AutoSendOn: s.autoSendOn,
-		},
+	state, err := s.ps.ToggleSendingState(ctx, postgrestore.SendingScopeGlobal, operator(ctx, req.Operator), req.Reason)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "SwitchAutoSend",
+			"method": "ToggleSendingState",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
 	}
+
+	res.Data = &rest.SwitchAutoSendData{
+		AutoSendOn: state.Enabled,
+		Reason:     state.Reason,
+		ChangedBy:  state.ChangedBy,
+		ChangedAt:  state.ChangedAt,
+	}
+
+	return res
+}
+
+// FetchAutoSendHistory returns fetch auto send history
+// swagger:operation GET /switch-auto-send/history fetchAutoSendHistoryRequest
+// ---
+// summary: Fetch Auto Send History
+// description: Returns auto send switches with who switched it, when and why, newest first
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchAutoSendHistoryResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchAutoSendHistory(ctx context.Context, req rest.FetchAutoSendHistoryRequest) rest.FetchAutoSendHistoryResponse {
+	res := rest.FetchAutoSendHistoryResponse{}
+
+	scope := req.Scope
+	if scope == "" {
+		scope = postgrestore.SendingScopeGlobal
+	}
+
+	if !validSendingScope(scope) {
+		res.Result = &rest.APIError{
+			Message: invalidSendingScopeError,
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+
+		return res
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = FetchAutoSendHistoryLimit
+	}
+
+	changes, err := s.ps.FetchSendingStateChanges(ctx, scope, limit)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchAutoSendHistory",
+			"method": "FetchSendingStateChanges",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	data := &rest.FetchAutoSendHistoryData{
+		Changes: make([]rest.SendingStateData, 0, len(changes)),
+	}
+
+	for _, change := range changes {
+		data.Changes = append(data.Changes, rest.SendingStateData{
+			Scope:     change.Scope,
+			Enabled:   change.Enabled,
+			Reason:    change.Reason,
+			ResumeAt:  change.ResumeAt,
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+
+	res.Data = data
+
+	return res
+}
+
+// CreateMessage returns create message
+// swagger:operation POST /messages createMessageRequest
+// ---
+// summary: Create Message
+// description: Queues a message for the sending job, recipient is stored in E.164 format and a group target is expanded into a message per member contact. Queued messages count against the daily send quota of the caller's tenant.
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/createMessageResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) CreateMessage(ctx context.Context, req rest.CreateMessageRequest) rest.CreateMessageResponse {
+	res := rest.CreateMessageResponse{}
+
+	messages, apiErr := s.buildMessages(ctx, req.Content, []string{req.Recipient}, req.GroupID, req.Template)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	if apiErr := s.reserveQuota(ctx, len(messages)); apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	if err := s.ps.InsertMessages(ctx, messages); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CreateMessage",
+			"method": "InsertMessages",
+		})
+
+		s.limits.Release(ctx, len(messages))
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	ids := make([]int64, 0, len(messages))
+	createdMessages := make([]rest.CreatedMessage, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+		createdMessages = append(createdMessages, rest.CreatedMessage{
+			ID:        message.ID,
+			Recipient: message.Recipient,
+			Status:    message.Status,
+		})
+	}
+
+	s.publishMessages(ctx, ids...)
+
+	res.Data = &rest.CreateMessageData{
+		Messages: createdMessages,
+	}
+
+	return res
 }
 
 // FetchSentMessages returns fetch messages
 // swagger:operation GET /fetch-sent-messages fetchSentMessagesRequest
 // ---
 // summary: FetchSentMessages
-// description: Returns response of fetch messages result
+// description: Returns a page of sent messages filtered by recipient, sending time, status and campaign
 // responses:
 //
 //	  200:
 //		  $ref: "#/responses/fetchSentMessagesResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
 func (s *RestService) FetchSentMessages(ctx context.Context, req rest.FetchSentMessagesRequest) rest.FetchSentMessagesResponse {
 	res := rest.FetchSentMessagesResponse{}
 
-	messages, err := s.ps.FetchMessages(ctx, true, 1000)
+	filter, apiErr := s.sentMessagesFilter(req)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+
+	messages, err := s.ps.FetchMessagesPage(ctx, filter)
 	if err != nil {
 		s.log(err, map[string]interface{}{
-			"action": "CronSendMessage",
-			"method": "FetchUnsentMessages",
+			"action": "FetchSentMessages",
+			"method": "FetchMessagesPage",
 		})
 
 		res.Result = &rest.APIError{
 			Message: err.Error(),
-			Code:    http.StatusBadRequest,
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	var nextCursor string
+	if len(messages) > limit {
+		messages = messages[:limit]
+		nextCursor = encodeCursor(req.Sort, messages[limit-1])
+	}
+
+	contents, err := s.sentMessageContents(ctx, messages)
+	if err != nil {
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
 		}
+
+		return res
 	}
 
 	var sentMessages []rest.FetchSentMessage
 
 	for _, message := range messages {
-		var redisMessage redisstore.RedisMessage
+		var messageContents []rest.FetchSentMessageContent
 
-		rsKey := fmt.Sprintf("%v", message.ID)
-		err := s.rs.Get(rsKey, &redisMessage)
-		if err != nil {
-			s.log(err, map[string]interface{}{
-				"action": "CronSendMessage",
-				"method": "Redis Get",
+		for _, rm := range contents[message.ID] {
+			messageContents = append(messageContents, rest.FetchSentMessageContent{
+				MessageId:   rm.MessageId,
+				Content:     rm.Content,
+				SendingTime: rm.SendingTime,
 			})
 		}
 
-		var contents []rest.FetchSentMessageContent
-
-		if len(redisMessage.Contents) > 0 {
-			for _, rm := range redisMessage.Contents {
-				contents = append(contents, rest.FetchSentMessageContent{
-					MessageId:   rm.MessageId,
-					Content:     rm.Content,
-					SendingTime: rm.SendingTime,
-				})
-			}
-		}
-
 		sentMessages = append(sentMessages, rest.FetchSentMessage{
-			Recipient: message.Recipient,
-			Contents:  contents,
+			ID:         message.ID,
+			Recipient:  message.Recipient,
+			Status:     message.Status,
+			CampaignID: message.CampaignID,
+			SentAt:     message.SentAt,
+			Contents:   messageContents,
 		})
 	}
 
 	res.Data = &rest.FetchSentMessagesData{
 		SentMessages: sentMessages,
+		NextCursor:   nextCursor,
 	}
 
 	return res
 }
 
-// CronSendMessage represents service's scheduled job that runs
-func (s *RestService) CronSendMessage(ctx context.Context) error {
-	if s.autoSendOn {
-		messages, err := s.ps.FetchMessages(ctx, false, FetchUnsentMessagesLimit)
+// sentMessageContents returns chunks of messages by message id, chunks are read from redis and the ones missing in
+// redis are loaded from postgres and cached
+func (s *RestService) sentMessageContents(ctx context.Context, messages []postgrestore.Message) (map[int64][]redisstore.RedisMessageContent, error) {
+	contents := make(map[int64][]redisstore.RedisMessageContent, len(messages))
+
+	var ids []int64
+	var rsKeys []string
+	var redisMessages []interface{}
+
+	for _, message := range messages {
+		if message.SentAt == nil {
+			continue
+		}
+
+		ids = append(ids, message.ID)
+		rsKeys = append(rsKeys, messageKey(message.ID))
+		redisMessages = append(redisMessages, &redisstore.RedisMessage{})
+	}
+
+	found, err := s.rs.MGet(ctx, rsKeys, redisMessages)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSentMessages",
+			"method": "Redis MGet",
+		})
+
+		found = make([]bool, len(ids))
+	}
+
+	var missingIDs []int64
+
+	for i, id := range ids {
+		redisMessage := redisMessages[i].(*redisstore.RedisMessage)
+		if !found[i] || len(redisMessage.Contents) == 0 {
+			missingIDs = append(missingIDs, id)
+			continue
+		}
+
+		contents[id] = redisMessage.Contents
+	}
+
+	deliveries, err := s.ps.FetchDeliveriesOfMessages(ctx, missingIDs)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSentMessages",
+			"method": "FetchDeliveriesOfMessages",
+		})
+
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		contents[delivery.MessageID] = append(contents[delivery.MessageID], redisstore.RedisMessageContent{
+			MessageId:   delivery.ProviderMessageID,
+			SendingTime: delivery.SentAt,
+			Content:     delivery.Content,
+		})
+	}
+
+	missing := make(map[string]interface{}, len(missingIDs))
+	for _, id := range missingIDs {
+		if len(contents[id]) > 0 {
+			missing[messageKey(id)] = redisstore.RedisMessage{Contents: contents[id]}
+		}
+	}
+
+	if err := s.rs.MSet(ctx, missing); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSentMessages",
+			"method": "Redis MSet",
+		})
+	}
+
+	return contents, nil
+}
+
+// sentMessagesFilter returns store filter of fetch sent messages request, sent and delivered messages are returned
+// when no status is requested
+func (s *RestService) sentMessagesFilter(req rest.FetchSentMessagesRequest) (postgrestore.MessageFilter, *rest.APIError) {
+	filter := postgrestore.MessageFilter{
+		Statuses:   req.Status,
+		CampaignID: req.CampaignID,
+		From:       req.From,
+		To:         req.To,
+		Limit:      req.Limit,
+	}
+
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []string{postgrestore.MessageStatusSent, postgrestore.MessageStatusDelivered}
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = FetchSentMessagesLimit
+	}
+
+	filter.SortBy, filter.Descending = parseSort(req.Sort)
+
+	if req.Recipient != "" {
+		recipient, apiErr := s.normalizeRecipient(req.Recipient)
+		if apiErr != nil {
+			return filter, apiErr
+		}
+
+		filter.Recipient = recipient
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, req.Sort)
+		if err != nil {
+			return filter, &rest.APIError{
+				Message: "validation failed, field: cursor, " + err.Error(),
+				Code:    http.StatusBadRequest,
+				Type:    rest.ErrorTypeValidation,
+			}
+		}
+
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+// CronSendMessage represents service's scheduled job that runs, it returns the number of messages it sent or retried.
+// It claims nothing once the service is draining. It claims the messages of every tenant, each message is sent on
+// behalf of its tenant.
+func (s *RestService) CronSendMessage(ctx context.Context) (int, error) {
+	if !s.inflight.start() {
+		return 0, nil
+	}
+	defer s.inflight.done()
+
+	ctx = tenant.HubWide(ctx)
+
+	pause, err := s.sendingPause(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !pause.all {
+		messages, err := s.ps.ClaimSendableMessages(ctx, FetchUnsentMessagesLimit, s.lease, pause.filter)
 		if err != nil {
 			s.log(err, map[string]interface{}{
 				"action": "CronSendMessage",
-				"method": "FetchUnsentMessages",
+				"method": "ClaimSendableMessages",
 			})
 
-			return err
+			return 0, err
 		}
 
 		messageLen := len(messages)
 
 		if messageLen == 0 {
-			return nil
+			return 0, nil
 		}
 
 		ch := make(chan postgrestore.Message, messageLen)
 		var wg sync.WaitGroup
 
 		for _, message := range messages {
+			s.inflight.add(message.ID)
 			wg.Add(1)
 			ch <- message
 		}
@@ -167,61 +506,190 @@ func (s *RestService) CronSendMessage(ctx context.Context) error {
 				defer wg.Done()
 				for message := range ch {
 					s.processSendingMessage(ctx, message)
+					s.inflight.remove(message.ID)
 				}
 			}()
 		}
 
 		wg.Wait()
+
+		return messageLen, nil
 	}
 
-	return nil
+	return 0, nil
 }
 
+// processSendingMessage sends a claimed message on behalf of its tenant, whose suppressions apply to it. Provider calls
+// are cancelled when the drain aborts the sends in progress, the message is then left sending for Drain to release.
 func (s *RestService) processSendingMessage(ctx context.Context, message postgrestore.Message) {
 	const maxMessageCharacterSize = 100
-	var contents []redisstore.RedisMessageContent
+
+	ctx = tenant.With(ctx, message.TenantID)
+
+	sendCtx, cancel := s.inflight.sendContext(ctx)
+	defer cancel()
+
+	suppressed, err := s.isSuppressed(ctx, message.Recipient)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CronSendMessage",
+			"method": "IsSuppressed",
+		})
+
+		return
+	}
+
+	if suppressed {
+		err = s.ps.UpdateMessageStatus(ctx, message.ID, postgrestore.MessageStatusSuppressed)
+		if err != nil {
+			s.log(err, map[string]interface{}{
+				"action": "CronSendMessage",
+				"method": "UpdateMessageStatus",
+			})
+		}
+
+		return
+	}
+
+	// chunks accepted by the provider in an earlier run are kept in redis until the message is marked as sent and are not
+	// sent again
+	var sending redisstore.RedisMessage
+	if err := s.rs.Get(ctx, sendingKey(message.ID), &sending); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CronSendMessage",
+			"method": "GetSendingProgress",
+		})
+
+		return
+	}
 
 	chunks := splitMessageContent(message.Content, maxMessageCharacterSize)
+	attempt := postgrestore.MessageAttempt{
+		MessageID: message.ID,
+		StartedAt: time.Now(),
+	}
 
-	for _, chunk := range chunks {
-		res, err := s.hc.SendMessage(ctx, hookclient.Message{
+	for i, chunk := range chunks {
+		// chunks are sent in order, the ones accepted in an earlier run are the first ones
+		if i < len(sending.Contents) {
+			continue
+		}
+
+		// a campaign message cancelled while its chunks are sent, i.e. its campaign is aborted, is not sent further and
+		// is left as is by MarkMessageSent
+		if message.CampaignID != nil && i > 0 && !s.stillSending(ctx, message.ID) {
+			break
+		}
+
+		res, err := s.hc.SendMessage(sendCtx, hookclient.Message{
 			To:      message.Recipient,
 			Content: chunk,
 		})
 
 		if err != nil {
+			// an aborted send is not a failure of the message
+			if sendCtx.Err() != nil {
+				return
+			}
+
 			s.log(err, map[string]interface{}{
 				"action": "CronSendMessage",
 				"method": "SendMessage",
 			})
 
+			attempt.Error = err.Error()
+			s.recordAttempt(ctx, attempt)
+
+			if err := s.ps.RecordMessageFailure(ctx, message.ID, s.maxAttempts); err != nil {
+				s.log(err, map[string]interface{}{
+					"action": "CronSendMessage",
+					"method": "RecordMessageFailure",
+				})
+			}
+
 			return
 		}
 
-		contents = append(contents, redisstore.RedisMessageContent{
+		sending.Contents = append(sending.Contents, redisstore.RedisMessageContent{
 			MessageId:   res.MessageID,
 			SendingTime: time.Now(),
 			Content:     chunk,
 		})
+		attempt.ChunksSent++
+
+		// the progress is saved right away so that a run stopped before the message is marked as sent does not resend
+		// the chunk
+		if err := s.rs.Set(ctx, sendingKey(message.ID), sending); err != nil {
+			s.log(err, map[string]interface{}{
+				"action": "CronSendMessage",
+				"method": "SetSendingProgress",
+			})
+
+			s.recordAttempt(ctx, attempt)
+
+			return
+		}
+	}
+
+	s.recordAttempt(ctx, attempt)
+
+	deliveries := make([]postgrestore.MessageDelivery, 0, len(sending.Contents))
+	for i, content := range sending.Contents {
+		deliveries = append(deliveries, postgrestore.MessageDelivery{
+			MessageID:         message.ID,
+			ChunkIndex:        i,
+			ProviderMessageID: content.MessageId,
+			Content:           content.Content,
+			SentAt:            content.SendingTime,
+		})
 	}
 
-	rsKey := fmt.Sprintf("%v", message.ID)
-	rsValue := redisstore.RedisMessage{Contents: contents}
-	err := s.rs.Set(rsKey, rsValue)
+	// the chunks are stored with the status and the outbox event, which is projected into redis by RelayOutbox
+	err = s.ps.MarkMessageSent(ctx, message.ID, deliveries)
 	if err != nil {
 		s.log(err, map[string]interface{}{
 			"action": "CronSendMessage",
-			"method": "Redis Set",
+			"method": "MarkMessageSent",
+		})
+
+		return
+	}
+
+	if err := s.rs.Del(ctx, sendingKey(message.ID)); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "CronSendMessage",
+			"method": "DelSendingProgress",
 		})
 	}
+}
 
-	err = s.ps.UpdateMessageStatusToSent(ctx, message.ID)
+// stillSending reports whether a message is still sending, a message whose status can not be read is assumed to be
+func (s *RestService) stillSending(ctx context.Context, id int64) bool {
+	message, err := s.ps.FetchMessage(ctx, id)
 	if err != nil {
 		s.log(err, map[string]interface{}{
 			"action": "CronSendMessage",
-			"method": "UpdateMessageStatusToSent",
+			"method": "FetchMessage",
 		})
+
+		return true
+	}
+
+	return message != nil && message.Status == postgrestore.MessageStatusSending
+}
+
+// normalizeRecipient returns recipient in E.164 format using the default region for national numbers
+func (s *RestService) normalizeRecipient(recipient string) (string, *rest.APIError) {
+	normalized, err := phone.Normalize(recipient, s.region)
+	if err != nil {
+		return "", &rest.APIError{
+			Message: "validation failed, field: recipient, " + err.Error(),
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
 	}
+
+	return normalized, nil
 }
 
 func (s *RestService) log(err error, additionalParams map[string]interface{}) {
@@ -236,6 +704,16 @@ func (s *RestService) log(err error, additionalParams map[string]interface{}) {
 	_ = level.Error(s.l).Log(logParams...)
 }
 
+// messageKey returns redis key of the sent chunks of a message
+func messageKey(id int64) string {
+	return fmt.Sprintf("message:%d", id)
+}
+
+// sendingKey returns redis key of the chunks of a sending message accepted by the provider
+func sendingKey(id int64) string {
+	return fmt.Sprintf("sending:%d", id)
+}
+
 func splitMessageContent(content string, maxMessageCharacterSize int) []string {
 	var chunks []string
 
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+	redisstore "notify-hub-backend/internal/store/redis"
+)
+
+// AddSuppression returns add suppression
+// swagger:operation POST /suppressions addSuppressionRequest
+// ---
+// summary: Add Suppression
+// description: Adds recipient to suppression list, messages of suppressed recipients are not sent
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/addSuppressionResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) AddSuppression(ctx context.Context, req rest.AddSuppressionRequest) rest.AddSuppressionResponse {
+	res := rest.AddSuppressionResponse{}
+
+	recipient, apiErr := s.normalizeRecipient(req.Recipient)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	suppression, err := s.addSuppression(ctx, recipient, req.Reason, postgrestore.SuppressionSourceAPI)
+	if err != nil {
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	res.Data = toSuppressionData(*suppression)
+
+	return res
+}
+
+// RemoveSuppression returns remove suppression
+// swagger:operation DELETE /suppressions/{recipient} removeSuppressionRequest
+// ---
+// summary: Remove Suppression
+// description: Removes recipient from suppression list
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/removeSuppressionResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) RemoveSuppression(ctx context.Context, req rest.RemoveSuppressionRequest) rest.RemoveSuppressionResponse {
+	res := rest.RemoveSuppressionResponse{}
+
+	recipient, apiErr := s.normalizeRecipient(req.Recipient)
+	if apiErr != nil {
+		res.Result = apiErr
+		return res
+	}
+
+	if err := s.removeSuppression(ctx, recipient); err != nil {
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	res.Data = &rest.RemoveSuppressionData{
+		Recipient: recipient,
+	}
+
+	return res
+}
+
+// FetchSuppressions returns fetch suppressions
+// swagger:operation GET /suppressions fetchSuppressionsRequest
+// ---
+// summary: Fetch Suppressions
+// description: Returns suppressed recipients with reasons
+// responses:
+//
+//	  200:
+//		  $ref: "#/responses/fetchSuppressionsResponse"
+//	  default:
+//		  $ref: "#/responses/errorResponse"
+func (s *RestService) FetchSuppressions(ctx context.Context, _ rest.FetchSuppressionsRequest) rest.FetchSuppressionsResponse {
+	res := rest.FetchSuppressionsResponse{}
+
+	suppressions, err := s.ps.FetchSuppressions(ctx)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "FetchSuppressions",
+			"method": "FetchSuppressions",
+		})
+
+		res.Result = &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+
+		return res
+	}
+
+	data := make([]rest.SuppressionData, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		data = append(data, *toSuppressionData(suppression))
+	}
+
+	res.Data = &rest.FetchSuppressionsData{
+		Suppressions: data,
+	}
+
+	return res
+}
+
+func (s *RestService) addSuppression(ctx context.Context, recipient, reason, source string) (*postgrestore.Suppression, error) {
+	suppression, err := s.ps.AddSuppression(ctx, postgrestore.Suppression{
+		Recipient: recipient,
+		Reason:    reason,
+		Source:    source,
+	})
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "AddSuppression",
+			"method": "AddSuppression",
+		})
+
+		return nil, err
+	}
+
+	s.invalidateSuppression(ctx, recipient)
+
+	return suppression, nil
+}
+
+func (s *RestService) removeSuppression(ctx context.Context, recipient string) error {
+	if err := s.ps.RemoveSuppression(ctx, recipient); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "RemoveSuppression",
+			"method": "RemoveSuppression",
+		})
+
+		return err
+	}
+
+	s.invalidateSuppression(ctx, recipient)
+
+	return nil
+}
+
+// isSuppressed reports whether the recipient is suppressed, reading through the redis cache
+func (s *RestService) isSuppressed(ctx context.Context, recipient string) (bool, error) {
+	rsKey := suppressionKey(recipient)
+
+	var cached redisstore.RedisSuppression
+	if err := s.rs.Get(ctx, rsKey, &cached); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "IsSuppressed",
+			"method": "Redis Get",
+		})
+	}
+
+	if cached.Recipient != "" {
+		return cached.Suppressed, nil
+	}
+
+	suppression, err := s.ps.FetchSuppression(ctx, recipient)
+	if err != nil {
+		return false, err
+	}
+
+	rsValue := redisstore.RedisSuppression{
+		Recipient:  recipient,
+		Suppressed: suppression != nil,
+	}
+
+	if suppression != nil {
+		rsValue.Reason = suppression.Reason
+	}
+
+	if err := s.rs.Set(ctx, rsKey, rsValue); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "IsSuppressed",
+			"method": "Redis Set",
+		})
+	}
+
+	return rsValue.Suppressed, nil
+}
+
+func (s *RestService) invalidateSuppression(ctx context.Context, recipient string) {
+	if err := s.rs.Del(ctx, suppressionKey(recipient)); err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "InvalidateSuppression",
+			"method": "Redis Del",
+		})
+	}
+}
+
+func suppressionKey(recipient string) string {
+	return fmt.Sprintf("suppression:%s", recipient)
+}
+
+func toSuppressionData(suppression postgrestore.Suppression) *rest.SuppressionData {
+	return &rest.SuppressionData{
+		Recipient: suppression.Recipient,
+		Reason:    suppression.Reason,
+		Source:    suppression.Source,
+		CreatedAt: suppression.CreatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func TestIsSuppressedReadsThroughCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		suppressed bool
+	}{
+		{name: "suppressed", suppressed: true},
+		{name: "not suppressed", suppressed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			if tt.suppressed {
+				ps.suppressions["+905325008081"] = postgrestore.Suppression{Recipient: "+905325008081"}
+			}
+
+			s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+			for i := 0; i < 2; i++ {
+				got, err := s.isSuppressed(context.Background(), "+905325008081")
+				if err != nil {
+					t.Fatalf("isSuppressed error = %v", err)
+				}
+
+				if got != tt.suppressed {
+					t.Fatalf("isSuppressed = %v, want %v", got, tt.suppressed)
+				}
+			}
+
+			if ps.suppressionReads != 1 {
+				t.Errorf("read the suppression %d times, want once and then from the cache", ps.suppressionReads)
+			}
+		})
+	}
+}
+
+func TestSuppressionChangesInvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(newFakePostgres(), newFakeRedis(), &fakeHook{})
+
+	if suppressed, _ := s.isSuppressed(ctx, "+905325008081"); suppressed {
+		t.Fatal("isSuppressed = true before the recipient is added")
+	}
+
+	res := s.AddSuppression(ctx, rest.AddSuppressionRequest{Recipient: "+905325008081", Reason: "complaint"})
+	if res.Result != nil {
+		t.Fatalf("AddSuppression result = %+v", res.Result)
+	}
+
+	if res.Data.Source != postgrestore.SuppressionSourceAPI {
+		t.Errorf("AddSuppression source = %q, want %q", res.Data.Source, postgrestore.SuppressionSourceAPI)
+	}
+
+	if suppressed, _ := s.isSuppressed(ctx, "+905325008081"); !suppressed {
+		t.Fatal("isSuppressed = false after the recipient is added")
+	}
+
+	if res := s.RemoveSuppression(ctx, rest.RemoveSuppressionRequest{Recipient: "+905325008081"}); res.Result != nil {
+		t.Fatalf("RemoveSuppression result = %+v", res.Result)
+	}
+
+	if suppressed, _ := s.isSuppressed(ctx, "+905325008081"); suppressed {
+		t.Error("isSuppressed = true after the recipient is removed")
+	}
+}
+
+func TestProcessSendingMessageSkipsSuppressed(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipient  string
+		status     string
+		sentChunks int
+	}{
+		{name: "suppressed recipient", recipient: "+905325008081", status: postgrestore.MessageStatusSuppressed},
+		{name: "other recipient", recipient: "+905325008082", status: postgrestore.MessageStatusSent, sentChunks: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newFakePostgres()
+			ps.suppressions["+905325008081"] = postgrestore.Suppression{Recipient: "+905325008081"}
+			hc := &fakeHook{}
+			s := newTestService(ps, newFakeRedis(), hc)
+
+			s.processSendingMessage(context.Background(), postgrestore.Message{ID: 1, Recipient: tt.recipient, Content: "hello"})
+
+			if ps.statuses[1] != tt.status {
+				t.Errorf("status = %q, want %q", ps.statuses[1], tt.status)
+			}
+
+			if len(hc.sent) != tt.sentChunks {
+				t.Errorf("sent %d chunks, want %d", len(hc.sent), tt.sentChunks)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"text/template"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+// built-in template variables, they take precedence over contact attributes with the same name
+const (
+	templateVariableName  = "name"
+	templateVariablePhone = "phone"
+)
+
+const emptyGroupError = "group has no contacts"
+
+// buildMessages returns a message per recipient, when groupID is set the group is expanded into a message per member
+// contact instead. The content is a template for group sends and when template is set, it is sent as it is otherwise
+// so that every recipient of a request is treated the same whether it is a contact or not.
+func (s *RestService) buildMessages(ctx context.Context, content string, recipients []string, groupID int64, template bool) ([]postgrestore.Message, *rest.APIError) {
+	if groupID != 0 {
+		return s.buildGroupMessages(ctx, content, groupID)
+	}
+
+	return s.buildRecipientMessages(ctx, content, recipients, template)
+}
+
+// buildGroupMessages returns a message per member contact of group with content rendered using the contact's variables
+func (s *RestService) buildGroupMessages(ctx context.Context, content string, groupID int64) ([]postgrestore.Message, *rest.APIError) {
+	tmpl, apiErr := parseTemplate(content)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	group, err := s.ps.FetchGroup(ctx, groupID)
+	if err != nil || group == nil {
+		return nil, storeError(err, groupNotFoundError)
+	}
+
+	if len(group.Contacts) == 0 {
+		return nil, &rest.APIError{
+			Message: emptyGroupError,
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+	}
+
+	messages := make([]postgrestore.Message, 0, len(group.Contacts))
+	for _, contact := range group.Contacts {
+		message, apiErr := renderMessage(tmpl, contact)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+
+		message.GroupID = &group.ID
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// buildRecipientMessages returns a message per recipient, when template is set the content is rendered using the
+// variables of the contact with the recipient's phone, or with the phone only for recipients that are not contacts
+func (s *RestService) buildRecipientMessages(ctx context.Context, content string, recipients []string, template bool) ([]postgrestore.Message, *rest.APIError) {
+	phones := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		phone, apiErr := s.normalizeRecipient(recipient)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+
+		phones = append(phones, phone)
+	}
+
+	messages := make([]postgrestore.Message, 0, len(phones))
+	if !template {
+		for _, phone := range phones {
+			messages = append(messages, postgrestore.Message{
+				Recipient: phone,
+				Content:   content,
+				Status:    postgrestore.MessageStatusQueued,
+			})
+		}
+
+		return messages, nil
+	}
+
+	tmpl, apiErr := parseTemplate(content)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	contacts, err := s.ps.FetchContactsByPhones(ctx, phones)
+	if err != nil {
+		s.log(err, map[string]interface{}{
+			"action": "BuildMessages",
+			"method": "FetchContactsByPhones",
+		})
+
+		return nil, &rest.APIError{
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+			Type:    rest.ErrorTypeInternal,
+		}
+	}
+
+	byPhone := make(map[string]postgrestore.Contact, len(contacts))
+	for _, contact := range contacts {
+		byPhone[contact.Phone] = contact
+	}
+
+	for _, phone := range phones {
+		contact, ok := byPhone[phone]
+		if !ok {
+			contact = postgrestore.Contact{Phone: phone}
+		}
+
+		message, apiErr := renderMessage(tmpl, contact)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// parseTemplate returns template of content, missing variables are rendered as empty strings
+func parseTemplate(content string) (*template.Template, *rest.APIError) {
+	tmpl, err := template.New("content").Option("missingkey=zero").Parse(content)
+	if err != nil {
+		return nil, &rest.APIError{
+			Message: "validation failed, field: content, " + err.Error(),
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+	}
+
+	return tmpl, nil
+}
+
+// renderMessage returns queued message of contact with content rendered using contact's template variables
+func renderMessage(tmpl *template.Template, contact postgrestore.Contact) (postgrestore.Message, *rest.APIError) {
+	variables := make(map[string]string, len(contact.Attributes)+2)
+	for k, v := range contact.Attributes {
+		variables[k] = v
+	}
+
+	variables[templateVariableName] = contact.Name
+	variables[templateVariablePhone] = contact.Phone
+
+	var content strings.Builder
+	if err := tmpl.Execute(&content, variables); err != nil {
+		return postgrestore.Message{}, &rest.APIError{
+			Message: "validation failed, field: content, " + err.Error(),
+			Code:    http.StatusBadRequest,
+			Type:    rest.ErrorTypeValidation,
+		}
+	}
+
+	return postgrestore.Message{
+		Recipient: contact.Phone,
+		Content:   content.String(),
+		Status:    postgrestore.MessageStatusQueued,
+	}, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	rest "notify-hub-backend"
+	postgrestore "notify-hub-backend/internal/store/postgres"
+)
+
+func newTemplatePostgres() *fakePostgres {
+	ps := newFakePostgres()
+	ps.contacts = []postgrestore.Contact{
+		{ID: 1, Name: "Ayse", Phone: "+905325008081", Attributes: postgrestore.Attributes{"team": "payments"}},
+		{ID: 2, Name: "Mehmet", Phone: "+905325008082"},
+	}
+	ps.groups[3] = postgrestore.Group{ID: 3, Contacts: ps.contacts}
+	ps.groups[4] = postgrestore.Group{ID: 4}
+
+	return ps
+}
+
+func TestBuildMessages(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          rest.CreateMessageRequest
+		want         []string
+		contactReads int
+	}{
+		{
+			name: "recipient contact without template",
+			req:  rest.CreateMessageRequest{Recipient: "05325008081", Content: "Hi {{.name}}"},
+			want: []string{"Hi {{.name}}"},
+		},
+		{
+			name: "recipient without template",
+			req:  rest.CreateMessageRequest{Recipient: "05325008083", Content: "Hi {{.name}}"},
+			want: []string{"Hi {{.name}}"},
+		},
+		{
+			name:         "recipient contact with template",
+			req:          rest.CreateMessageRequest{Recipient: "05325008081", Content: "Hi {{.name}} of {{.team}}", Template: true},
+			want:         []string{"Hi Ayse of payments"},
+			contactReads: 1,
+		},
+		{
+			name:         "recipient with template",
+			req:          rest.CreateMessageRequest{Recipient: "05325008083", Content: "Hi {{.name}}{{.phone}}", Template: true},
+			want:         []string{"Hi +905325008083"},
+			contactReads: 1,
+		},
+		{
+			name: "group",
+			req:  rest.CreateMessageRequest{GroupID: 3, Content: "Hi {{.name}} of {{.team}}"},
+			want: []string{"Hi Ayse of payments", "Hi Mehmet of "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := newTemplatePostgres()
+			s := newTestService(ps, newFakeRedis(), &fakeHook{})
+
+			messages, apiErr := s.buildMessages(context.Background(), tt.req.Content, []string{tt.req.Recipient}, tt.req.GroupID, tt.req.Template)
+			if apiErr != nil {
+				t.Fatalf("buildMessages error = %+v", apiErr)
+			}
+
+			if len(messages) != len(tt.want) {
+				t.Fatalf("built %d messages, want %d", len(messages), len(tt.want))
+			}
+
+			for i, message := range messages {
+				if message.Content != tt.want[i] {
+					t.Errorf("message %d content = %q, want %q", i, message.Content, tt.want[i])
+				}
+
+				if message.Status != postgrestore.MessageStatusQueued {
+					t.Errorf("message %d status = %q, want %q", i, message.Status, postgrestore.MessageStatusQueued)
+				}
+
+				if (message.GroupID != nil) != (tt.req.GroupID != 0) {
+					t.Errorf("message %d group = %v, want %d", i, message.GroupID, tt.req.GroupID)
+				}
+			}
+
+			if ps.contactReads != tt.contactReads {
+				t.Errorf("read contacts %d times, want %d", ps.contactReads, tt.contactReads)
+			}
+		})
+	}
+}
+
+func TestBuildMessagesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  rest.CreateMessageRequest
+		code int
+	}{
+		{name: "invalid template", req: rest.CreateMessageRequest{Recipient: "05325008081", Content: "Hi {{.name", Template: true}, code: http.StatusBadRequest},
+		{name: "invalid group template", req: rest.CreateMessageRequest{GroupID: 3, Content: "Hi {{.name"}, code: http.StatusBadRequest},
+		{name: "missing group", req: rest.CreateMessageRequest{GroupID: 5, Content: "Hi"}, code: http.StatusNotFound},
+		{name: "empty group", req: rest.CreateMessageRequest{GroupID: 4, Content: "Hi"}, code: http.StatusBadRequest},
+		{name: "invalid recipient", req: rest.CreateMessageRequest{Recipient: "123", Content: "Hi"}, code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(newTemplatePostgres(), newFakeRedis(), &fakeHook{})
+
+			_, apiErr := s.buildMessages(context.Background(), tt.req.Content, []string{tt.req.Recipient}, tt.req.GroupID, tt.req.Template)
+			if apiErr == nil || apiErr.Code != tt.code {
+				t.Errorf("buildMessages error = %+v, want code %d", apiErr, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package postgrestore
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// APIKeyTouchInterval limits how often the last use of an api key is written.
+const APIKeyTouchInterval = time.Minute
+
+// Scopes represents the permissions of an api key stored as jsonb.
+type Scopes []string
+
+// Value implements driver.Valuer interface.
+func (s Scopes) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner interface.
+func (s *Scopes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*s = Scopes{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported scopes type %T", value)
+	}
+
+	return json.Unmarshal(b, s)
+}
+
+// APIKey represents an api key, the key itself is not stored but its hash and its first characters to tell keys apart.
+type APIKey struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string     `gorm:"not null;default:default;index" json:"tenantId"`
+	Name       string     `gorm:"not null" json:"name"`
+	Prefix     string     `gorm:"not null" json:"prefix"`
+	KeyHash    string     `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     Scopes     `gorm:"type:jsonb;not null;default:'[]'" json:"scopes"`
+	CreatedBy  string     `gorm:"not null;default:''" json:"createdBy"`
+	CreatedAt  time.Time  `gorm:"not null" json:"createdAt"`
+	RotatedAt  *time.Time `json:"rotatedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// InsertAPIKey inserts an api key of the tenant of ctx, callers authenticated with the key act on behalf of the tenant.
+func (s *store) InsertAPIKey(ctx context.Context, key *APIKey) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	key.TenantID = tenantID
+
+	if err := s.db.WithContext(ctx).Create(key).Error; err != nil {
+		return fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAPIKeys retrieves every api key of the tenant of ctx, revoked ones included.
+func (s *store) FetchAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Order("id ASC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// FetchAPIKeyByHash retrieves the api key with the given hash unless it is revoked, keys of every tenant are looked up
+// since the tenant of a request is known once its key is.
+func (s *store) FetchAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	var key APIKey
+	err := s.db.WithContext(ctx).Where("key_hash = ? AND revoked_at IS NULL", hash).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// RotateAPIKey replaces the hash and the prefix of an api key that is not revoked, the previous key stops working.
+func (s *store) RotateAPIKey(ctx context.Context, id int64, prefix, hash string) (*APIKey, error) {
+	var key APIKey
+	res := s.db.WithContext(ctx).Model(&key).Scopes(byTenant(ctx)).Clauses(clause.Returning{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"prefix": prefix, "key_hash": hash, "rotated_at": time.Now()})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to rotate api key: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w, api key %d does not exist or is revoked", ErrNotFound, id)
+	}
+
+	return &key, nil
+}
+
+// RevokeAPIKey revokes an api key, revoking a revoked key keeps its revocation time.
+func (s *store) RevokeAPIKey(ctx context.Context, id int64) (*APIKey, error) {
+	var key APIKey
+	res := s.db.WithContext(ctx).Model(&key).Scopes(byTenant(ctx)).Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, now())"))
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &key, nil
+}
+
+// TouchAPIKey records the last use of an api key, at most once per APIKeyTouchInterval.
+func (s *store) TouchAPIKey(ctx context.Context, id int64) error {
+	err := s.db.WithContext(ctx).Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, time.Now().Add(-APIKeyTouchInterval)).
+		Update("last_used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to touch api key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// campaign statuses
+const (
+	CampaignStatusActive  = "active"
+	CampaignStatusPaused  = "paused"
+	CampaignStatusAborted = "aborted"
+)
+
+// Campaign represents the broadcast campaign model.
+type Campaign struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID          string    `gorm:"not null;default:default;index:idx_campaigns_tenant_id" json:"tenantId"`
+	Name              string    `gorm:"not null" json:"name"`
+	Content           string    `gorm:"not null" json:"content"`
+	GroupID           *int64    `json:"groupId"`
+	AudienceSize      int       `gorm:"not null" json:"audienceSize"`
+	Status            string    `gorm:"not null;default:active;index" json:"status"`
+	StartAt           time.Time `gorm:"not null" json:"startAt"`
+	ThrottlePerMinute int       `gorm:"not null;default:0" json:"throttlePerMinute"`
+	CreatedAt         time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt         time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// CampaignProgress represents message counts of a campaign keyed by message status.
+type CampaignProgress map[string]int
+
+// InsertCampaign inserts a campaign of the tenant of ctx and its messages in a single transaction.
+func (s *store) InsertCampaign(ctx context.Context, campaign *Campaign, messages []Message) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert campaign: %w", err)
+	}
+
+	campaign.TenantID = tenantID
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return fmt.Errorf("failed to insert campaign: %w", err)
+		}
+
+		for i := range messages {
+			messages[i].CampaignID = &campaign.ID
+			messages[i].TenantID = campaign.TenantID
+		}
+
+		if err := tx.CreateInBatches(messages, 500).Error; err != nil {
+			return fmt.Errorf("failed to insert campaign messages: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateCampaignStatus moves a campaign to status if its current status is one of from, queued and sending messages of
+// aborted campaigns are cancelled in the same transaction and their number is returned.
+func (s *store) UpdateCampaignStatus(ctx context.Context, id int64, status string, from []string) (int, error) {
+	var cancelled int
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Campaign{}).Scopes(byTenant(ctx)).Where("id = ? AND status IN ?", id, from).Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update campaign status: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w, campaign %d is not %v", ErrNotFound, id, from)
+		}
+
+		if status != CampaignStatusAborted {
+			return nil
+		}
+
+		res = tx.Model(&Message{}).Scopes(byTenant(ctx)).
+			Where("campaign_id = ? AND status IN ?", id, []string{MessageStatusQueued, MessageStatusSending}).
+			Updates(map[string]interface{}{
+				"status":     MessageStatusCancelled,
+				"claimed_at": nil,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("failed to cancel campaign messages: %w", res.Error)
+		}
+
+		cancelled = int(res.RowsAffected)
+
+		return nil
+	})
+
+	return cancelled, err
+}
+
+// FetchCampaign retrieves a campaign by its ID, it returns nil if the campaign does not exist.
+func (s *store) FetchCampaign(ctx context.Context, id int64) (*Campaign, error) {
+	var campaign Campaign
+	err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).First(&campaign, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// FetchCampaigns retrieves all campaigns, newest first.
+func (s *store) FetchCampaigns(ctx context.Context) ([]Campaign, error) {
+	var campaigns []Campaign
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Order("id DESC").Find(&campaigns).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// FetchCampaignProgress retrieves message counts of a campaign grouped by message status.
+func (s *store) FetchCampaignProgress(ctx context.Context, id int64) (CampaignProgress, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+
+	err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Select("status, COUNT(*) AS count").Where("campaign_id = ?", id).Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaign progress: %w", err)
+	}
+
+	progress := make(CampaignProgress, len(rows))
+	for _, row := range rows {
+		progress[row.Status] = row.Count
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,180 @@
+package postgrestore
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Attributes represents free-form contact attributes stored as jsonb.
+type Attributes map[string]string
+
+// Value implements driver.Valuer interface.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner interface.
+func (a *Attributes) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Attributes{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("unsupported attributes type %T", value)
+	}
+
+	return json.Unmarshal(b, a)
+}
+
+// Contact represents the contact model.
+type Contact struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string     `gorm:"not null;default:default;uniqueIndex:idx_contacts_tenant_phone" json:"tenantId"`
+	Name       string     `gorm:"not null" json:"name"`
+	Phone      string     `gorm:"not null;uniqueIndex:idx_contacts_tenant_phone" json:"phone"`
+	Attributes Attributes `gorm:"type:jsonb;not null;default:'{}'" json:"attributes"`
+	CreatedAt  time.Time  `gorm:"not null" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"not null" json:"updatedAt"`
+}
+
+// InsertContact inserts a contact of the tenant of ctx.
+func (s *store) InsertContact(ctx context.Context, contact *Contact) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert contact: %w", err)
+	}
+
+	contact.TenantID = tenantID
+
+	if err := s.db.WithContext(ctx).Create(contact).Error; err != nil {
+		return fmt.Errorf("failed to insert contact: %w", err)
+	}
+
+	return nil
+}
+
+// InsertContacts inserts contacts of the tenant of ctx in a single statement, contacts whose phone already exists in the
+// tenant are skipped.
+func (s *store) InsertContacts(ctx context.Context, contacts []Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert contacts: %w", err)
+	}
+
+	for i := range contacts {
+		contacts[i].TenantID = tenantID
+	}
+
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "phone"}},
+		DoNothing: true,
+	}).Create(&contacts).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert contacts: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateContact updates name, phone and attributes of a contact.
+func (s *store) UpdateContact(ctx context.Context, contact *Contact) error {
+	res := s.db.WithContext(ctx).Model(contact).Scopes(byTenant(ctx)).Select("name", "phone", "attributes", "updated_at").Updates(contact)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update contact: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteContact deletes a contact and its group memberships.
+func (s *store) DeleteContact(ctx context.Context, id int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(byTenant(ctx)).Select("id").First(&Contact{}, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch contact: %w", err)
+		}
+
+		if err := tx.Exec("DELETE FROM group_contacts WHERE contact_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete contact memberships: %w", err)
+		}
+
+		res := tx.Delete(&Contact{}, id)
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete contact: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// FetchContact retrieves a contact by its ID, it returns nil if the contact does not exist.
+func (s *store) FetchContact(ctx context.Context, id int64) (*Contact, error) {
+	var contact Contact
+	err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).First(&contact, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch contact: %w", err)
+	}
+
+	return &contact, nil
+}
+
+// FetchContactsByPhones retrieves contacts of the tenant of ctx whose phone number is one of phones, phones without a contact are skipped.
+func (s *store) FetchContactsByPhones(ctx context.Context, phones []string) ([]Contact, error) {
+	if len(phones) == 0 {
+		return nil, nil
+	}
+
+	var contacts []Contact
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("phone IN ?", phones).Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// FetchContacts retrieves all contacts ordered by ID.
+func (s *store) FetchContacts(ctx context.Context) ([]Contact, error) {
+	var contacts []Contact
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Order("id ASC").Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch contacts: %w", err)
+	}
+
+	return contacts, nil
+}
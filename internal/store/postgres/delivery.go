@@ -0,0 +1,226 @@
+package postgrestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// delivery receipt statuses
+const (
+	ReceiptStatusDelivered = "delivered"
+)
+
+// MessageAttempt represents a send attempt of a message.
+type MessageAttempt struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	MessageID  int64     `gorm:"not null;index" json:"messageId"`
+	StartedAt  time.Time `gorm:"not null" json:"startedAt"`
+	FinishedAt time.Time `gorm:"not null" json:"finishedAt"`
+	ChunksSent int       `gorm:"not null" json:"chunksSent"`
+	Error      string    `json:"error"`
+}
+
+// MessageDelivery represents a chunk of a message accepted by the provider.
+type MessageDelivery struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	MessageID         int64     `gorm:"not null;index;uniqueIndex:idx_message_deliveries_message_chunk" json:"messageId"`
+	ChunkIndex        int       `gorm:"not null;uniqueIndex:idx_message_deliveries_message_chunk" json:"chunkIndex"`
+	ProviderMessageID string    `gorm:"not null;index" json:"providerMessageId"`
+	Content           string    `gorm:"not null" json:"content"`
+	SentAt            time.Time `gorm:"not null" json:"sentAt"`
+}
+
+// DeliveryReceipt represents a delivery report of a chunk received from the provider.
+type DeliveryReceipt struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProviderMessageID string    `gorm:"not null;index" json:"providerMessageId"`
+	MessageID         *int64    `gorm:"index" json:"messageId"`
+	Status            string    `gorm:"not null" json:"status"`
+	Error             string    `json:"error"`
+	ReceivedAt        time.Time `gorm:"not null" json:"receivedAt"`
+}
+
+// InsertMessageAttempt inserts a send attempt of a message of the tenant of ctx.
+func (s *store) InsertMessageAttempt(ctx context.Context, attempt *MessageAttempt) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := messageExists(ctx, tx, attempt.MessageID); err != nil {
+			return err
+		}
+
+		if err := tx.Create(attempt).Error; err != nil {
+			return fmt.Errorf("failed to insert message attempt: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MarkMessageSent marks a sending message as sent, inserts its chunks accepted by the provider and writes a message sent
+// outbox event carrying them in one transaction. The message is marked as delivered at once if receipts of all its
+// chunks arrived while it was sent, a message that is no longer sending, e.g. cancelled, is left as is. The message
+// must be of the tenant of ctx, which the event belongs to.
+func (s *store) MarkMessageSent(ctx context.Context, id int64, deliveries []MessageDelivery) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to mark message sent: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Message{}).Scopes(byTenant(ctx)).Where("id = ? AND status = ?", id, MessageStatusSending).Updates(map[string]interface{}{
+			"status":  MessageStatusSent,
+			"sent_at": time.Now(),
+		})
+		if res.Error != nil {
+			return fmt.Errorf("failed to update message status: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		if len(deliveries) > 0 {
+			if err := tx.Create(&deliveries).Error; err != nil {
+				return fmt.Errorf("failed to insert message deliveries: %w", err)
+			}
+		}
+
+		payload, err := json.Marshal(deliveries)
+		if err != nil {
+			return fmt.Errorf("failed to encode outbox event: %w", err)
+		}
+
+		err = tx.Create(&OutboxEvent{
+			TenantID:    tenantID,
+			Topic:       OutboxTopicMessageSent,
+			AggregateID: id,
+			Payload:     payload,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox event: %w", err)
+		}
+
+		return promoteDelivered(tx, id)
+	})
+}
+
+// FetchMessageAttempts retrieves send attempts of a message of the tenant of ctx in order.
+func (s *store) FetchMessageAttempts(ctx context.Context, messageID int64) ([]MessageAttempt, error) {
+	var attempts []MessageAttempt
+	if err := s.db.WithContext(ctx).Scopes(byMessageTenant(ctx)).Where("message_id = ?", messageID).Order("id ASC").Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch message attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// FetchMessageDeliveries retrieves chunks of a message of the tenant of ctx accepted by the provider in order.
+func (s *store) FetchMessageDeliveries(ctx context.Context, messageID int64) ([]MessageDelivery, error) {
+	var deliveries []MessageDelivery
+	if err := s.db.WithContext(ctx).Scopes(byMessageTenant(ctx)).Where("message_id = ?", messageID).Order("chunk_index ASC, id ASC").Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch message deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// FetchDeliveriesOfMessages retrieves chunks of the given messages of the tenant of ctx accepted by the provider in
+// order.
+func (s *store) FetchDeliveriesOfMessages(ctx context.Context, messageIDs []int64) ([]MessageDelivery, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []MessageDelivery
+	if err := s.db.WithContext(ctx).Scopes(byMessageTenant(ctx)).Where("message_id IN ?", messageIDs).Order("message_id ASC, chunk_index ASC, id ASC").Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch message deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// FetchDeliveryReceipts retrieves delivery receipts of a message of the tenant of ctx in order.
+func (s *store) FetchDeliveryReceipts(ctx context.Context, messageID int64) ([]DeliveryReceipt, error) {
+	var receipts []DeliveryReceipt
+	if err := s.db.WithContext(ctx).Scopes(byMessageTenant(ctx)).Where("message_id = ?", messageID).Order("id ASC").Find(&receipts).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch delivery receipts: %w", err)
+	}
+
+	return receipts, nil
+}
+
+// RecordDeliveryReceipt inserts a delivery receipt resolving its message from the provider message ID among the messages
+// of the tenant of ctx, the message is marked as delivered once each of its chunks has a delivered receipt. Receipts of
+// the provider carry no tenant, their webhook records them with a tenant.HubWide context.
+func (s *store) RecordDeliveryReceipt(ctx context.Context, receipt *DeliveryReceipt) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var delivery MessageDelivery
+		err := tx.Scopes(byMessageTenant(ctx)).Where("provider_message_id = ?", receipt.ProviderMessageID).First(&delivery).Error
+		if err == nil {
+			receipt.MessageID = &delivery.MessageID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to fetch message delivery: %w", err)
+		}
+
+		if err := tx.Create(receipt).Error; err != nil {
+			return fmt.Errorf("failed to insert delivery receipt: %w", err)
+		}
+
+		if receipt.MessageID == nil || receipt.Status != ReceiptStatusDelivered {
+			return nil
+		}
+
+		return promoteDelivered(tx, *receipt.MessageID)
+	})
+}
+
+// promoteDelivered links receipts received before the chunks of a message were stored to the message, and marks the
+// message as delivered if it is sent and each of its chunks has a delivered receipt.
+func promoteDelivered(tx *gorm.DB, messageID int64) error {
+	err := tx.Model(&DeliveryReceipt{}).
+		Where("message_id IS NULL AND provider_message_id IN (SELECT provider_message_id FROM message_deliveries WHERE message_id = ?)", messageID).
+		Update("message_id", messageID).Error
+	if err != nil {
+		return fmt.Errorf("failed to link delivery receipts: %w", err)
+	}
+
+	var chunks, undelivered int64
+	if err := tx.Model(&MessageDelivery{}).Where("message_id = ?", messageID).Count(&chunks).Error; err != nil {
+		return fmt.Errorf("failed to count message chunks: %w", err)
+	}
+
+	err = tx.Model(&MessageDelivery{}).
+		Where("message_id = ?", messageID).
+		Where("NOT EXISTS (SELECT 1 FROM delivery_receipts r WHERE r.provider_message_id = message_deliveries.provider_message_id AND r.status = ?)", ReceiptStatusDelivered).
+		Count(&undelivered).Error
+	if err != nil {
+		return fmt.Errorf("failed to count undelivered chunks: %w", err)
+	}
+
+	if chunks == 0 || undelivered > 0 {
+		return nil
+	}
+
+	err = tx.Model(&Message{}).Where("id = ? AND status = ?", messageID, MessageStatusSent).Update("status", MessageStatusDelivered).Error
+	if err != nil {
+		return fmt.Errorf("failed to update message status: %w", err)
+	}
+
+	return nil
+}
+
+// messageExists checks in tx that a message of the tenant of ctx exists, it returns ErrNotFound otherwise
+func messageExists(ctx context.Context, tx *gorm.DB, id int64) error {
+	err := tx.Scopes(byTenant(ctx)).Select("id").First(&Message{}, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to fetch message: %w", err)
+	}
+
+	return nil
+}
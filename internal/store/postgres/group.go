@@ -0,0 +1,142 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Group represents the recipient group model.
+type Group struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID    string    `gorm:"not null;default:default;uniqueIndex:idx_groups_tenant_name" json:"tenantId"`
+	Name        string    `gorm:"not null;uniqueIndex:idx_groups_tenant_name" json:"name"`
+	Description string    `json:"description"`
+	Contacts    []Contact `gorm:"many2many:group_contacts" json:"contacts"`
+	CreatedAt   time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// InsertGroup inserts a group of the tenant of ctx with its members, members are contacts of the same tenant.
+func (s *store) InsertGroup(ctx context.Context, group *Group, contactIDs []int64) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert group: %w", err)
+	}
+
+	group.TenantID = tenantID
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Contacts").Create(group).Error; err != nil {
+			return fmt.Errorf("failed to insert group: %w", err)
+		}
+
+		return replaceGroupContacts(ctx, tx, group, contactIDs)
+	})
+}
+
+// UpdateGroup updates name and description of a group and replaces its members.
+func (s *store) UpdateGroup(ctx context.Context, group *Group, contactIDs []int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(group).Scopes(byTenant(ctx)).Omit("Contacts").Select("name", "description", "updated_at").Updates(group)
+		if res.Error != nil {
+			return fmt.Errorf("failed to update group: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return replaceGroupContacts(ctx, tx, group, contactIDs)
+	})
+}
+
+// DeleteGroup deletes a group and its memberships, contacts are kept.
+func (s *store) DeleteGroup(ctx context.Context, id int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(byTenant(ctx)).Select("id").First(&Group{}, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		} else if err != nil {
+			return fmt.Errorf("failed to fetch group: %w", err)
+		}
+
+		if err := tx.Exec("DELETE FROM group_contacts WHERE group_id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete group memberships: %w", err)
+		}
+
+		res := tx.Delete(&Group{}, id)
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete group: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// FetchGroup retrieves a group with its members, it returns nil if the group does not exist.
+func (s *store) FetchGroup(ctx context.Context, id int64) (*Group, error) {
+	var group Group
+	err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Preload("Contacts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("contacts.id ASC")
+	}).First(&group, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// FetchGroups retrieves all groups without their members ordered by ID.
+func (s *store) FetchGroups(ctx context.Context) ([]Group, error) {
+	var groups []Group
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Order("id ASC").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+func replaceGroupContacts(ctx context.Context, tx *gorm.DB, group *Group, contactIDs []int64) error {
+	contacts := make([]Contact, 0, len(contactIDs))
+	if len(contactIDs) > 0 {
+		if err := tx.Scopes(byTenant(ctx)).Where("id IN ?", contactIDs).Find(&contacts).Error; err != nil {
+			return fmt.Errorf("failed to fetch group contacts: %w", err)
+		}
+
+		if len(contacts) != len(uniqueIDs(contactIDs)) {
+			return fmt.Errorf("%w, some of the group contacts do not exist", ErrNotFound)
+		}
+	}
+
+	association := tx.Model(group).Association("Contacts")
+	if len(contacts) == 0 {
+		if err := association.Clear(); err != nil {
+			return fmt.Errorf("failed to clear group contacts: %w", err)
+		}
+	} else if err := association.Replace(contacts); err != nil {
+		return fmt.Errorf("failed to replace group contacts: %w", err)
+	}
+
+	group.Contacts = contacts
+
+	return nil
+}
+
+func uniqueIDs(ids []int64) map[int64]struct{} {
+	unique := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+
+	return unique
+}
@@ -0,0 +1,81 @@
+package postgrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InboundMessage represents the mobile originated reply model.
+type InboundMessage struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID          string    `gorm:"not null;default:default;index:idx_inbound_messages_tenant_recipient;uniqueIndex:idx_inbound_messages_tenant_provider_message_id,where:provider_message_id <> ''" json:"tenantId"`
+	Recipient         string    `gorm:"not null;index:idx_inbound_messages_tenant_recipient" json:"recipient"`
+	Content           string    `gorm:"not null" json:"content"`
+	Keyword           string    `json:"keyword"`
+	ProviderMessageID string    `gorm:"uniqueIndex:idx_inbound_messages_tenant_provider_message_id,where:provider_message_id <> ''" json:"providerMessageId"`
+	ReceivedAt        time.Time `gorm:"not null" json:"receivedAt"`
+}
+
+// RecordInboundMessage inserts a reply received from a recipient for the tenant of ctx and applies its suppression change
+// to the tenant in one transaction, suppression is added when it is set and keyword suppressions of the recipient are
+// removed when resubscribe is set. A reply whose provider message id is already recorded for the tenant is not applied
+// again and false is returned.
+func (s *store) RecordInboundMessage(ctx context.Context, message *InboundMessage, suppression *Suppression, resubscribe bool) (bool, error) {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert inbound message: %w", err)
+	}
+
+	message.TenantID = tenantID
+
+	recorded := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "tenant_id"}, {Name: "provider_message_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "provider_message_id <> ''"}}},
+			DoNothing:   true,
+		}).Create(message)
+		if result.Error != nil {
+			return fmt.Errorf("failed to insert inbound message: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if suppression != nil {
+			if err := upsertSuppression(ctx, tx, suppression); err != nil {
+				return err
+			}
+		}
+
+		if resubscribe {
+			err := tx.Scopes(byTenant(ctx)).Where("recipient = ? AND source = ?", message.Recipient, SuppressionSourceKeyword).Delete(&Suppression{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to remove keyword suppression: %w", err)
+			}
+		}
+
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return recorded, nil
+}
+
+// FetchInboundMessages retrieves replies of a recipient, newest first, and applies a limit.
+func (s *store) FetchInboundMessages(ctx context.Context, recipient string, limit int) ([]InboundMessage, error) {
+	var messages []InboundMessage
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("recipient = ?", recipient).Order("id DESC").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch inbound messages: %w", err)
+	}
+
+	return messages, nil
+}
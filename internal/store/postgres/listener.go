@@ -0,0 +1,119 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+
+	envvars "notify-hub-backend/configs/env-vars"
+)
+
+// MessageQueuedChannel is the notification channel fired by the messages_queued_notify trigger when messages are inserted
+const MessageQueuedChannel = "messages_queued"
+
+// Listener defines behaviors of postgres notification listener
+type Listener interface {
+	Wait(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// ErrListenerClosed is returned by waits of a closed listener
+var ErrListenerClosed = errors.New("listener is closed")
+
+// listener represents a dedicated connection listening on a notification channel. The connection is only used by the
+// goroutine calling Wait, Close cancels the wait in progress and closes the connection once the wait has returned.
+type listener struct {
+	dsn     string
+	channel string
+	conn    *pgx.Conn
+
+	mu      sync.Mutex
+	closed  bool
+	cancel  context.CancelFunc
+	waiting chan struct{}
+}
+
+// NewListener creates and returns postgres notification listener, it connects on the first wait and reconnects on
+// the next wait after a connection failure
+func NewListener(cfg envvars.Postgres, channel string) Listener {
+	return &listener{
+		dsn:     cfg.DSN,
+		channel: channel,
+	}
+}
+
+// Wait blocks until a notification is received on the channel, ctx is done or the listener is closed. Waits must not
+// run concurrently.
+func (l *listener) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ErrListenerClosed
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	waiting := make(chan struct{})
+	l.cancel, l.waiting = cancel, waiting
+	l.mu.Unlock()
+
+	defer func() {
+		cancel()
+
+		l.mu.Lock()
+		l.cancel, l.waiting = nil, nil
+		l.mu.Unlock()
+
+		close(waiting)
+	}()
+
+	if l.conn == nil || l.conn.IsClosed() {
+		conn, err := pgx.Connect(ctx, l.dsn)
+		if err != nil {
+			return fmt.Errorf("failed to connect listener: %w", err)
+		}
+
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+			_ = conn.Close(context.Background())
+			return fmt.Errorf("failed to listen %s: %w", l.channel, err)
+		}
+
+		l.conn = conn
+	}
+
+	if _, err := l.conn.WaitForNotification(ctx); err != nil {
+		_ = l.conn.Close(context.Background())
+		l.conn = nil
+
+		return fmt.Errorf("failed to wait for notification: %w", err)
+	}
+
+	return nil
+}
+
+// Close cancels the wait in progress, waits for it to return and closes the listener connection.
+func (l *listener) Close(ctx context.Context) error {
+	l.mu.Lock()
+	l.closed = true
+	cancel, waiting := l.cancel, l.waiting
+	l.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+
+		select {
+		case <-waiting:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// no wait runs after the listener is closed, the connection is not used by another goroutine anymore
+	if l.conn == nil {
+		return nil
+	}
+
+	return l.conn.Close(ctx)
+}
@@ -0,0 +1,72 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListenerWakesOnInsert(t *testing.T) {
+	_, cfg := testSchema(t)
+	ctx := context.Background()
+	s := testStore(t, cfg)
+
+	l := NewListener(cfg, MessageQueuedChannel)
+	t.Cleanup(func() { _ = l.Close(context.Background()) })
+
+	// the first wait connects and listens, it only returns for notifications sent after that
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(ctx) }()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		if err := s.InsertMessage(ctx, &Message{Recipient: "+905325008081", Content: "hello", Status: MessageStatusQueued}); err != nil {
+			t.Fatalf("InsertMessage() error = %v", err)
+		}
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			return
+		case <-deadline:
+			t.Fatal("Wait() did not return after a message was inserted")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func TestListenerClose(t *testing.T) {
+	_, cfg := testSchema(t)
+
+	l := NewListener(cfg, MessageQueuedChannel)
+
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(context.Background()) }()
+
+	// the wait in progress is cancelled by the close
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := l.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Wait() error = nil, want the cancelled wait to fail")
+		}
+	case <-ctx.Done():
+		t.Fatal("Wait() did not return after the listener was closed")
+	}
+
+	if err := l.Wait(context.Background()); !errors.Is(err, ErrListenerClosed) {
+		t.Errorf("Wait() after Close error = %v, want %v", err, ErrListenerClosed)
+	}
+}
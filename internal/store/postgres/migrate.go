@@ -0,0 +1,279 @@
+package postgrestore
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	envvars "notify-hub-backend/configs/env-vars"
+)
+
+// migrationLockKey is the advisory lock key held while migrations run so that concurrent runs do not interleave
+const migrationLockKey = 7305319182
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration represents a versioned schema change read from the embedded migrations folder.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration represents an applied migration.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"appliedAt"`
+}
+
+// Migrator defines behaviors of schema migrator
+type Migrator interface {
+	Up(ctx context.Context, steps int) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Version(ctx context.Context) (int, error)
+	Close() error
+}
+
+type migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator creates and returns schema migrator of the embedded migrations.
+func NewMigrator(cfg envvars.Postgres) (Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+
+	return &migrator{db: db, migrations: migrations}, nil
+}
+
+// LatestSchemaVersion returns the version of the last embedded migration, which is the version the store expects.
+func LatestSchemaVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Up applies pending migrations in order, at most steps of them unless steps is 0. Each migration runs in its own
+// transaction together with its schema_migrations record.
+func (m *migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		version, err := schemaVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			if steps > 0 && len(applied) == steps {
+				break
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+
+				return tx.Create(&SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts applied migrations in reverse order, at most steps of them unless steps is 0.
+func (m *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		version, err := schemaVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+
+			if steps > 0 && len(reverted) == steps {
+				break
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s is irreversible", migration.Version, migration.Name)
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+
+				return tx.Delete(&SchemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Version returns the version of the last applied migration, 0 if none is applied.
+func (m *migrator) Version(ctx context.Context) (int, error) {
+	return schemaVersion(m.db.WithContext(ctx))
+}
+
+func (m *migrator) Close() error {
+	db, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
+
+// locked runs fn on a single connection holding the migration advisory lock.
+func (m *migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	version    bigint PRIMARY KEY,
+	name       text        NOT NULL,
+	applied_at timestamptz NOT NULL
+)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+
+		return fn(conn)
+	})
+}
+
+// schemaVersion returns the version of the last applied migration, 0 if none is applied.
+func schemaVersion(db *gorm.DB) (int, error) {
+	var exists bool
+	if err := db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch schema version: %w", err)
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// loadMigrations reads the embedded migrations ordered by version, files are named <version>_<name>.<up|down>.sql
+// and statements are executed as they are, without placeholders.
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names %s and %s", version, migration.Name, match[2])
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
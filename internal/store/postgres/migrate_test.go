@@ -0,0 +1,101 @@
+package postgrestore
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations() returned no migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s has version %d, want %d", migration.Version, migration.Name, migration.Version, i+1)
+		}
+
+		if migration.Down == "" {
+			t.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+	}
+
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("LatestSchemaVersion() error = %v", err)
+	}
+
+	if latest != migrations[len(migrations)-1].Version {
+		t.Errorf("LatestSchemaVersion() = %d, want %d", latest, migrations[len(migrations)-1].Version)
+	}
+}
+
+func TestMigratorUpgradesBaselineSchema(t *testing.T) {
+	db, cfg := testSchema(t)
+	ctx := context.Background()
+
+	// the schema created by AutoMigrate before the migrations existed
+	err := db.Exec(`CREATE TABLE messages (
+	id        bigserial PRIMARY KEY,
+	recipient text NOT NULL,
+	content   text NOT NULL,
+	sent      boolean DEFAULT false
+)`).Error
+	if err != nil {
+		t.Fatalf("failed to create baseline schema: %v", err)
+	}
+
+	err = db.Exec(`INSERT INTO messages (recipient, content, sent) VALUES ('5325008081', 'sent', true), ('5325008082', 'queued', false)`).Error
+	if err != nil {
+		t.Fatalf("failed to insert baseline messages: %v", err)
+	}
+
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	latest, _ := LatestSchemaVersion()
+	if version, err := m.Version(ctx); err != nil || version != latest {
+		t.Fatalf("Version() = %d, %v, want %d", version, err, latest)
+	}
+
+	var messages []Message
+	if err := db.Order("id ASC").Find(&messages).Error; err != nil {
+		t.Fatalf("failed to fetch messages: %v", err)
+	}
+
+	want := map[string]string{"5325008081": MessageStatusSent, "5325008082": MessageStatusQueued}
+	if len(messages) != len(want) {
+		t.Fatalf("got %d messages, want %d", len(messages), len(want))
+	}
+
+	for _, message := range messages {
+		if message.Status != want[message.Recipient] {
+			t.Errorf("message to %s has status %q, want %q", message.Recipient, message.Status, want[message.Recipient])
+		}
+	}
+
+	if db.Migrator().HasColumn("messages", "sent") {
+		t.Error("messages still has the sent column")
+	}
+
+	// the upgraded schema reverts and applies again like a new one
+	if _, err := m.Down(ctx, 0); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Up() after Down() error = %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS messages_queued_notify ON messages;
+DROP FUNCTION IF EXISTS notify_message_queued();
+
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS delivery_receipts;
+DROP TABLE IF EXISTS message_deliveries;
+DROP TABLE IF EXISTS message_attempts;
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS group_contacts;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS inbound_messages;
+DROP TABLE IF EXISTS suppressions;
+DROP TABLE IF EXISTS messages;
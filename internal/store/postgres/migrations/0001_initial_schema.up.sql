@@ -0,0 +1,167 @@
+-- baseline of the schema previously created by gorm AutoMigrate, statements are idempotent so that databases created
+-- by AutoMigrate are adopted. Tables that exist are kept and the columns they are missing are added.
+
+CREATE TABLE IF NOT EXISTS messages (
+	id          bigserial PRIMARY KEY,
+	recipient   text        NOT NULL,
+	content     text        NOT NULL,
+	status      text        NOT NULL DEFAULT 'queued',
+	group_id    bigint,
+	campaign_id bigint,
+	attempts    bigint      NOT NULL DEFAULT 0,
+	sent_at     timestamptz,
+	claimed_at  timestamptz
+);
+
+-- messages of the baseline schema have only id, recipient, content and a sent flag instead of a status
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'queued';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS group_id bigint;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS campaign_id bigint;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS attempts bigint NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS sent_at timestamptz;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS claimed_at timestamptz;
+
+-- the sent flag is copied to the status so that sent messages are not sent again, then dropped
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'messages' AND column_name = 'sent'
+	) THEN
+		UPDATE messages SET status = CASE WHEN sent THEN 'sent' ELSE 'queued' END WHERE status = 'queued';
+		ALTER TABLE messages DROP COLUMN sent;
+	END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_messages_status ON messages (status);
+CREATE INDEX IF NOT EXISTS idx_messages_group_id ON messages (group_id);
+CREATE INDEX IF NOT EXISTS idx_messages_campaign_sent_at ON messages (campaign_id, sent_at);
+
+CREATE TABLE IF NOT EXISTS suppressions (
+	id         bigserial PRIMARY KEY,
+	recipient  text        NOT NULL,
+	reason     text        NOT NULL,
+	source     text        NOT NULL,
+	created_at timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressions_recipient ON suppressions (recipient);
+
+CREATE TABLE IF NOT EXISTS inbound_messages (
+	id                  bigserial PRIMARY KEY,
+	recipient           text        NOT NULL,
+	content             text        NOT NULL,
+	keyword             text,
+	provider_message_id text,
+	received_at         timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_messages_recipient ON inbound_messages (recipient);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inbound_messages_provider_message_id ON inbound_messages (provider_message_id)
+	WHERE provider_message_id <> '';
+
+CREATE TABLE IF NOT EXISTS contacts (
+	id         bigserial PRIMARY KEY,
+	name       text        NOT NULL,
+	phone      text        NOT NULL,
+	attributes jsonb       NOT NULL DEFAULT '{}',
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_phone ON contacts (phone);
+
+CREATE TABLE IF NOT EXISTS groups (
+	id          bigserial PRIMARY KEY,
+	name        text        NOT NULL,
+	description text,
+	created_at  timestamptz NOT NULL,
+	updated_at  timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name ON groups (name);
+
+CREATE TABLE IF NOT EXISTS group_contacts (
+	group_id   bigint NOT NULL,
+	contact_id bigint NOT NULL,
+	PRIMARY KEY (group_id, contact_id),
+	CONSTRAINT fk_group_contacts_group FOREIGN KEY (group_id) REFERENCES groups (id),
+	CONSTRAINT fk_group_contacts_contact FOREIGN KEY (contact_id) REFERENCES contacts (id)
+);
+
+CREATE TABLE IF NOT EXISTS campaigns (
+	id                  bigserial PRIMARY KEY,
+	name                text        NOT NULL,
+	content             text        NOT NULL,
+	group_id            bigint,
+	audience_size       bigint      NOT NULL,
+	status              text        NOT NULL DEFAULT 'active',
+	start_at            timestamptz NOT NULL,
+	throttle_per_minute bigint      NOT NULL DEFAULT 0,
+	created_at          timestamptz NOT NULL,
+	updated_at          timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_status ON campaigns (status);
+
+CREATE TABLE IF NOT EXISTS message_attempts (
+	id          bigserial PRIMARY KEY,
+	message_id  bigint      NOT NULL,
+	started_at  timestamptz NOT NULL,
+	finished_at timestamptz NOT NULL,
+	chunks_sent bigint      NOT NULL,
+	error       text
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_attempts_message_id ON message_attempts (message_id);
+
+CREATE TABLE IF NOT EXISTS message_deliveries (
+	id                  bigserial PRIMARY KEY,
+	message_id          bigint      NOT NULL,
+	chunk_index         bigint      NOT NULL,
+	provider_message_id text        NOT NULL,
+	content             text        NOT NULL,
+	sent_at             timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_deliveries_message_id ON message_deliveries (message_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_deliveries_message_chunk ON message_deliveries (message_id, chunk_index);
+CREATE INDEX IF NOT EXISTS idx_message_deliveries_provider_message_id ON message_deliveries (provider_message_id);
+
+CREATE TABLE IF NOT EXISTS delivery_receipts (
+	id                  bigserial PRIMARY KEY,
+	provider_message_id text        NOT NULL,
+	message_id          bigint,
+	status              text        NOT NULL,
+	error               text,
+	received_at         timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_receipts_provider_message_id ON delivery_receipts (provider_message_id);
+CREATE INDEX IF NOT EXISTS idx_delivery_receipts_message_id ON delivery_receipts (message_id);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+	id           bigserial PRIMARY KEY,
+	topic        text        NOT NULL,
+	aggregate_id bigint      NOT NULL,
+	payload      jsonb       NOT NULL,
+	created_at   timestamptz NOT NULL,
+	processed_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed_at ON outbox_events (processed_at);
+
+-- inserted messages wake listening senders up, notifications of a transaction are delivered once since they
+-- carry no payload
+CREATE OR REPLACE FUNCTION notify_message_queued() RETURNS trigger AS $$
+BEGIN
+	PERFORM pg_notify('messages_queued', '');
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS messages_queued_notify ON messages;
+
+CREATE TRIGGER messages_queued_notify AFTER INSERT ON messages
+	FOR EACH STATEMENT EXECUTE PROCEDURE notify_message_queued();
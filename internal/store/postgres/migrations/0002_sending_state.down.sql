@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sending_state_changes;
+DROP TABLE IF EXISTS sending_states;
@@ -0,0 +1,19 @@
+-- sending state per scope, a missing scope is enabled
+CREATE TABLE sending_states (
+	scope      text PRIMARY KEY,
+	enabled    boolean     NOT NULL,
+	reason     text        NOT NULL DEFAULT '',
+	changed_by text        NOT NULL DEFAULT '',
+	changed_at timestamptz NOT NULL
+);
+
+CREATE TABLE sending_state_changes (
+	id         bigserial PRIMARY KEY,
+	scope      text        NOT NULL,
+	enabled    boolean     NOT NULL,
+	reason     text        NOT NULL DEFAULT '',
+	changed_by text        NOT NULL DEFAULT '',
+	changed_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_sending_state_changes_scope ON sending_state_changes (scope, id);
@@ -0,0 +1,2 @@
+ALTER TABLE sending_state_changes DROP COLUMN IF EXISTS resume_at;
+ALTER TABLE sending_states DROP COLUMN IF EXISTS resume_at;
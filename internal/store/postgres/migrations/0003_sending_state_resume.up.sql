@@ -0,0 +1,3 @@
+-- paused scopes are resumed automatically once resume_at has passed
+ALTER TABLE sending_states ADD COLUMN resume_at timestamptz;
+ALTER TABLE sending_state_changes ADD COLUMN resume_at timestamptz;
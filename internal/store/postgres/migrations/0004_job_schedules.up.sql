@@ -0,0 +1,7 @@
+-- schedules of jobs changed at runtime, jobs without a row run on the schedule of their environment variable
+CREATE TABLE job_schedules (
+	name       text PRIMARY KEY,
+	spec       text        NOT NULL,
+	changed_by text        NOT NULL DEFAULT '',
+	changed_at timestamptz NOT NULL
+);
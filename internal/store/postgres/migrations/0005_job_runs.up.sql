@@ -0,0 +1,13 @@
+-- runs of scheduled jobs on every instance
+CREATE TABLE job_runs (
+	id          bigserial PRIMARY KEY,
+	name        text        NOT NULL,
+	instance    text        NOT NULL DEFAULT '',
+	started_at  timestamptz NOT NULL,
+	finished_at timestamptz NOT NULL,
+	duration_ms bigint      NOT NULL,
+	processed   bigint      NOT NULL DEFAULT 0,
+	error       text        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_job_runs_name ON job_runs (name, id);
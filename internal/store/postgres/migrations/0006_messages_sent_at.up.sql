@@ -0,0 +1,2 @@
+-- the last sending time is read by the readiness check
+CREATE INDEX idx_messages_sent_at ON messages (sent_at);
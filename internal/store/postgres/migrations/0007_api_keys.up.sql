@@ -0,0 +1,13 @@
+-- api keys of clients, only the sha256 hash of a key is stored
+CREATE TABLE api_keys (
+	id           bigserial PRIMARY KEY,
+	name         text        NOT NULL,
+	prefix       text        NOT NULL,
+	key_hash     text        NOT NULL UNIQUE,
+	scopes       jsonb       NOT NULL DEFAULT '[]',
+	created_by   text        NOT NULL DEFAULT '',
+	created_at   timestamptz NOT NULL,
+	rotated_at   timestamptz,
+	revoked_at   timestamptz,
+	last_used_at timestamptz
+);
@@ -0,0 +1,30 @@
+DROP INDEX IF EXISTS idx_messages_recipient;
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+DROP INDEX IF EXISTS idx_campaigns_tenant_id;
+DROP INDEX IF EXISTS idx_messages_tenant_id;
+
+DROP INDEX IF EXISTS idx_inbound_messages_tenant_recipient;
+CREATE INDEX idx_inbound_messages_recipient ON inbound_messages (recipient);
+
+-- unique indexes of a single tenant fail if several tenants share a recipient, phone, group name or reply
+DROP INDEX IF EXISTS idx_inbound_messages_tenant_provider_message_id;
+CREATE UNIQUE INDEX idx_inbound_messages_provider_message_id ON inbound_messages (provider_message_id)
+	WHERE provider_message_id <> '';
+
+DROP INDEX IF EXISTS idx_groups_tenant_name;
+CREATE UNIQUE INDEX idx_groups_name ON groups (name);
+
+DROP INDEX IF EXISTS idx_contacts_tenant_phone;
+CREATE UNIQUE INDEX idx_contacts_phone ON contacts (phone);
+
+DROP INDEX IF EXISTS idx_suppressions_tenant_recipient;
+CREATE UNIQUE INDEX idx_suppressions_recipient ON suppressions (recipient);
+
+ALTER TABLE outbox_events DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE campaigns DROP COLUMN tenant_id;
+ALTER TABLE groups DROP COLUMN tenant_id;
+ALTER TABLE contacts DROP COLUMN tenant_id;
+ALTER TABLE inbound_messages DROP COLUMN tenant_id;
+ALTER TABLE suppressions DROP COLUMN tenant_id;
+ALTER TABLE messages DROP COLUMN tenant_id;
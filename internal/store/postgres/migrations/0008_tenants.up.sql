@@ -0,0 +1,35 @@
+-- rows of tenant owned tables belong to a tenant, existing rows belong to the default tenant. Templates are the
+-- contents of messages and campaigns and are scoped with them.
+ALTER TABLE messages ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE suppressions ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE inbound_messages ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE contacts ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE groups ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE campaigns ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+ALTER TABLE outbox_events ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+-- recipients, phones and group names are unique within a tenant
+DROP INDEX idx_suppressions_recipient;
+CREATE UNIQUE INDEX idx_suppressions_tenant_recipient ON suppressions (tenant_id, recipient);
+
+DROP INDEX idx_contacts_phone;
+CREATE UNIQUE INDEX idx_contacts_tenant_phone ON contacts (tenant_id, phone);
+
+DROP INDEX idx_groups_name;
+CREATE UNIQUE INDEX idx_groups_tenant_name ON groups (tenant_id, name);
+
+DROP INDEX idx_inbound_messages_recipient;
+CREATE INDEX idx_inbound_messages_tenant_recipient ON inbound_messages (tenant_id, recipient);
+
+-- tenants share the sending number, a reply is recorded once for each tenant that messaged its sender
+DROP INDEX idx_inbound_messages_provider_message_id;
+CREATE UNIQUE INDEX idx_inbound_messages_tenant_provider_message_id ON inbound_messages (tenant_id, provider_message_id)
+	WHERE provider_message_id <> '';
+
+CREATE INDEX idx_messages_tenant_id ON messages (tenant_id, id);
+CREATE INDEX idx_campaigns_tenant_id ON campaigns (tenant_id, id);
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- replies are recorded for every tenant that sent a message to their sender, the tenants are looked up by recipient
+CREATE INDEX idx_messages_recipient ON messages (recipient, tenant_id);
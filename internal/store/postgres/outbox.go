@@ -0,0 +1,48 @@
+package postgrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// outbox event topics
+const (
+	OutboxTopicMessageSent = "message.sent"
+)
+
+// OutboxEvent represents a state transition written with the transition itself, to be projected into other stores
+// on behalf of the tenant of the transition.
+type OutboxEvent struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID    string     `gorm:"not null;default:default" json:"tenantId"`
+	Topic       string     `gorm:"not null" json:"topic"`
+	AggregateID int64      `gorm:"not null" json:"aggregateId"`
+	Payload     []byte     `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt   time.Time  `gorm:"not null" json:"createdAt"`
+	ProcessedAt *time.Time `gorm:"index" json:"processedAt"`
+}
+
+// FetchOutboxEvents retrieves unprocessed outbox events of the tenant of ctx in order and applies a limit, the relay
+// fetches the events of every tenant with a tenant.HubWide context.
+func (s *store) FetchOutboxEvents(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("processed_at IS NULL").Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkOutboxEventsProcessed stamps outbox events of the tenant of ctx as processed.
+func (s *store) MarkOutboxEventsProcessed(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(&OutboxEvent{}).Scopes(byTenant(ctx)).Where("id IN ?", ids).Update("processed_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox events processed: %w", err)
+	}
+
+	return nil
+}
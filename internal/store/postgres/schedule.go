@@ -0,0 +1,83 @@
+package postgrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// JobSchedule represents a schedule of a job changed at runtime.
+type JobSchedule struct {
+	Name      string    `gorm:"primaryKey" json:"name"`
+	Spec      string    `gorm:"not null" json:"spec"`
+	ChangedBy string    `gorm:"not null;default:''" json:"changedBy"`
+	ChangedAt time.Time `gorm:"not null" json:"changedAt"`
+}
+
+// JobRun represents a run of a scheduled job on an instance.
+type JobRun struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string    `gorm:"not null" json:"name"`
+	Instance   string    `gorm:"not null;default:''" json:"instance"`
+	StartedAt  time.Time `gorm:"not null" json:"startedAt"`
+	FinishedAt time.Time `gorm:"not null" json:"finishedAt"`
+	DurationMs int64     `gorm:"not null" json:"durationMs"`
+	Processed  int       `gorm:"not null;default:0" json:"processed"`
+	Error      string    `gorm:"not null;default:''" json:"error"`
+}
+
+// FetchJobSchedules retrieves the schedules of every job that was changed at runtime.
+func (s *store) FetchJobSchedules(ctx context.Context) ([]JobSchedule, error) {
+	var schedules []JobSchedule
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch job schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// SetJobSchedule stores the schedule of a job, replacing the previous one.
+func (s *store) SetJobSchedule(ctx context.Context, schedule *JobSchedule) error {
+	schedule.ChangedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"spec", "changed_by", "changed_at"}),
+	}).Create(schedule).Error
+	if err != nil {
+		return fmt.Errorf("failed to set job schedule: %w", err)
+	}
+
+	return nil
+}
+
+// InsertJobRun stores a run of a job.
+func (s *store) InsertJobRun(ctx context.Context, run *JobRun) error {
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to insert job run: %w", err)
+	}
+
+	return nil
+}
+
+// FetchJobRuns retrieves runs of a job on every instance, newest first, and applies a limit.
+func (s *store) FetchJobRuns(ctx context.Context, name string, limit int) ([]JobRun, error) {
+	var runs []JobRun
+	if err := s.db.WithContext(ctx).Where("name = ?", name).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// DeleteJobRuns deletes runs started before the given time and returns the number of deleted runs.
+func (s *store) DeleteJobRuns(ctx context.Context, before time.Time) (int, error) {
+	res := s.db.WithContext(ctx).Where("started_at < ?", before).Delete(&JobRun{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete job runs: %w", res.Error)
+	}
+
+	return int(res.RowsAffected), nil
+}
@@ -0,0 +1,185 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sending scopes, marketing messages are the ones of campaigns and transactional messages are the ones without a
+// campaign. A campaign is scoped with SendingScopeCampaignPrefix followed by its id, e.g. campaign:42.
+const (
+	SendingScopeGlobal         = "global"
+	SendingScopeMarketing      = "channel:marketing"
+	SendingScopeTransactional  = "channel:transactional"
+	SendingScopeHookProvider   = "provider:hook"
+	SendingScopeCampaignPrefix = "campaign:"
+)
+
+// SendingState represents the current sending state of a scope, a scope without state is enabled.
+type SendingState struct {
+	Scope     string     `gorm:"primaryKey" json:"scope"`
+	Enabled   bool       `gorm:"not null" json:"enabled"`
+	Reason    string     `gorm:"not null;default:''" json:"reason"`
+	ResumeAt  *time.Time `json:"resumeAt"`
+	ChangedBy string     `gorm:"not null;default:''" json:"changedBy"`
+	ChangedAt time.Time  `gorm:"not null" json:"changedAt"`
+}
+
+// EnabledAt reports whether sending of the scope is enabled at t, a paused scope is enabled once its resume time has
+// passed.
+func (s SendingState) EnabledAt(t time.Time) bool {
+	return s.Enabled || (s.ResumeAt != nil && !s.ResumeAt.After(t))
+}
+
+// SendingStateChange represents a change of the sending state of a scope.
+type SendingStateChange struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Scope     string     `gorm:"not null" json:"scope"`
+	Enabled   bool       `gorm:"not null" json:"enabled"`
+	Reason    string     `gorm:"not null;default:''" json:"reason"`
+	ResumeAt  *time.Time `json:"resumeAt"`
+	ChangedBy string     `gorm:"not null;default:''" json:"changedBy"`
+	ChangedAt time.Time  `gorm:"not null" json:"changedAt"`
+}
+
+// FetchSendingState retrieves the sending state of a scope, it returns an enabled state if the scope has no state.
+func (s *store) FetchSendingState(ctx context.Context, scope string) (*SendingState, error) {
+	var state SendingState
+	err := s.db.WithContext(ctx).Where("scope = ?", scope).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &SendingState{Scope: scope, Enabled: true}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch sending state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// FetchSendingStates retrieves the sending states of every scope that has one, ordered by scope.
+func (s *store) FetchSendingStates(ctx context.Context) ([]SendingState, error) {
+	var states []SendingState
+	if err := s.db.WithContext(ctx).Order("scope ASC").Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sending states: %w", err)
+	}
+
+	return states, nil
+}
+
+// SetSendingState stores the sending state of a scope and records the change, setting a state that is already stored
+// is a no-op that returns the stored state without recording a change.
+func (s *store) SetSendingState(ctx context.Context, state SendingState) (*SendingState, error) {
+	const query = `
+INSERT INTO sending_states (scope, enabled, reason, resume_at, changed_by, changed_at)
+VALUES (@scope, @enabled, @reason, @resumeAt, @changedBy, @now)
+ON CONFLICT (scope) DO UPDATE SET
+	enabled = EXCLUDED.enabled,
+	reason = EXCLUDED.reason,
+	resume_at = EXCLUDED.resume_at,
+	changed_by = EXCLUDED.changed_by,
+	changed_at = EXCLUDED.changed_at
+WHERE (sending_states.enabled, sending_states.reason, sending_states.resume_at)
+	IS DISTINCT FROM (EXCLUDED.enabled, EXCLUDED.reason, EXCLUDED.resume_at)
+RETURNING *`
+
+	var stored []SendingState
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(query, map[string]interface{}{
+			"scope":     state.Scope,
+			"enabled":   state.Enabled,
+			"reason":    state.Reason,
+			"resumeAt":  state.ResumeAt,
+			"changedBy": state.ChangedBy,
+			"now":       time.Now(),
+		}).Scan(&stored).Error
+		if err != nil {
+			return fmt.Errorf("failed to set sending state: %w", err)
+		}
+
+		// nothing is returned when the stored state is the same
+		if len(stored) == 0 {
+			if err := tx.Where("scope = ?", state.Scope).Find(&stored).Error; err != nil {
+				return fmt.Errorf("failed to fetch sending state: %w", err)
+			}
+
+			return nil
+		}
+
+		return insertSendingStateChange(tx, stored[0])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stored) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return &stored[0], nil
+}
+
+// ToggleSendingState flips the sending state of a scope in a single statement so that concurrent toggles are
+// serialized, and records the change. A paused scope whose resume time has passed counts as enabled.
+func (s *store) ToggleSendingState(ctx context.Context, scope, changedBy, reason string) (*SendingState, error) {
+	const query = `
+INSERT INTO sending_states (scope, enabled, reason, changed_by, changed_at)
+VALUES (@scope, false, @reason, @changedBy, @now)
+ON CONFLICT (scope) DO UPDATE SET
+	enabled = NOT (sending_states.enabled OR COALESCE(sending_states.resume_at <= EXCLUDED.changed_at, false)),
+	reason = EXCLUDED.reason,
+	resume_at = NULL,
+	changed_by = EXCLUDED.changed_by,
+	changed_at = EXCLUDED.changed_at
+RETURNING *`
+
+	var state SendingState
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(query, map[string]interface{}{
+			"scope":     scope,
+			"reason":    reason,
+			"changedBy": changedBy,
+			"now":       time.Now(),
+		}).Scan(&state).Error
+		if err != nil {
+			return fmt.Errorf("failed to toggle sending state: %w", err)
+		}
+
+		return insertSendingStateChange(tx, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// FetchSendingStateChanges retrieves changes of the sending state of a scope, newest first, and applies a limit.
+func (s *store) FetchSendingStateChanges(ctx context.Context, scope string, limit int) ([]SendingStateChange, error) {
+	var changes []SendingStateChange
+	if err := s.db.WithContext(ctx).Where("scope = ?", scope).Order("id DESC").Limit(limit).Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch sending state changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+func insertSendingStateChange(tx *gorm.DB, state SendingState) error {
+	err := tx.Create(&SendingStateChange{
+		Scope:     state.Scope,
+		Enabled:   state.Enabled,
+		Reason:    state.Reason,
+		ResumeAt:  state.ResumeAt,
+		ChangedBy: state.ChangedBy,
+		ChangedAt: state.ChangedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to insert sending state change: %w", err)
+	}
+
+	return nil
+}
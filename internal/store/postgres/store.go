@@ -2,27 +2,147 @@ package postgrestore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/tenant"
+)
+
+// message statuses
+const (
+	MessageStatusQueued     = "queued"
+	MessageStatusSending    = "sending"
+	MessageStatusSent       = "sent"
+	MessageStatusSuppressed = "suppressed"
+	MessageStatusFailed     = "failed"
+	MessageStatusDelivered  = "delivered"
+	MessageStatusCancelled  = "cancelled"
+)
+
+// store errors
+var (
+	ErrNotFound      = errors.New("record not found")
+	ErrNoTenant      = errors.New("query has no tenant")
+	ErrAlreadyExists = gorm.ErrDuplicatedKey
 )
 
 // Message represents the message model.
 type Message struct {
-	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	Recipient string `gorm:"not null" json:"recipient"`
-	Content   string `gorm:"not null" json:"content"`
-	Sent      bool   `gorm:"default:false" json:"sent"`
+	ID         int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   string     `gorm:"not null;default:default;index:idx_messages_tenant_id" json:"tenantId"`
+	Recipient  string     `gorm:"not null" json:"recipient"`
+	Content    string     `gorm:"not null" json:"content"`
+	Status     string     `gorm:"not null;default:queued;index" json:"status"`
+	GroupID    *int64     `gorm:"index" json:"groupId"`
+	CampaignID *int64     `gorm:"index:idx_messages_campaign_sent_at" json:"campaignId"`
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	SentAt     *time.Time `gorm:"index:idx_messages_campaign_sent_at" json:"sentAt"`
+	ClaimedAt  *time.Time `json:"claimedAt"`
+}
+
+// message sort keys
+const (
+	MessageSortID     = "id"
+	MessageSortSentAt = "sent_at"
+)
+
+// MessageFilter represents filters, sorting and keyset position of a message page.
+type MessageFilter struct {
+	Recipient  string
+	Statuses   []string
+	CampaignID int64
+	From       time.Time
+	To         time.Time
+	SortBy     string
+	Descending bool
+	After      *MessageCursor
+	Limit      int
+}
+
+// ClaimFilter represents messages that are not claimed because their sending is paused.
+type ClaimFilter struct {
+	SkipMarketing     bool
+	SkipTransactional bool
+	PausedCampaignIDs []int64
+}
+
+// MessageCursor represents the sort key of the last message of a page.
+type MessageCursor struct {
+	ID     int64     `json:"id"`
+	SentAt time.Time `json:"sentAt"`
 }
 
 // Store interface defines the methods to interact with the database.
 type Store interface {
-	FetchMessages(ctx context.Context, sent bool, limit int) ([]Message, error)
-	UpdateMessageStatusToSent(ctx context.Context, id int64) error
-	InsertDummyMessages(ctx context.Context) error
+	InsertMessage(ctx context.Context, message *Message) error
+	InsertMessages(ctx context.Context, messages []Message) error
+	FetchMessage(ctx context.Context, id int64) (*Message, error)
+	FetchMessages(ctx context.Context, status string, limit int) ([]Message, error)
+	ClaimSendableMessages(ctx context.Context, limit int, lease time.Duration, filter ClaimFilter) ([]Message, error)
+	ClaimMessage(ctx context.Context, id int64, lease time.Duration) (*Message, error)
+	FetchMessagesPage(ctx context.Context, filter MessageFilter) ([]Message, error)
+	UpdateMessageStatus(ctx context.Context, id int64, status string) error
+	RecordMessageFailure(ctx context.Context, id int64, maxAttempts int) error
+	ReleaseMessages(ctx context.Context, ids []int64) error
+	CountMessages(ctx context.Context, status string) (int64, error)
+	FetchLastSentAt(ctx context.Context) (*time.Time, error)
+	FetchRecipientTenants(ctx context.Context, recipient string) ([]string, error)
+	InsertMessageAttempt(ctx context.Context, attempt *MessageAttempt) error
+	MarkMessageSent(ctx context.Context, id int64, deliveries []MessageDelivery) error
+	FetchMessageAttempts(ctx context.Context, messageID int64) ([]MessageAttempt, error)
+	FetchMessageDeliveries(ctx context.Context, messageID int64) ([]MessageDelivery, error)
+	FetchDeliveriesOfMessages(ctx context.Context, messageIDs []int64) ([]MessageDelivery, error)
+	FetchDeliveryReceipts(ctx context.Context, messageID int64) ([]DeliveryReceipt, error)
+	RecordDeliveryReceipt(ctx context.Context, receipt *DeliveryReceipt) error
+	FetchOutboxEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	MarkOutboxEventsProcessed(ctx context.Context, ids []int64) error
+	ResetData(ctx context.Context) error
+	FetchSendingState(ctx context.Context, scope string) (*SendingState, error)
+	FetchSendingStates(ctx context.Context) ([]SendingState, error)
+	SetSendingState(ctx context.Context, state SendingState) (*SendingState, error)
+	ToggleSendingState(ctx context.Context, scope, changedBy, reason string) (*SendingState, error)
+	FetchSendingStateChanges(ctx context.Context, scope string, limit int) ([]SendingStateChange, error)
+	FetchJobSchedules(ctx context.Context) ([]JobSchedule, error)
+	SetJobSchedule(ctx context.Context, schedule *JobSchedule) error
+	InsertJobRun(ctx context.Context, run *JobRun) error
+	FetchJobRuns(ctx context.Context, name string, limit int) ([]JobRun, error)
+	DeleteJobRuns(ctx context.Context, before time.Time) (int, error)
+	InsertAPIKey(ctx context.Context, key *APIKey) error
+	FetchAPIKeys(ctx context.Context) ([]APIKey, error)
+	FetchAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	RotateAPIKey(ctx context.Context, id int64, prefix, hash string) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) (*APIKey, error)
+	TouchAPIKey(ctx context.Context, id int64) error
+	AddSuppression(ctx context.Context, suppression Suppression) (*Suppression, error)
+	RemoveSuppression(ctx context.Context, recipient string) error
+	FetchSuppression(ctx context.Context, recipient string) (*Suppression, error)
+	FetchSuppressions(ctx context.Context) ([]Suppression, error)
+	RecordInboundMessage(ctx context.Context, message *InboundMessage, suppression *Suppression, resubscribe bool) (bool, error)
+	FetchInboundMessages(ctx context.Context, recipient string, limit int) ([]InboundMessage, error)
+	InsertContact(ctx context.Context, contact *Contact) error
+	InsertContacts(ctx context.Context, contacts []Contact) error
+	UpdateContact(ctx context.Context, contact *Contact) error
+	DeleteContact(ctx context.Context, id int64) error
+	FetchContact(ctx context.Context, id int64) (*Contact, error)
+	FetchContactsByPhones(ctx context.Context, phones []string) ([]Contact, error)
+	FetchContacts(ctx context.Context) ([]Contact, error)
+	InsertGroup(ctx context.Context, group *Group, contactIDs []int64) error
+	UpdateGroup(ctx context.Context, group *Group, contactIDs []int64) error
+	DeleteGroup(ctx context.Context, id int64) error
+	FetchGroup(ctx context.Context, id int64) (*Group, error)
+	FetchGroups(ctx context.Context) ([]Group, error)
+	InsertCampaign(ctx context.Context, campaign *Campaign, messages []Message) error
+	UpdateCampaignStatus(ctx context.Context, id int64, status string, from []string) (int, error)
+	FetchCampaign(ctx context.Context, id int64) (*Campaign, error)
+	FetchCampaigns(ctx context.Context) ([]Campaign, error)
+	FetchCampaignProgress(ctx context.Context, id int64) (CampaignProgress, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -30,59 +150,405 @@ type store struct {
 	db *gorm.DB
 }
 
+// NewStore creates and returns postgres store, the schema must be migrated to the latest version beforehand
 func NewStore(cfg envvars.Postgres) (Store, error) {
-	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to the database: %w", err)
 	}
 
-	if err := db.AutoMigrate(&Message{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate the Message model: %w", err)
+	if err := checkSchemaVersion(db); err != nil {
+		return nil, err
 	}
 
 	return &store{db: db}, nil
 }
 
-// FetchMessages retrieves messages based on their sent status and applies a limit.
-func (s *store) FetchMessages(ctx context.Context, sent bool, limit int) ([]Message, error) {
+// checkSchemaVersion returns an error unless the applied migrations match the embedded ones.
+func checkSchemaVersion(db *gorm.DB) error {
+	expected, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	version, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	if version != expected {
+		return fmt.Errorf("database schema is at version %d, expected %d, run the migrate up command", version, expected)
+	}
+
+	return nil
+}
+
+// InsertMessage inserts a message to be sent for the tenant of ctx.
+func (s *store) InsertMessage(ctx context.Context, message *Message) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert message: %w", err)
+	}
+
+	message.TenantID = tenantID
+
+	if err := s.db.WithContext(ctx).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to insert message: %w", err)
+	}
+
+	return nil
+}
+
+// InsertMessages inserts messages to be sent for the tenant of ctx in a single transaction.
+func (s *store) InsertMessages(ctx context.Context, messages []Message) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to insert messages: %w", err)
+	}
+
+	for i := range messages {
+		messages[i].TenantID = tenantID
+	}
+
+	if err := s.db.WithContext(ctx).CreateInBatches(messages, 500).Error; err != nil {
+		return fmt.Errorf("failed to insert messages: %w", err)
+	}
+
+	return nil
+}
+
+// FetchMessages retrieves messages based on their status and applies a limit.
+func (s *store) FetchMessages(ctx context.Context, status string, limit int) ([]Message, error) {
 	var messages []Message
-	if err := s.db.WithContext(ctx).Where("sent = ?", sent).Order("id ASC").Limit(limit).Find(&messages).Error; err != nil {
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("status = ?", status).Order("id ASC").Limit(limit).Find(&messages).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch messages: %w", err)
 	}
 
 	return messages, nil
 }
 
-// UpdateMessageStatusToSent updates the sent status of a message to true based on its ID.
-func (s *store) UpdateMessageStatusToSent(ctx context.Context, id int64) error {
-	if err := s.db.WithContext(ctx).Model(&Message{}).Where("id = ?", id).Update("sent", true).Error; err != nil {
+// FetchMessagesPage retrieves a page of messages matching filter, the page starts after filter's cursor.
+// Messages without a sending time are sorted as the oldest ones when sorting by sending time.
+func (s *store) FetchMessagesPage(ctx context.Context, filter MessageFilter) ([]Message, error) {
+	const sentAtKey = "COALESCE(sent_at, 'epoch'::timestamptz)"
+
+	q := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx))
+
+	if filter.Recipient != "" {
+		q = q.Where("recipient = ?", filter.Recipient)
+	}
+
+	if len(filter.Statuses) > 0 {
+		q = q.Where("status IN ?", filter.Statuses)
+	}
+
+	if filter.CampaignID != 0 {
+		q = q.Where("campaign_id = ?", filter.CampaignID)
+	}
+
+	if !filter.From.IsZero() {
+		q = q.Where("sent_at >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		q = q.Where("sent_at < ?", filter.To)
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	switch filter.SortBy {
+	case MessageSortSentAt:
+		if filter.After != nil {
+			q = q.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sentAtKey, comparison), filter.After.SentAt, filter.After.ID)
+		}
+
+		q = q.Order(fmt.Sprintf("%s %s, id %s", sentAtKey, direction, direction))
+	default:
+		if filter.After != nil {
+			q = q.Where(fmt.Sprintf("id %s ?", comparison), filter.After.ID)
+		}
+
+		q = q.Order("id " + direction)
+	}
+
+	var messages []Message
+	if err := q.Limit(filter.Limit).Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch messages page: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ClaimSendableMessages marks queued messages that are not held back by their campaign as sending and returns them, a
+// campaign's messages are sendable once it is active and started, and at most its per minute throttle minus its last
+// minute sends are claimed. Messages left sending longer than lease, e.g. by a crashed run, are claimed again. Messages
+// excluded by filter are left as they are. Only messages of the tenant of ctx are claimed, the sender claims the
+// messages of every tenant with a tenant.HubWide context.
+func (s *store) ClaimSendableMessages(ctx context.Context, limit int, lease time.Duration, filter ClaimFilter) ([]Message, error) {
+	tenantID, err := tenantOf(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim sendable messages: %w", err)
+	}
+
+	const query = `
+WITH recent AS (
+	SELECT campaign_id, COUNT(*) AS sent
+	FROM messages
+	WHERE campaign_id IS NOT NULL AND (sent_at > @since OR (status = @sending AND claimed_at >= @stale))
+	GROUP BY campaign_id
+), candidates AS (
+	SELECT messages.id,
+		ROW_NUMBER() OVER (PARTITION BY messages.campaign_id ORDER BY messages.id) AS campaign_rank,
+		COALESCE(campaigns.throttle_per_minute, 0) AS throttle_per_minute,
+		COALESCE(recent.sent, 0) AS recent_sent,
+		messages.campaign_id
+	FROM messages
+	LEFT JOIN campaigns ON campaigns.id = messages.campaign_id
+	LEFT JOIN recent ON recent.campaign_id = messages.campaign_id
+	WHERE (messages.status = @queued OR (messages.status = @sending AND messages.claimed_at < @stale))
+		AND (messages.campaign_id IS NULL OR (campaigns.status = @active AND campaigns.start_at <= @now))
+		AND (messages.campaign_id IS NOT NULL OR NOT @skipTransactional)
+		AND (messages.campaign_id IS NULL OR (NOT @skipMarketing AND messages.campaign_id NOT IN @pausedCampaigns))
+		AND (@tenant = '' OR messages.tenant_id = @tenant)
+), picked AS (
+	SELECT id FROM candidates
+	WHERE campaign_id IS NULL OR throttle_per_minute = 0 OR campaign_rank <= throttle_per_minute - recent_sent
+	ORDER BY id ASC
+	LIMIT @limit
+)
+UPDATE messages SET status = @sending, claimed_at = @now
+FROM picked
+WHERE messages.id = picked.id
+	AND (messages.status = @queued OR (messages.status = @sending AND messages.claimed_at < @stale))
+RETURNING messages.*`
+
+	now := time.Now()
+
+	// campaign ids start from 1, 0 keeps the list from being empty which would exclude every campaign
+	pausedCampaigns := append([]int64{0}, filter.PausedCampaignIDs...)
+
+	var messages []Message
+	err = s.db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"since":             now.Add(-time.Minute),
+		"stale":             now.Add(-lease),
+		"queued":            MessageStatusQueued,
+		"sending":           MessageStatusSending,
+		"active":            CampaignStatusActive,
+		"now":               now,
+		"limit":             limit,
+		"skipMarketing":     filter.SkipMarketing,
+		"skipTransactional": filter.SkipTransactional,
+		"pausedCampaigns":   pausedCampaigns,
+		"tenant":            tenantID,
+	}).Scan(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim sendable messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// FetchMessage retrieves a message by its ID, it returns nil if the message does not exist.
+func (s *store) FetchMessage(ctx context.Context, id int64) (*Message, error) {
+	var message Message
+	err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).First(&message, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// ClaimMessage marks a queued message that does not belong to a campaign as sending and returns it, it returns nil
+// if the message is not claimable, e.g. it is already claimed, sent or cancelled.
+func (s *store) ClaimMessage(ctx context.Context, id int64, lease time.Duration) (*Message, error) {
+	now := time.Now()
+
+	var messages []Message
+	err := s.db.WithContext(ctx).Model(&messages).Scopes(byTenant(ctx)).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND campaign_id IS NULL", id).
+		Where("status = ? OR (status = ? AND claimed_at < ?)", MessageStatusQueued, MessageStatusSending, now.Add(-lease)).
+		Updates(map[string]interface{}{
+			"status":     MessageStatusSending,
+			"claimed_at": now,
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim message: %w", err)
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return &messages[0], nil
+}
+
+// UpdateMessageStatus updates the status of a sending message based on its ID. A message that is no longer sending,
+// e.g. cancelled, is left as is.
+func (s *store) UpdateMessageStatus(ctx context.Context, id int64, status string) error {
+	if err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Where("id = ? AND status = ?", id, MessageStatusSending).Update("status", status).Error; err != nil {
 		return fmt.Errorf("failed to update message status: %w", err)
 	}
 
 	return nil
 }
 
-// InsertDummyMessages deletes all existing messages and inserts dummy messages numbered from 1 to 10 into the Message table.
-func (s *store) InsertDummyMessages(ctx context.Context) error {
-	// Delete all existing records in the Message table
-	if err := s.db.WithContext(ctx).Where("1 = 1").Delete(&Message{}).Error; err != nil {
-		return fmt.Errorf("failed to delete existing messages: %w", err)
+// RecordMessageFailure increments send attempts of a sending message and queues it again for the next run, or marks it
+// as failed once maxAttempts is reached. A message that is no longer sending, e.g. cancelled, is left as is.
+func (s *store) RecordMessageFailure(ctx context.Context, id int64, maxAttempts int) error {
+	err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Where("id = ? AND status = ?", id, MessageStatusSending).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"status":     gorm.Expr("CASE WHEN attempts + 1 >= ? THEN ? ELSE ? END", maxAttempts, MessageStatusFailed, MessageStatusQueued),
+		"claimed_at": nil,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record message failure: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseMessages queues messages that are still sending again without counting an attempt, so that they are claimed
+// by the next run instead of after their lease.
+func (s *store) ReleaseMessages(ctx context.Context, ids []int64) error {
+	err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).
+		Where("id IN ? AND status = ?", ids, MessageStatusSending).
+		Updates(map[string]interface{}{
+			"status":     MessageStatusQueued,
+			"claimed_at": nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release messages: %w", err)
+	}
+
+	return nil
+}
+
+// CountMessages counts messages with a status.
+func (s *store) CountMessages(ctx context.Context, status string) (int64, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Where("status = ?", status).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// FetchLastSentAt retrieves the sending time of the last sent message, it returns nil if no message was sent.
+func (s *store) FetchLastSentAt(ctx context.Context) (*time.Time, error) {
+	var sentAt *time.Time
+	err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Select("MAX(sent_at)").Scan(&sentAt).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch last sending time: %w", err)
+	}
+
+	return sentAt, nil
+}
+
+// FetchRecipientTenants retrieves the tenants that queued a message to a recipient in order, it returns the default
+// tenant if no message was queued to the recipient. Replies of recipients carry no tenant, their webhook looks them up
+// with a tenant.HubWide context.
+func (s *store) FetchRecipientTenants(ctx context.Context, recipient string) ([]string, error) {
+	var tenants []string
+	err := s.db.WithContext(ctx).Model(&Message{}).Scopes(byTenant(ctx)).Where("recipient = ?", recipient).Distinct("tenant_id").Order("tenant_id ASC").Pluck("tenant_id", &tenants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recipient tenants: %w", err)
+	}
+
+	if len(tenants) == 0 {
+		return []string{tenant.Default}, nil
+	}
+
+	return tenants, nil
+}
+
+// ResetData deletes all messages with their delivery history, contacts and groups, and restarts their ids.
+func (s *store) ResetData(ctx context.Context) error {
+	const query = `TRUNCATE messages, message_attempts, message_deliveries, delivery_receipts, outbox_events,
+	campaigns, group_contacts, groups, contacts RESTART IDENTITY`
+
+	if err := s.db.WithContext(ctx).Exec(query).Error; err != nil {
+		return fmt.Errorf("failed to reset data: %w", err)
+	}
+
+	return nil
+}
+
+// tenantOf returns the tenant queries of ctx are restricted to, or an empty id for contexts marked as working across
+// tenants. Contexts carrying neither are refused with ErrNoTenant so that a caller that lost its tenant never reads or
+// writes the data of every tenant.
+func tenantOf(ctx context.Context) (string, error) {
+	if id, ok := tenant.From(ctx); ok {
+		return id, nil
+	}
+
+	if tenant.IsHubWide(ctx) {
+		return "", nil
+	}
+
+	return "", ErrNoTenant
+}
+
+// ownerOf returns the tenant rows inserted with ctx belong to. Every row has a tenant, contexts without one are refused
+// with ErrNoTenant, hub wide ones included.
+func ownerOf(ctx context.Context) (string, error) {
+	if id, ok := tenant.From(ctx); ok {
+		return id, nil
 	}
 
-	// Insert dummy messages numbered from 1 to 10
-	for i := 1; i <= 10; i++ {
-		message := Message{
-			Recipient: fmt.Sprintf("532500808%d", i),
-			Content:   fmt.Sprintf("Lorem ipsum dolor sit amet, consectetur adipiscing elit. Pellentesque sit amet sem nec nisl facilisis pretium. Nunc aliquet justo euismod urna, in fermentum eros accumsan. This is message number %d", i),
-			Sent:      false,
+	return "", ErrNoTenant
+}
+
+// byTenant restricts a query of a tenant owned table to the tenant of ctx. Queries of contexts marked with
+// tenant.HubWide are not restricted, queries of other contexts without a tenant fail with ErrNoTenant.
+func byTenant(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		id, err := tenantOf(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
 		}
 
-		if err := s.db.WithContext(ctx).Create(&message).Error; err != nil {
-			return fmt.Errorf("failed to insert dummy message %d: %w", i, err)
+		if id == "" {
+			return db
 		}
+
+		return db.Where("tenant_id = ?", id)
 	}
+}
 
-	return nil
+// byMessageTenant restricts a query of a table owned by messages, e.g. their delivery history, to the messages of the
+// tenant of ctx like byTenant.
+func byMessageTenant(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		id, err := tenantOf(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		if id == "" {
+			return db
+		}
+
+		return db.Where("message_id IN (SELECT id FROM messages WHERE tenant_id = ?)", id)
+	}
+}
+
+// Ping checks the database connection.
+func (s *store) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the generic DB object from GORM: %w", err)
+	}
+
+	return sqlDB.PingContext(ctx)
 }
 
 // Close closes the GORM database connection.
@@ -0,0 +1,280 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/tenant"
+)
+
+// testDSNVariable names the url of a postgres database the store tests create their schemas in, the tests are skipped
+// when it is not set
+const testDSNVariable = "POSTGRES_TEST_DSN"
+
+func TestDeliveryReceiptsBeforeSent(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		receipts []string
+		want     string
+	}{
+		{name: "every chunk delivered", chunks: []string{"p-1", "p-2"}, receipts: []string{"p-1", "p-2"}, want: MessageStatusDelivered},
+		{name: "a chunk not delivered", chunks: []string{"p-1", "p-2"}, receipts: []string{"p-1"}, want: MessageStatusSent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cfg := testSchema(t)
+			ctx := tenant.With(context.Background(), "payments")
+			s := testStore(t, cfg)
+
+			message := Message{Recipient: "+905325008081", Content: "hello", Status: MessageStatusSending}
+			if err := s.InsertMessage(ctx, &message); err != nil {
+				t.Fatalf("InsertMessage() error = %v", err)
+			}
+
+			// the provider reports chunks before the sender stores them
+			for _, providerMessageID := range tt.receipts {
+				receipt := DeliveryReceipt{ProviderMessageID: providerMessageID, Status: ReceiptStatusDelivered, ReceivedAt: time.Now()}
+				if err := s.RecordDeliveryReceipt(tenant.HubWide(context.Background()), &receipt); err != nil {
+					t.Fatalf("RecordDeliveryReceipt() error = %v", err)
+				}
+			}
+
+			deliveries := make([]MessageDelivery, 0, len(tt.chunks))
+			for i, providerMessageID := range tt.chunks {
+				deliveries = append(deliveries, MessageDelivery{
+					MessageID:         message.ID,
+					ChunkIndex:        i,
+					ProviderMessageID: providerMessageID,
+					Content:           "hello",
+					SentAt:            time.Now(),
+				})
+			}
+
+			if err := s.MarkMessageSent(ctx, message.ID, deliveries); err != nil {
+				t.Fatalf("MarkMessageSent() error = %v", err)
+			}
+
+			got, err := s.FetchMessage(ctx, message.ID)
+			if err != nil {
+				t.Fatalf("FetchMessage() error = %v", err)
+			}
+
+			if got.Status != tt.want {
+				t.Errorf("status = %q, want %q", got.Status, tt.want)
+			}
+
+			receipts, err := s.FetchDeliveryReceipts(ctx, message.ID)
+			if err != nil {
+				t.Fatalf("FetchDeliveryReceipts() error = %v", err)
+			}
+
+			if len(receipts) != len(tt.receipts) {
+				t.Errorf("got %d receipts of the message, want %d", len(receipts), len(tt.receipts))
+			}
+		})
+	}
+}
+
+// dryRunDB returns a database that builds statements without connecting, statements run outside of transactions
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("postgres://notify@127.0.0.1:1/notify"), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	return db
+}
+
+func TestTenantScopes(t *testing.T) {
+	db := dryRunDB(t)
+	background := context.Background()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		messages   string
+		deliveries string
+	}{
+		{
+			name: "no tenant",
+			ctx:  background,
+			err:  ErrNoTenant,
+		},
+		{
+			name:       "tenant",
+			ctx:        tenant.With(background, "acme"),
+			messages:   `SELECT * FROM "messages" WHERE status = $1 AND tenant_id = $2`,
+			deliveries: `SELECT * FROM "message_deliveries" WHERE message_id = $1 AND message_id IN (SELECT id FROM messages WHERE tenant_id = $2)`,
+		},
+		{
+			name:       "hub wide",
+			ctx:        tenant.HubWide(background),
+			messages:   `SELECT * FROM "messages" WHERE status = $1`,
+			deliveries: `SELECT * FROM "message_deliveries" WHERE message_id = $1`,
+		},
+		{
+			name:       "tenant of hub wide",
+			ctx:        tenant.With(tenant.HubWide(background), "acme"),
+			messages:   `SELECT * FROM "messages" WHERE status = $1 AND tenant_id = $2`,
+			deliveries: `SELECT * FROM "message_deliveries" WHERE message_id = $1 AND message_id IN (SELECT id FROM messages WHERE tenant_id = $2)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []Message
+			res := db.WithContext(tt.ctx).Scopes(byTenant(tt.ctx)).Where("status = ?", MessageStatusQueued).Find(&messages)
+			if !errors.Is(res.Error, tt.err) {
+				t.Fatalf("byTenant error = %v, want %v", res.Error, tt.err)
+			}
+
+			if sql := res.Statement.SQL.String(); sql != tt.messages {
+				t.Errorf("byTenant sql = %q, want %q", sql, tt.messages)
+			}
+
+			var deliveries []MessageDelivery
+			res = db.WithContext(tt.ctx).Scopes(byMessageTenant(tt.ctx)).Where("message_id = ?", 1).Find(&deliveries)
+			if !errors.Is(res.Error, tt.err) {
+				t.Fatalf("byMessageTenant error = %v, want %v", res.Error, tt.err)
+			}
+
+			if sql := res.Statement.SQL.String(); sql != tt.deliveries {
+				t.Errorf("byMessageTenant sql = %q, want %q", sql, tt.deliveries)
+			}
+		})
+	}
+}
+
+func TestInsertsRequireTenant(t *testing.T) {
+	s := &store{db: dryRunDB(t)}
+	background := context.Background()
+
+	inserts := map[string]func(ctx context.Context) error{
+		"message": func(ctx context.Context) error {
+			return s.InsertMessage(ctx, &Message{Recipient: "+905325008081", Content: "hello"})
+		},
+		"messages": func(ctx context.Context) error {
+			return s.InsertMessages(ctx, []Message{{Recipient: "+905325008081", Content: "hello"}})
+		},
+		"contact": func(ctx context.Context) error {
+			return s.InsertContact(ctx, &Contact{Name: "Ayşe", Phone: "+905325008081"})
+		},
+		"api key": func(ctx context.Context) error {
+			return s.InsertAPIKey(ctx, &APIKey{Name: "ci", KeyHash: "hash"})
+		},
+		"inbound message": func(ctx context.Context) error {
+			_, err := s.RecordInboundMessage(ctx, &InboundMessage{Recipient: "+905325008081", Content: "STOP"}, nil, false)
+			return err
+		},
+		"sent message": func(ctx context.Context) error {
+			return s.MarkMessageSent(ctx, 1, nil)
+		},
+	}
+
+	// rows always belong to a tenant, hub wide callers have none to give them
+	for name, insert := range inserts {
+		for _, ctx := range []context.Context{background, tenant.HubWide(background)} {
+			if err := insert(ctx); !errors.Is(err, ErrNoTenant) {
+				t.Errorf("insert %s error = %v, want %v", name, err, ErrNoTenant)
+			}
+		}
+	}
+
+	message := Message{Recipient: "+905325008081", Content: "hello"}
+	if err := s.InsertMessage(tenant.With(background, "acme"), &message); err != nil {
+		t.Fatalf("InsertMessage error = %v", err)
+	}
+
+	if message.TenantID != "acme" {
+		t.Errorf("message tenant = %q, want acme", message.TenantID)
+	}
+}
+
+// testSchema creates an empty schema in the test database and returns a connection and configuration using it, the
+// schema is dropped when the test finishes
+func testSchema(t *testing.T) (*gorm.DB, envvars.Postgres) {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNVariable)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNVariable)
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("store_test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+
+	cfg := envvars.Postgres{DSN: dsn + separator + "search_path=" + schema}
+
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect to the test schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+
+		_ = admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error
+
+		if sqlDB, err := admin.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db, cfg
+}
+
+// testStore migrates the schema of cfg to the latest version and returns a store using it, the store is closed when the
+// test finishes
+func testStore(t *testing.T, cfg envvars.Postgres) Store {
+	t.Helper()
+
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	s, err := NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s
+}
@@ -0,0 +1,90 @@
+package postgrestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// suppression sources
+const (
+	SuppressionSourceAPI     = "api"
+	SuppressionSourceKeyword = "keyword"
+)
+
+// Suppression represents the suppressed recipient model.
+type Suppression struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID  string    `gorm:"not null;default:default;uniqueIndex:idx_suppressions_tenant_recipient" json:"tenantId"`
+	Recipient string    `gorm:"not null;uniqueIndex:idx_suppressions_tenant_recipient" json:"recipient"`
+	Reason    string    `gorm:"not null" json:"reason"`
+	Source    string    `gorm:"not null" json:"source"`
+	CreatedAt time.Time `gorm:"not null" json:"createdAt"`
+}
+
+// AddSuppression inserts a suppression entry of the tenant of ctx, or updates the reason and source of an existing entry
+// for the same recipient.
+func (s *store) AddSuppression(ctx context.Context, suppression Suppression) (*Suppression, error) {
+	if err := upsertSuppression(ctx, s.db.WithContext(ctx), &suppression); err != nil {
+		return nil, err
+	}
+
+	return s.FetchSuppression(ctx, suppression.Recipient)
+}
+
+// RemoveSuppression deletes the suppression entry of a recipient.
+func (s *store) RemoveSuppression(ctx context.Context, recipient string) error {
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("recipient = ?", recipient).Delete(&Suppression{}).Error; err != nil {
+		return fmt.Errorf("failed to remove suppression: %w", err)
+	}
+
+	return nil
+}
+
+// FetchSuppression retrieves the suppression entry of a recipient, it returns nil if the recipient is not suppressed.
+func (s *store) FetchSuppression(ctx context.Context, recipient string) (*Suppression, error) {
+	var suppression Suppression
+	err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Where("recipient = ?", recipient).First(&suppression).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch suppression: %w", err)
+	}
+
+	return &suppression, nil
+}
+
+// FetchSuppressions retrieves all suppression entries ordered by creation.
+func (s *store) FetchSuppressions(ctx context.Context) ([]Suppression, error) {
+	var suppressions []Suppression
+	if err := s.db.WithContext(ctx).Scopes(byTenant(ctx)).Order("id ASC").Find(&suppressions).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
+
+// upsertSuppression inserts a suppression entry of the tenant of ctx with db, or updates the reason and source of the
+// existing entry.
+func upsertSuppression(ctx context.Context, db *gorm.DB, suppression *Suppression) error {
+	tenantID, err := ownerOf(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to add suppression: %w", err)
+	}
+
+	suppression.TenantID = tenantID
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "recipient"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "source"}),
+	}).Create(suppression).Error
+	if err != nil {
+		return fmt.Errorf("failed to add suppression: %w", err)
+	}
+
+	return nil
+}
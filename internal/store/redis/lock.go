@@ -0,0 +1,96 @@
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotHeld represents a lock that expired or was acquired by another owner
+var ErrLockNotHeld = errors.New("lock is not held")
+
+// refreshScript extends the expiry of a lock if it is still held by the token
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript deletes a lock if it is still held by the token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Lock represents a lock acquired by TryLock, it expires unless it is refreshed.
+type Lock interface {
+	Refresh(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+type lock struct {
+	c     *redis.Client
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+// TryLock acquires the lock of key for ttl, it returns nil without an error if the lock is held by another owner.
+func (s *store) TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lock{
+		c:     s.c,
+		key:   key,
+		token: token,
+		ttl:   ttl,
+	}
+
+	ok, err := s.c.SetNX(ctx, l.key, l.token, ttl).Result()
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return l, nil
+}
+
+// Refresh extends the lock by its ttl
+func (l *lock) Refresh(ctx context.Context) error {
+	n, err := refreshScript.Run(ctx, l.c, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// Release releases the lock, a lock that expired in the meantime is left to its new owner
+func (l *lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.c, []string{l.key}, l.token).Err()
+}
+
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,168 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	envvars "notify-hub-backend/configs/env-vars"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const messageIDField = "messageId"
+
+// QueueEntry represents a message waiting in the stream
+type QueueEntry struct {
+	ID        string
+	MessageID int64
+}
+
+// Queue defines behaviors of redis stream work queue
+type Queue interface {
+	Publish(ctx context.Context, messageIDs ...int64) error
+	Consume(ctx context.Context, consumer string, count int64) ([]QueueEntry, error)
+	Reclaim(ctx context.Context, consumer string, count int64) ([]QueueEntry, error)
+	Ack(ctx context.Context, ids ...string) error
+	Len(ctx context.Context) (int64, error)
+	Close() error
+}
+
+// queue represents redis stream work queue read by a consumer group
+type queue struct {
+	stream      string
+	group       string
+	block       time.Duration
+	reclaimIdle time.Duration
+	c           *redis.Client
+}
+
+// NewQueue creates the consumer group of the stream if it does not exist and returns redis stream work queue,
+// the stream key is prefixed like store keys
+func NewQueue(m envvars.Redis, environment string, cfg envvars.Queue) (Queue, error) {
+	q := &queue{
+		stream:      fmt.Sprintf("%s:%s:%s", m.KeyPrefix, environment, cfg.Stream),
+		group:       cfg.Group,
+		block:       cfg.Block,
+		reclaimIdle: cfg.ReclaimIdle,
+	}
+
+	c := redis.NewClient(&redis.Options{
+		Addr:     m.Address,
+		Password: m.Password,
+		DB:       m.DB,
+	})
+
+	err := c.XGroupCreateMkStream(context.Background(), q.stream, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, err
+	}
+
+	q.c = c
+
+	return q, nil
+}
+
+// Publish appends messages to the stream in a single pipelined round trip
+func (q *queue) Publish(ctx context.Context, messageIDs ...int64) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	_, err := q.c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, id := range messageIDs {
+			p.XAdd(ctx, &redis.XAddArgs{
+				Stream: q.stream,
+				Values: map[string]interface{}{messageIDField: id},
+			})
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// Consume reads new entries for the consumer, blocking up to the configured duration when the stream is empty
+func (q *queue) Consume(ctx context.Context, consumer string, count int64) ([]QueueEntry, error) {
+	streams, err := q.c.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: consumer,
+		Streams:  []string{q.stream, ">"},
+		Count:    count,
+		Block:    q.block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []QueueEntry
+	for _, stream := range streams {
+		entries = append(entries, toQueueEntries(stream.Messages)...)
+	}
+
+	return entries, nil
+}
+
+// Reclaim takes over entries left unacknowledged by any consumer longer than the configured idle time,
+// e.g. by a crashed worker
+func (q *queue) Reclaim(ctx context.Context, consumer string, count int64) ([]QueueEntry, error) {
+	messages, _, err := q.c.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		Consumer: consumer,
+		MinIdle:  q.reclaimIdle,
+		Start:    "0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return toQueueEntries(messages), nil
+}
+
+// Ack acknowledges entries and removes them from the stream
+func (q *queue) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := q.c.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.XAck(ctx, q.stream, q.group, ids...)
+		p.XDel(ctx, q.stream, ids...)
+
+		return nil
+	})
+
+	return err
+}
+
+// Len returns the number of entries that are not acknowledged yet, read or not
+func (q *queue) Len(ctx context.Context) (int64, error) {
+	return q.c.XLen(ctx, q.stream).Result()
+}
+
+func (q *queue) Close() error {
+	return q.c.Close()
+}
+
+func toQueueEntries(messages []redis.XMessage) []QueueEntry {
+	entries := make([]QueueEntry, 0, len(messages))
+	for _, message := range messages {
+		entry := QueueEntry{ID: message.ID}
+
+		if value, ok := message.Values[messageIDField].(string); ok {
+			entry.MessageID, _ = strconv.ParseInt(value, 10, 64)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
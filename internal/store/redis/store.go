@@ -3,12 +3,22 @@ package redisstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	envvars "notify-hub-backend/configs/env-vars"
+	"notify-hub-backend/internal/tenant"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// SchemaVersion is the version of values written by the store, values of another version are read as missing
+// so callers rebuild them in the current format
+const SchemaVersion = 1
+
+// ErrNoTenant is returned for keys of a context carrying neither a tenant nor the tenant.HubWide mark
+var ErrNoTenant = errors.New("key has no tenant")
+
 type RedisMessage struct {
 	Contents []RedisMessageContent
 }
@@ -19,11 +29,29 @@ type RedisMessageContent struct {
 	Content     string
 }
 
+type RedisSuppression struct {
+	Recipient  string
+	Suppressed bool
+	Reason     string
+}
+
+// envelope represents a stored value with its schema version
+type envelope struct {
+	Version int             `json:"v"`
+	Data    json.RawMessage `json:"data"`
+}
+
 // Store defines behaviors of redis store
 type Store interface {
-	Set(string, interface{}) error
-	Get(key string, dest interface{}) error
-	Hset(string, ...interface{}) error
+	Set(ctx context.Context, key string, value interface{}) error
+	Get(ctx context.Context, key string, dest interface{}) error
+	MGet(ctx context.Context, keys []string, dests []interface{}) ([]bool, error)
+	MSet(ctx context.Context, values map[string]interface{}) error
+	Hset(ctx context.Context, key string, values ...interface{}) error
+	Del(ctx context.Context, keys ...string) error
+	IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+	TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -33,16 +61,19 @@ type store struct {
 	password string
 	db       int
 	expiry   time.Duration
+	prefix   string
 	c        *redis.Client
 }
 
-// NewStore creates and returns redis store
-func NewStore(m envvars.Redis) (Store, error) {
+// NewStore creates and returns redis store, keys are prefixed with the configured prefix and the environment, and with
+// the tenant of the context. Keys of contexts without a tenant are refused unless they are marked with tenant.HubWide.
+func NewStore(m envvars.Redis, environment string) (Store, error) {
 	s := &store{
 		address:  m.Address,
 		password: m.Password,
 		db:       m.DB,
 		expiry:   m.Expiry,
+		prefix:   fmt.Sprintf("%s:%s:", m.KeyPrefix, environment),
 	}
 
 	c := redis.NewClient(&redis.Options{
@@ -60,35 +91,211 @@ func NewStore(m envvars.Redis) (Store, error) {
 	return s, nil
 }
 
-func (s *store) Hset(key string, values ...interface{}) error {
-	res := s.c.HSet(context.Background(), key, values)
+func (s *store) Hset(ctx context.Context, key string, values ...interface{}) error {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	res := s.c.HSet(ctx, key, values...)
 
 	return res.Err()
 }
 
-func (s *store) Set(key string, value interface{}) error {
-	// Serialize the value to JSON
-	data, err := json.Marshal(value)
+func (s *store) Set(ctx context.Context, key string, value interface{}) error {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := encode(value)
 	if err != nil {
 		return err
 	}
 
-	res := s.c.Set(context.Background(), key, data, s.expiry)
+	res := s.c.Set(ctx, key, data, s.expiry)
 	return res.Err()
 }
 
-func (s *store) Get(key string, dest interface{}) error {
-	res := s.c.Get(context.Background(), key)
+func (s *store) Get(ctx context.Context, key string, dest interface{}) error {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	res := s.c.Get(ctx, key)
 	if res.Err() == redis.Nil {
 		return nil
 	} else if res.Err() != nil {
 		return res.Err()
 	}
 
-	jsonValue := res.Val()
-	return json.Unmarshal([]byte(jsonValue), dest)
+	_, err = decode(res.Val(), dest)
+	return err
+}
+
+// MGet reads keys in a single round trip and decodes each value into the dest at the same index,
+// the returned flags report which keys were found
+func (s *store) MGet(ctx context.Context, keys []string, dests []interface{}) ([]bool, error) {
+	if len(keys) != len(dests) {
+		return nil, fmt.Errorf("mget: %d keys and %d destinations", len(keys), len(dests))
+	}
+
+	found := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	prefixed, err := s.keys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := s.c.MGet(ctx, prefixed...)
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+
+	for i, value := range res.Val() {
+		jsonValue, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		ok, err := decode(jsonValue, dests[i])
+		if err != nil {
+			return nil, err
+		}
+
+		found[i] = ok
+	}
+
+	return found, nil
+}
+
+// MSet writes values with the store expiry in a single pipelined round trip
+func (s *store) MSet(ctx context.Context, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_, err := s.c.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for key, value := range values {
+			key, err := s.key(ctx, key)
+			if err != nil {
+				return err
+			}
+
+			data, err := encode(value)
+			if err != nil {
+				return err
+			}
+
+			p.Set(ctx, key, data, s.expiry)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *store) Del(ctx context.Context, keys ...string) error {
+	prefixed, err := s.keys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	res := s.c.Del(ctx, prefixed...)
+	return res.Err()
+}
+
+// IncrBy adds n to the counter of key and returns the new value, the counter expires ttl after its last change
+func (s *store) IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	key, err := s.key(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	var incr *redis.IntCmd
+	_, err = s.c.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		incr = p.IncrBy(ctx, key, n)
+		p.Expire(ctx, key, ttl)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (s *store) Ping(ctx context.Context) error {
+	return s.c.Ping(ctx).Err()
 }
 
 func (s *store) Close() error {
 	return s.c.Close()
 }
+
+// key prefixes key, keys of a tenant are prefixed with the tenant so that tenants never read each other's values. Keys
+// of a context marked with tenant.HubWide are shared by every tenant, other contexts without a tenant are refused with
+// ErrNoTenant so that a caller that lost its tenant never reads the values of another tenant.
+func (s *store) key(ctx context.Context, key string) (string, error) {
+	if id, ok := tenant.From(ctx); ok {
+		return s.prefix + "tenant:" + id + ":" + key, nil
+	}
+
+	if tenant.IsHubWide(ctx) {
+		return s.prefix + key, nil
+	}
+
+	return "", ErrNoTenant
+}
+
+func (s *store) keys(ctx context.Context, keys []string) ([]string, error) {
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key, err := s.key(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixed = append(prefixed, key)
+	}
+
+	return prefixed, nil
+}
+
+// encode serializes the value to JSON inside an envelope of the current schema version
+func encode(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(envelope{
+		Version: SchemaVersion,
+		Data:    data,
+	})
+}
+
+// decode deserializes an envelope into dest, it reports false without touching dest when the value is of another
+// schema version
+func decode(value string, dest interface{}) (bool, error) {
+	var e envelope
+	if err := json.Unmarshal([]byte(value), &e); err != nil {
+		return false, err
+	}
+
+	if e.Version != SchemaVersion {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(e.Data, dest); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,92 @@
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"notify-hub-backend/internal/tenant"
+)
+
+func TestDecode(t *testing.T) {
+	current, err := encode(RedisSuppression{Recipient: "+905325008081", Suppressed: true})
+	if err != nil {
+		t.Fatalf("encode error = %v", err)
+	}
+
+	older, _ := json.Marshal(envelope{Version: SchemaVersion - 1, Data: json.RawMessage(`{"Recipient":"+905325008082"}`)})
+
+	tests := []struct {
+		name      string
+		value     string
+		found     bool
+		recipient string
+		wantErr   bool
+	}{
+		{name: "current version", value: string(current), found: true, recipient: "+905325008081"},
+		{name: "older version", value: string(older)},
+		{name: "not an envelope", value: "plain", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RedisSuppression
+			found, err := decode(tt.value, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decode error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if found != tt.found || got.Recipient != tt.recipient {
+				t.Errorf("decode = %v, %+v, want %v and recipient %q", found, got, tt.found, tt.recipient)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	s := &store{prefix: "notify-hub:dev:"}
+	background := context.Background()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []string
+		err  error
+	}{
+		{
+			name: "tenant",
+			ctx:  tenant.With(background, "acme"),
+			want: []string{"notify-hub:dev:tenant:acme:message:1", "notify-hub:dev:tenant:acme:message:2"},
+		},
+		{
+			name: "hub wide",
+			ctx:  tenant.HubWide(background),
+			want: []string{"notify-hub:dev:message:1", "notify-hub:dev:message:2"},
+		},
+		{
+			name: "tenant of hub wide",
+			ctx:  tenant.With(tenant.HubWide(background), "acme"),
+			want: []string{"notify-hub:dev:tenant:acme:message:1", "notify-hub:dev:tenant:acme:message:2"},
+		},
+		{
+			name: "no tenant",
+			ctx:  background,
+			err:  ErrNoTenant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.keys(tt.ctx, []string{"message:1", "message:2"})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("keys error = %v, want %v", err, tt.err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
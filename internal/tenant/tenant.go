@@ -0,0 +1,49 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of data that existed before tenants, and of every caller when authentication is disabled
+const Default = "default"
+
+// ids are written into redis keys, they are limited to characters that need no escaping
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type contextKey struct{}
+
+type hubWideKey struct{}
+
+// Valid reports whether id can be used as a tenant id
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// With returns a copy of ctx carrying the tenant id, stores scope their queries to it
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From returns the tenant of ctx, contexts of background jobs working across tenants carry none
+func From(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// HubWide returns a copy of ctx of a caller working across tenants, e.g. the sending job or a provider webhook. Stores
+// refuse queries of contexts carrying neither a tenant nor this mark, a tenant set with With afterwards takes precedence.
+func HubWide(ctx context.Context) context.Context {
+	return context.WithValue(ctx, hubWideKey{}, true)
+}
+
+// IsHubWide reports whether ctx is of a caller working across tenants, i.e. it is marked with HubWide and carries no
+// tenant
+func IsHubWide(ctx context.Context) bool {
+	if _, ok := From(ctx); ok {
+		return false
+	}
+
+	hubWide, _ := ctx.Value(hubWideKey{}).(bool)
+	return hubWide
+}
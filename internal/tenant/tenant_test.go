@@ -0,0 +1,53 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "payments", want: true},
+		{id: "growth-tr_2", want: true},
+		{id: "", want: false},
+		{id: "pay ments", want: false},
+		{id: "payments:1", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Valid(tt.id); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestHubWide(t *testing.T) {
+	background := context.Background()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		tenant  string
+		hubWide bool
+	}{
+		{name: "background", ctx: background},
+		{name: "tenant", ctx: With(background, "payments"), tenant: "payments"},
+		{name: "hub wide", ctx: HubWide(background), hubWide: true},
+		{name: "tenant of hub wide", ctx: With(HubWide(background), "payments"), tenant: "payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if id, _ := From(tt.ctx); id != tt.tenant {
+				t.Errorf("From = %q, want %q", id, tt.tenant)
+			}
+
+			if got := IsHubWide(tt.ctx); got != tt.hubWide {
+				t.Errorf("IsHubWide = %v, want %v", got, tt.hubWide)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package httptransport
+
+import rest "notify-hub-backend"
+
+// requestError represents a request that can not be decoded or is not valid, errType is the error type written to
+// the client
+type requestError struct {
+	errType string
+	err     error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// invalidRequest returns err as a request whose header, query, path or body can not be decoded
+func invalidRequest(err error) error {
+	return &requestError{errType: rest.ErrorTypeInvalidRequest, err: err}
+}
+
+// validationFailed returns err as a request that is decoded but does not pass validation
+func validationFailed(err error) error {
+	return &requestError{errType: rest.ErrorTypeValidation, err: err}
+}
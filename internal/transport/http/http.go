@@ -8,9 +8,13 @@ import (
 	"net/http"
 	rest "notify-hub-backend"
 	service "notify-hub-backend"
+	"notify-hub-backend/internal/auth"
 	"notify-hub-backend/internal/endpoints"
+	"notify-hub-backend/internal/limit"
+	"notify-hub-backend/internal/phone"
 	"notify-hub-backend/internal/transport"
 	"reflect"
+	"strconv"
 
 	"github.com/go-kit/kit/endpoint"
 	kithttp "github.com/go-kit/kit/transport/http"
@@ -25,38 +29,281 @@ import (
 const (
 	health            = "Health"
 	switchAutoSend    = "SwitchAutoSend"
+	createMessage     = "CreateMessage"
 	fetchSentMessages = "FetchSentMessages"
+	addSuppression    = "AddSuppression"
+	removeSuppression = "RemoveSuppression"
+	fetchSuppressions = "FetchSuppressions"
+
+	receiveInboundMessage = "ReceiveInboundMessage"
+	fetchInboundMessages  = "FetchInboundMessages"
+
+	createContact = "CreateContact"
+	fetchContacts = "FetchContacts"
+	fetchContact  = "FetchContact"
+	updateContact = "UpdateContact"
+	deleteContact = "DeleteContact"
+	createGroup   = "CreateGroup"
+	fetchGroups   = "FetchGroups"
+	fetchGroup    = "FetchGroup"
+	updateGroup   = "UpdateGroup"
+	deleteGroup   = "DeleteGroup"
+
+	createCampaign = "CreateCampaign"
+	fetchCampaign  = "FetchCampaign"
+	pauseCampaign  = "PauseCampaign"
+	resumeCampaign = "ResumeCampaign"
+	abortCampaign  = "AbortCampaign"
+
+	fetchMessage           = "FetchMessage"
+	receiveDeliveryReceipt = "ReceiveDeliveryReceipt"
+
+	fetchAutoSendHistory = "FetchAutoSendHistory"
+
+	setSendingState    = "SetSendingState"
+	fetchSendingStates = "FetchSendingStates"
+
+	fetchSchedules = "FetchSchedules"
+	updateSchedule = "UpdateSchedule"
+
+	fetchJobRuns = "FetchJobRuns"
+
+	livez  = "Livez"
+	readyz = "Readyz"
+
+	createAPIKey = "CreateAPIKey"
+	fetchAPIKeys = "FetchAPIKeys"
+	rotateAPIKey = "RotateAPIKey"
+	revokeAPIKey = "RevokeAPIKey"
 )
 
 // decoder tags
 const (
 	headerTag = "header"
 	queryTag  = "query"
+	pathTag   = "path"
 )
 
-const invalidResponseError = "invalid response"
+// validation tags
+const (
+	phoneTag = "phone"
+)
+
+// error messages
+const (
+	invalidResponseError  = "invalid response"
+	routeNotFoundError    = "route not found"
+	methodNotAllowedError = "method not allowed"
+)
 
-// MakeHTTPHandler makes and returns http handler
-func MakeHTTPHandler(l log.Logger, s service.Service) http.Handler {
+// MakeHTTPHandler makes and returns http handler, endpoints require credentials authenticated by a unless it is nil
+// and run for the default tenant then. Requests of a tenant are limited by lim unless it is nil.
+func MakeHTTPHandler(l log.Logger, s service.Service, a auth.Authenticator, lim *limit.Limiter) http.Handler {
 	es := endpoints.MakeEndpoints(s)
+	if lim != nil {
+		es = endpoints.Limit(es, lim)
+	}
+
+	if a != nil {
+		es = endpoints.Authorize(es, a)
+	} else {
+		es = endpoints.DefaultTenant(es)
+	}
 
 	r := mux.NewRouter()
+	r.NotFoundHandler = http.HandlerFunc(notFoundHandler)
+	r.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowedHandler)
 
 	// health GET /health
 	r.Methods("GET").Path("/health").Handler(
 		makeHealthHandler(es.HealthEndpoint, makeDefaultServerOptions(l, health)),
 	)
 
+	// Livez GET /livez
+	r.Methods(http.MethodGet).Path("/livez").Handler(
+		makeLivezHandler(es.LivezEndpoint, makeDefaultServerOptions(l, livez)),
+	)
+
+	// Readyz GET /readyz
+	r.Methods(http.MethodGet).Path("/readyz").Handler(
+		makeReadyzHandler(es.ReadyzEndpoint, makeDefaultServerOptions(l, readyz)),
+	)
+
 	// SwitchAutoSend POST /switch-auto-send
 	r.Methods(http.MethodPost).Path("/switch-auto-send").Handler(
 		makeSwitchAutoSendHandler(es.SwitchAutoSendEndpoint, makeDefaultServerOptions(l, switchAutoSend)),
 	)
 
+	// CreateMessage POST /messages
+	r.Methods(http.MethodPost).Path("/messages").Handler(
+		makeCreateMessageHandler(es.CreateMessageEndpoint, makeDefaultServerOptions(l, createMessage)),
+	)
+
 	// FetchSentMessages GET /fetch-sent-messages
 	r.Methods(http.MethodGet).Path("/fetch-sent-messages").Handler(
 		makeFetchSentMessagesHandler(es.FetchSentMessagesEndpoint, makeDefaultServerOptions(l, fetchSentMessages)),
 	)
 
+	// AddSuppression POST /suppressions
+	r.Methods(http.MethodPost).Path("/suppressions").Handler(
+		makeAddSuppressionHandler(es.AddSuppressionEndpoint, makeDefaultServerOptions(l, addSuppression)),
+	)
+
+	// RemoveSuppression DELETE /suppressions/{recipient}
+	r.Methods(http.MethodDelete).Path("/suppressions/{recipient}").Handler(
+		makeRemoveSuppressionHandler(es.RemoveSuppressionEndpoint, makeDefaultServerOptions(l, removeSuppression)),
+	)
+
+	// FetchSuppressions GET /suppressions
+	r.Methods(http.MethodGet).Path("/suppressions").Handler(
+		makeFetchSuppressionsHandler(es.FetchSuppressionsEndpoint, makeDefaultServerOptions(l, fetchSuppressions)),
+	)
+
+	// ReceiveInboundMessage POST /inbound-messages
+	r.Methods(http.MethodPost).Path("/inbound-messages").Handler(
+		makeReceiveInboundMessageHandler(es.ReceiveInboundMessageEndpoint, makeDefaultServerOptions(l, receiveInboundMessage)),
+	)
+
+	// FetchInboundMessages GET /inbound-messages
+	r.Methods(http.MethodGet).Path("/inbound-messages").Handler(
+		makeFetchInboundMessagesHandler(es.FetchInboundMessagesEndpoint, makeDefaultServerOptions(l, fetchInboundMessages)),
+	)
+
+	// CreateContact POST /contacts
+	r.Methods(http.MethodPost).Path("/contacts").Handler(
+		makeCreateContactHandler(es.CreateContactEndpoint, makeDefaultServerOptions(l, createContact)),
+	)
+
+	// FetchContacts GET /contacts
+	r.Methods(http.MethodGet).Path("/contacts").Handler(
+		makeFetchContactsHandler(es.FetchContactsEndpoint, makeDefaultServerOptions(l, fetchContacts)),
+	)
+
+	// FetchContact GET /contacts/{id}
+	r.Methods(http.MethodGet).Path("/contacts/{id}").Handler(
+		makeFetchContactHandler(es.FetchContactEndpoint, makeDefaultServerOptions(l, fetchContact)),
+	)
+
+	// UpdateContact PUT /contacts/{id}
+	r.Methods(http.MethodPut).Path("/contacts/{id}").Handler(
+		makeUpdateContactHandler(es.UpdateContactEndpoint, makeDefaultServerOptions(l, updateContact)),
+	)
+
+	// DeleteContact DELETE /contacts/{id}
+	r.Methods(http.MethodDelete).Path("/contacts/{id}").Handler(
+		makeDeleteContactHandler(es.DeleteContactEndpoint, makeDefaultServerOptions(l, deleteContact)),
+	)
+
+	// CreateGroup POST /groups
+	r.Methods(http.MethodPost).Path("/groups").Handler(
+		makeCreateGroupHandler(es.CreateGroupEndpoint, makeDefaultServerOptions(l, createGroup)),
+	)
+
+	// FetchGroups GET /groups
+	r.Methods(http.MethodGet).Path("/groups").Handler(
+		makeFetchGroupsHandler(es.FetchGroupsEndpoint, makeDefaultServerOptions(l, fetchGroups)),
+	)
+
+	// FetchGroup GET /groups/{id}
+	r.Methods(http.MethodGet).Path("/groups/{id}").Handler(
+		makeFetchGroupHandler(es.FetchGroupEndpoint, makeDefaultServerOptions(l, fetchGroup)),
+	)
+
+	// UpdateGroup PUT /groups/{id}
+	r.Methods(http.MethodPut).Path("/groups/{id}").Handler(
+		makeUpdateGroupHandler(es.UpdateGroupEndpoint, makeDefaultServerOptions(l, updateGroup)),
+	)
+
+	// DeleteGroup DELETE /groups/{id}
+	r.Methods(http.MethodDelete).Path("/groups/{id}").Handler(
+		makeDeleteGroupHandler(es.DeleteGroupEndpoint, makeDefaultServerOptions(l, deleteGroup)),
+	)
+
+	// CreateCampaign POST /campaigns
+	r.Methods(http.MethodPost).Path("/campaigns").Handler(
+		makeCreateCampaignHandler(es.CreateCampaignEndpoint, makeDefaultServerOptions(l, createCampaign)),
+	)
+
+	// FetchCampaign GET /campaigns/{id}
+	r.Methods(http.MethodGet).Path("/campaigns/{id}").Handler(
+		makeFetchCampaignHandler(es.FetchCampaignEndpoint, makeDefaultServerOptions(l, fetchCampaign)),
+	)
+
+	// PauseCampaign POST /campaigns/{id}/pause
+	r.Methods(http.MethodPost).Path("/campaigns/{id}/pause").Handler(
+		makePauseCampaignHandler(es.PauseCampaignEndpoint, makeDefaultServerOptions(l, pauseCampaign)),
+	)
+
+	// ResumeCampaign POST /campaigns/{id}/resume
+	r.Methods(http.MethodPost).Path("/campaigns/{id}/resume").Handler(
+		makeResumeCampaignHandler(es.ResumeCampaignEndpoint, makeDefaultServerOptions(l, resumeCampaign)),
+	)
+
+	// AbortCampaign POST /campaigns/{id}/abort
+	r.Methods(http.MethodPost).Path("/campaigns/{id}/abort").Handler(
+		makeAbortCampaignHandler(es.AbortCampaignEndpoint, makeDefaultServerOptions(l, abortCampaign)),
+	)
+
+	// FetchMessage GET /messages/{id}
+	r.Methods(http.MethodGet).Path("/messages/{id}").Handler(
+		makeFetchMessageHandler(es.FetchMessageEndpoint, makeDefaultServerOptions(l, fetchMessage)),
+	)
+
+	// ReceiveDeliveryReceipt POST /delivery-receipts
+	r.Methods(http.MethodPost).Path("/delivery-receipts").Handler(
+		makeReceiveDeliveryReceiptHandler(es.ReceiveDeliveryReceiptEndpoint, makeDefaultServerOptions(l, receiveDeliveryReceipt)),
+	)
+
+	// FetchAutoSendHistory GET /switch-auto-send/history
+	r.Methods(http.MethodGet).Path("/switch-auto-send/history").Handler(
+		makeFetchAutoSendHistoryHandler(es.FetchAutoSendHistoryEndpoint, makeDefaultServerOptions(l, fetchAutoSendHistory)),
+	)
+
+	// SetSendingState PUT /sending/state
+	r.Methods(http.MethodPut).Path("/sending/state").Handler(
+		makeSetSendingStateHandler(es.SetSendingStateEndpoint, makeDefaultServerOptions(l, setSendingState)),
+	)
+
+	// FetchSendingStates GET /sending/state
+	r.Methods(http.MethodGet).Path("/sending/state").Handler(
+		makeFetchSendingStatesHandler(es.FetchSendingStatesEndpoint, makeDefaultServerOptions(l, fetchSendingStates)),
+	)
+
+	// FetchSchedules GET /schedules
+	r.Methods(http.MethodGet).Path("/schedules").Handler(
+		makeFetchSchedulesHandler(es.FetchSchedulesEndpoint, makeDefaultServerOptions(l, fetchSchedules)),
+	)
+
+	// UpdateSchedule PUT /schedules/{name}
+	r.Methods(http.MethodPut).Path("/schedules/{name}").Handler(
+		makeUpdateScheduleHandler(es.UpdateScheduleEndpoint, makeDefaultServerOptions(l, updateSchedule)),
+	)
+
+	// FetchJobRuns GET /schedules/{name}/runs
+	r.Methods(http.MethodGet).Path("/schedules/{name}/runs").Handler(
+		makeFetchJobRunsHandler(es.FetchJobRunsEndpoint, makeDefaultServerOptions(l, fetchJobRuns)),
+	)
+
+	// CreateAPIKey POST /api-keys
+	r.Methods(http.MethodPost).Path("/api-keys").Handler(
+		makeCreateAPIKeyHandler(es.CreateAPIKeyEndpoint, makeDefaultServerOptions(l, createAPIKey)),
+	)
+
+	// FetchAPIKeys GET /api-keys
+	r.Methods(http.MethodGet).Path("/api-keys").Handler(
+		makeFetchAPIKeysHandler(es.FetchAPIKeysEndpoint, makeDefaultServerOptions(l, fetchAPIKeys)),
+	)
+
+	// RotateAPIKey POST /api-keys/{id}/rotate
+	r.Methods(http.MethodPost).Path("/api-keys/{id}/rotate").Handler(
+		makeRotateAPIKeyHandler(es.RotateAPIKeyEndpoint, makeDefaultServerOptions(l, rotateAPIKey)),
+	)
+
+	// RevokeAPIKey DELETE /api-keys/{id}
+	r.Methods(http.MethodDelete).Path("/api-keys/{id}").Handler(
+		makeRevokeAPIKeyHandler(es.RevokeAPIKeyEndpoint, makeDefaultServerOptions(l, revokeAPIKey)),
+	)
+
 	// services docs
 	// swagger router
 	swaggerRouter := r.PathPrefix("/docs").Subrouter()
@@ -94,14 +341,191 @@ func makeSwitchAutoSendHandler(e endpoint.Endpoint, serverOption []kithttp.Serve
 	return h
 }
 
+func makeCreateMessageHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.CreateMessageRequest{}), encoder, serverOption...)
+	return h
+}
+
 func makeFetchSentMessagesHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
 	h := kithttp.NewServer(e, makeDecoder(rest.FetchSentMessagesRequest{}), encoder, serverOption...)
 	return h
 }
 
+func makeAddSuppressionHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.AddSuppressionRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeRemoveSuppressionHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.RemoveSuppressionRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchSuppressionsHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchSuppressionsRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeReceiveInboundMessageHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.ReceiveInboundMessageRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchInboundMessagesHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchInboundMessagesRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeCreateContactHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.CreateContactRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchContactsHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchContactsRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchContactHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchContactRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeUpdateContactHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.UpdateContactRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeDeleteContactHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.DeleteContactRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeCreateGroupHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.CreateGroupRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchGroupsHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchGroupsRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchGroupHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchGroupRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeUpdateGroupHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.UpdateGroupRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeDeleteGroupHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.DeleteGroupRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeCreateCampaignHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.CreateCampaignRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchCampaignHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchCampaignRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makePauseCampaignHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.PauseCampaignRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeResumeCampaignHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.ResumeCampaignRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeAbortCampaignHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.AbortCampaignRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchMessageHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchMessageRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeReceiveDeliveryReceiptHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.ReceiveDeliveryReceiptRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchAutoSendHistoryHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchAutoSendHistoryRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeSetSendingStateHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.SetSendingStateRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchSendingStatesHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchSendingStatesRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchSchedulesHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchSchedulesRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeUpdateScheduleHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.UpdateScheduleRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchJobRunsHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchJobRunsRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeLivezHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.LivezRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeReadyzHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.ReadyzRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeCreateAPIKeyHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.CreateAPIKeyRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeFetchAPIKeysHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.FetchAPIKeysRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeRotateAPIKeyHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.RotateAPIKeyRequest{}), encoder, serverOption...)
+	return h
+}
+
+func makeRevokeAPIKeyHandler(e endpoint.Endpoint, serverOption []kithttp.ServerOption) http.Handler {
+	h := kithttp.NewServer(e, makeDecoder(rest.RevokeAPIKeyRequest{}), encoder, serverOption...)
+	return h
+}
+
 func makeDefaultServerOptions(l log.Logger, endpointName string) []kithttp.ServerOption {
 	return []kithttp.ServerOption{
 		kithttp.ServerErrorHandler(transport.NewErrorHandler(l, endpointName)),
+		kithttp.ServerErrorEncoder(errorEncoder),
+		kithttp.ServerBefore(auth.HTTPToContext()),
 	}
 }
 
@@ -110,16 +534,20 @@ func makeDecoder(emptyReq interface{}) kithttp.DecodeRequestFunc {
 		req := reflect.New(reflect.TypeOf(emptyReq)).Interface()
 
 		if err := newHeaderDecoder().Decode(req, r.Header); err != nil {
-			return nil, fmt.Errorf("decoding request header failed, %s", err.Error())
+			return nil, invalidRequest(fmt.Errorf("decoding request header failed, %s", err.Error()))
 		}
 
 		if err := newQueryDecoder().Decode(req, r.URL.Query()); err != nil {
-			return nil, fmt.Errorf("decoding request query failed, %s", err.Error())
+			return nil, invalidRequest(fmt.Errorf("decoding request query failed, %s", err.Error()))
+		}
+
+		if err := newPathDecoder().Decode(req, pathValues(r)); err != nil {
+			return nil, invalidRequest(fmt.Errorf("decoding request path failed, %s", err.Error()))
 		}
 
 		if requestHasBody(r) {
 			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-				return nil, fmt.Errorf("decoding request body failed, %s", err.Error())
+				return nil, invalidRequest(fmt.Errorf("decoding request body failed, %s", err.Error()))
 			}
 		}
 
@@ -139,6 +567,10 @@ func newQueryDecoder() *schema.Decoder {
 	return newDecoder(queryTag)
 }
 
+func newPathDecoder() *schema.Decoder {
+	return newDecoder(pathTag)
+}
+
 func newDecoder(tag string) *schema.Decoder {
 	decoder := schema.NewDecoder()
 	decoder.IgnoreUnknownKeys(true)
@@ -150,29 +582,169 @@ func newDecoder(tag string) *schema.Decoder {
 	return decoder
 }
 
+func pathValues(r *http.Request) map[string][]string {
+	vars := mux.Vars(r)
+
+	values := make(map[string][]string, len(vars))
+	for k, v := range vars {
+		values[k] = []string{v}
+	}
+
+	return values
+}
+
 func requestHasBody(r *http.Request) bool {
 	return r.Body != http.NoBody
 }
 
 func validate(req interface{}) error {
-	errs := validator.New().Struct(req)
+	errs := newValidator().Struct(req)
 	if errs == nil {
 		return nil
 	}
 
 	firstErr := errs.(validator.ValidationErrors)[0]
 
-	return errors.New("validation failed, tag: " + firstErr.Tag() + ", field: " + firstErr.Field())
+	if firstErr.Tag() == phoneTag {
+		return validationFailed(errors.New("validation failed, tag: " + firstErr.Tag() + ", field: " + firstErr.Field() + ", value must be a phone number in international (+905321234567) or national (05321234567) format"))
+	}
+
+	return validationFailed(errors.New("validation failed, tag: " + firstErr.Tag() + ", field: " + firstErr.Field()))
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	_ = v.RegisterValidation(phoneTag, func(fl validator.FieldLevel) bool {
+		return phone.Valid(fl.Field().String())
+	})
+
+	return v
+}
+
+// statusCoder is implemented by responses that choose their http status code
+type statusCoder interface {
+	StatusCode() int
 }
 
+// encoder writes the response with the status code of its Result if it failed, the status code chosen by the
+// response or 202
 func encoder(_ context.Context, rw http.ResponseWriter, response interface{}) error {
 	r, ok := response.(rest.Response)
 	if !ok {
 		return errors.New(invalidResponseError)
 	}
-	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
-	rw.Header().Set("Access-Control-Allow-Origin", "*")
-	rw.WriteHeader(http.StatusAccepted)
+
+	code := http.StatusAccepted
+	if sc, ok := response.(statusCoder); ok {
+		code = sc.StatusCode()
+	}
+
+	if apiErr := resultOf(response); apiErr != nil {
+		if apiErr.Code < http.StatusBadRequest || apiErr.Code > 599 {
+			apiErr.Code = http.StatusInternalServerError
+		}
+
+		if apiErr.Type == "" {
+			apiErr.Type = rest.ErrorTypeOf(apiErr.Code)
+		}
+
+		setRetryAfter(rw, apiErr)
+
+		code = apiErr.Code
+	}
+
+	writeJSON(rw, code)
 
 	return json.NewEncoder(rw).Encode(r)
 }
+
+// resultOf returns the Result field of a response, responses report failures by setting it
+func resultOf(response interface{}) *rest.APIError {
+	v := reflect.Indirect(reflect.ValueOf(response))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	f := v.FieldByName("Result")
+	if !f.IsValid() {
+		return nil
+	}
+
+	apiErr, _ := f.Interface().(*rest.APIError)
+
+	return apiErr
+}
+
+// errorEncoder writes errors returned by decoders, endpoints and the encoder in the envelope of responses.
+// Decoding and validation failures are written with 400, authentication failures with 401 and 403, exceeded limits
+// with 429, other errors with 500.
+func errorEncoder(_ context.Context, err error, rw http.ResponseWriter) {
+	apiErr := &rest.APIError{
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+		Type:    rest.ErrorTypeInternal,
+	}
+
+	var reqErr *requestError
+	var exceeded *limit.Exceeded
+	switch {
+	case errors.As(err, &reqErr):
+		apiErr.Code = http.StatusBadRequest
+		apiErr.Type = reqErr.errType
+	case errors.Is(err, auth.ErrUnauthenticated):
+		apiErr.Code = http.StatusUnauthorized
+		apiErr.Type = rest.ErrorTypeUnauthorized
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+	case errors.Is(err, auth.ErrForbidden):
+		apiErr.Code = http.StatusForbidden
+		apiErr.Type = rest.ErrorTypeForbidden
+	case errors.As(err, &exceeded):
+		apiErr = exceeded.APIError()
+	}
+
+	writeError(rw, apiErr)
+}
+
+// notFoundHandler writes the error of requests that match no route
+func notFoundHandler(rw http.ResponseWriter, _ *http.Request) {
+	writeError(rw, &rest.APIError{
+		Message: routeNotFoundError,
+		Code:    http.StatusNotFound,
+		Type:    rest.ErrorTypeNotFound,
+	})
+}
+
+// methodNotAllowedHandler writes the error of requests that match a route but not its method
+func methodNotAllowedHandler(rw http.ResponseWriter, _ *http.Request) {
+	writeError(rw, &rest.APIError{
+		Message: methodNotAllowedError,
+		Code:    http.StatusMethodNotAllowed,
+		Type:    rest.ErrorTypeMethodNotAllowed,
+	})
+}
+
+func writeError(rw http.ResponseWriter, apiErr *rest.APIError) {
+	setRetryAfter(rw, apiErr)
+	writeJSON(rw, apiErr.Code)
+
+	_ = json.NewEncoder(rw).Encode(struct {
+		Data   interface{}    `json:"data"`
+		Result *rest.APIError `json:"result"`
+	}{
+		Result: apiErr,
+	})
+}
+
+// setRetryAfter tells clients when to retry a request that exceeded a limit
+func setRetryAfter(rw http.ResponseWriter, apiErr *rest.APIError) {
+	if apiErr.RetryAfter > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(apiErr.RetryAfter))
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, code int) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Header().Set("Access-Control-Allow-Origin", "*")
+	rw.WriteHeader(code)
+}
@@ -0,0 +1,134 @@
+package httptransport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rest "notify-hub-backend"
+)
+
+// envelope represents a written response, data is not decoded
+type envelope struct {
+	Data   json.RawMessage `json:"data"`
+	Result *rest.APIError  `json:"result"`
+}
+
+func decodeEnvelope(t *testing.T, rw *httptest.ResponseRecorder) envelope {
+	t.Helper()
+
+	var res envelope
+	if err := json.NewDecoder(rw.Body).Decode(&res); err != nil {
+		t.Fatalf("decoding response failed, %v", err)
+	}
+
+	return res
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		response interface{}
+		code     int
+		errType  string
+	}{
+		{name: "success", response: rest.FetchMessageResponse{}, code: http.StatusAccepted},
+		{
+			name:     "not found",
+			response: rest.FetchMessageResponse{Result: &rest.APIError{Message: "message not found", Code: http.StatusNotFound}},
+			code:     http.StatusNotFound,
+			errType:  rest.ErrorTypeNotFound,
+		},
+		{
+			name:     "conflict",
+			response: rest.FetchMessageResponse{Result: &rest.APIError{Message: "already exists", Code: http.StatusConflict, Type: rest.ErrorTypeConflict}},
+			code:     http.StatusConflict,
+			errType:  rest.ErrorTypeConflict,
+		},
+		{
+			name:     "code out of range",
+			response: rest.FetchMessageResponse{Result: &rest.APIError{Message: "failed"}},
+			code:     http.StatusInternalServerError,
+			errType:  rest.ErrorTypeInternal,
+		},
+		{name: "readiness", response: rest.ReadyzResponse{Status: rest.HealthStatusDown}, code: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			if err := encoder(context.Background(), rw, tt.response); err != nil {
+				t.Fatalf("encoder error = %v", err)
+			}
+
+			if rw.Code != tt.code {
+				t.Errorf("status = %d, want %d", rw.Code, tt.code)
+			}
+
+			if tt.errType == "" {
+				return
+			}
+
+			res := decodeEnvelope(t, rw)
+			if res.Result == nil || res.Result.Code != tt.code || res.Result.Type != tt.errType {
+				t.Errorf("result = %+v, want code %d of type %s", res.Result, tt.code, tt.errType)
+			}
+		})
+	}
+}
+
+func TestErrorEncoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		errType string
+	}{
+		{name: "invalid request", err: invalidRequest(errors.New("decoding request body failed")), code: http.StatusBadRequest, errType: rest.ErrorTypeInvalidRequest},
+		{name: "validation failed", err: validationFailed(errors.New("validation failed")), code: http.StatusBadRequest, errType: rest.ErrorTypeValidation},
+		{name: "other", err: errors.New("connection refused"), code: http.StatusInternalServerError, errType: rest.ErrorTypeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			errorEncoder(context.Background(), tt.err, rw)
+
+			res := decodeEnvelope(t, rw)
+			if rw.Code != tt.code || res.Result == nil || res.Result.Code != tt.code || res.Result.Type != tt.errType {
+				t.Errorf("status %d with result %+v, want code %d of type %s", rw.Code, res.Result, tt.code, tt.errType)
+			}
+		})
+	}
+}
+
+func TestDecoderErrorTypes(t *testing.T) {
+	decode := makeDecoder(rest.CreateMessageRequest{})
+
+	tests := []struct {
+		name    string
+		body    string
+		errType string
+	}{
+		{name: "malformed body", body: `{"recipient":`, errType: rest.ErrorTypeInvalidRequest},
+		{name: "invalid field", body: `{}`, errType: rest.ErrorTypeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			_, err := decode(context.Background(), r)
+
+			var reqErr *requestError
+			if !errors.As(err, &reqErr) || reqErr.errType != tt.errType {
+				t.Errorf("decode error = %v, want an error of type %s", err, tt.errType)
+			}
+		})
+	}
+}
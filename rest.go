@@ -2,15 +2,56 @@ package rest
 
 import (
 	"context"
+	"net/http"
 	"time"
 )
 
 // Service defines behaviors of sample service
 type Service interface {
 	Health(context.Context, HealthRequest) HealthResponse
+	Livez(context.Context, LivezRequest) LivezResponse
+	Readyz(context.Context, ReadyzRequest) ReadyzResponse
 	SwitchAutoSend(context.Context, SwitchAutoSendRequest) SwitchAutoSendResponse
-	CronSendMessage(context.Context) error
+	FetchAutoSendHistory(context.Context, FetchAutoSendHistoryRequest) FetchAutoSendHistoryResponse
+	SetSendingState(context.Context, SetSendingStateRequest) SetSendingStateResponse
+	FetchSendingStates(context.Context, FetchSendingStatesRequest) FetchSendingStatesResponse
+	SyncSchedules(context.Context) (int, error)
+	FetchSchedules(context.Context, FetchSchedulesRequest) FetchSchedulesResponse
+	UpdateSchedule(context.Context, UpdateScheduleRequest) UpdateScheduleResponse
+	FetchJobRuns(context.Context, FetchJobRunsRequest) FetchJobRunsResponse
+	PruneJobRuns(context.Context) (int, error)
+	CreateMessage(context.Context, CreateMessageRequest) CreateMessageResponse
+	CronSendMessage(context.Context) (int, error)
+	RelayOutbox(context.Context) (int, error)
+	ConsumeQueue(context.Context, string) error
+	Drain(context.Context) error
 	FetchSentMessages(context.Context, FetchSentMessagesRequest) FetchSentMessagesResponse
+	FetchMessage(context.Context, FetchMessageRequest) FetchMessageResponse
+	ReceiveDeliveryReceipt(context.Context, ReceiveDeliveryReceiptRequest) ReceiveDeliveryReceiptResponse
+	AddSuppression(context.Context, AddSuppressionRequest) AddSuppressionResponse
+	RemoveSuppression(context.Context, RemoveSuppressionRequest) RemoveSuppressionResponse
+	FetchSuppressions(context.Context, FetchSuppressionsRequest) FetchSuppressionsResponse
+	ReceiveInboundMessage(context.Context, ReceiveInboundMessageRequest) ReceiveInboundMessageResponse
+	FetchInboundMessages(context.Context, FetchInboundMessagesRequest) FetchInboundMessagesResponse
+	CreateContact(context.Context, CreateContactRequest) CreateContactResponse
+	UpdateContact(context.Context, UpdateContactRequest) UpdateContactResponse
+	DeleteContact(context.Context, DeleteContactRequest) DeleteContactResponse
+	FetchContact(context.Context, FetchContactRequest) FetchContactResponse
+	FetchContacts(context.Context, FetchContactsRequest) FetchContactsResponse
+	CreateGroup(context.Context, CreateGroupRequest) CreateGroupResponse
+	UpdateGroup(context.Context, UpdateGroupRequest) UpdateGroupResponse
+	DeleteGroup(context.Context, DeleteGroupRequest) DeleteGroupResponse
+	FetchGroup(context.Context, FetchGroupRequest) FetchGroupResponse
+	FetchGroups(context.Context, FetchGroupsRequest) FetchGroupsResponse
+	CreateCampaign(context.Context, CreateCampaignRequest) CreateCampaignResponse
+	FetchCampaign(context.Context, FetchCampaignRequest) FetchCampaignResponse
+	PauseCampaign(context.Context, PauseCampaignRequest) PauseCampaignResponse
+	ResumeCampaign(context.Context, ResumeCampaignRequest) ResumeCampaignResponse
+	AbortCampaign(context.Context, AbortCampaignRequest) AbortCampaignResponse
+	CreateAPIKey(context.Context, CreateAPIKeyRequest) CreateAPIKeyResponse
+	FetchAPIKeys(context.Context, FetchAPIKeysRequest) FetchAPIKeysResponse
+	RotateAPIKey(context.Context, RotateAPIKeyRequest) RotateAPIKeyResponse
+	RevokeAPIKey(context.Context, RevokeAPIKeyRequest) RevokeAPIKeyResponse
 }
 
 // Request defines behaviors of request
@@ -22,17 +63,127 @@ type Response interface{}
 // compile-time proofs of request interface implementation
 var (
 	_ Request = (*HealthRequest)(nil)
+	_ Request = (*LivezRequest)(nil)
+	_ Request = (*ReadyzRequest)(nil)
+	_ Request = (*SwitchAutoSendRequest)(nil)
+	_ Request = (*FetchAutoSendHistoryRequest)(nil)
+	_ Request = (*SetSendingStateRequest)(nil)
+	_ Request = (*FetchSendingStatesRequest)(nil)
+	_ Request = (*FetchSchedulesRequest)(nil)
+	_ Request = (*UpdateScheduleRequest)(nil)
+	_ Request = (*FetchJobRunsRequest)(nil)
+	_ Request = (*CreateMessageRequest)(nil)
+	_ Request = (*FetchMessageRequest)(nil)
+	_ Request = (*ReceiveDeliveryReceiptRequest)(nil)
+	_ Request = (*AddSuppressionRequest)(nil)
+	_ Request = (*RemoveSuppressionRequest)(nil)
+	_ Request = (*FetchSuppressionsRequest)(nil)
+	_ Request = (*ReceiveInboundMessageRequest)(nil)
+	_ Request = (*FetchInboundMessagesRequest)(nil)
+	_ Request = (*CreateContactRequest)(nil)
+	_ Request = (*UpdateContactRequest)(nil)
+	_ Request = (*DeleteContactRequest)(nil)
+	_ Request = (*FetchContactRequest)(nil)
+	_ Request = (*FetchContactsRequest)(nil)
+	_ Request = (*CreateGroupRequest)(nil)
+	_ Request = (*UpdateGroupRequest)(nil)
+	_ Request = (*DeleteGroupRequest)(nil)
+	_ Request = (*FetchGroupRequest)(nil)
+	_ Request = (*FetchGroupsRequest)(nil)
+	_ Request = (*CreateCampaignRequest)(nil)
+	_ Request = (*FetchCampaignRequest)(nil)
+	_ Request = (*PauseCampaignRequest)(nil)
+	_ Request = (*ResumeCampaignRequest)(nil)
+	_ Request = (*AbortCampaignRequest)(nil)
+	_ Request = (*CreateAPIKeyRequest)(nil)
+	_ Request = (*FetchAPIKeysRequest)(nil)
+	_ Request = (*RotateAPIKeyRequest)(nil)
+	_ Request = (*RevokeAPIKeyRequest)(nil)
 )
 
 // compile-time proofs of response interface implementation
 var (
+	_ Response = (*LivezResponse)(nil)
+	_ Response = (*ReadyzResponse)(nil)
 	_ Response = (*SwitchAutoSendResponse)(nil)
+	_ Response = (*FetchAutoSendHistoryResponse)(nil)
+	_ Response = (*SetSendingStateResponse)(nil)
+	_ Response = (*FetchSendingStatesResponse)(nil)
+	_ Response = (*FetchSchedulesResponse)(nil)
+	_ Response = (*UpdateScheduleResponse)(nil)
+	_ Response = (*FetchJobRunsResponse)(nil)
+	_ Response = (*CreateMessageResponse)(nil)
+	_ Response = (*FetchMessageResponse)(nil)
+	_ Response = (*ReceiveDeliveryReceiptResponse)(nil)
+	_ Response = (*AddSuppressionResponse)(nil)
+	_ Response = (*RemoveSuppressionResponse)(nil)
+	_ Response = (*FetchSuppressionsResponse)(nil)
+	_ Response = (*ReceiveInboundMessageResponse)(nil)
+	_ Response = (*FetchInboundMessagesResponse)(nil)
+	_ Response = (*CreateContactResponse)(nil)
+	_ Response = (*UpdateContactResponse)(nil)
+	_ Response = (*DeleteContactResponse)(nil)
+	_ Response = (*FetchContactResponse)(nil)
+	_ Response = (*FetchContactsResponse)(nil)
+	_ Response = (*CreateGroupResponse)(nil)
+	_ Response = (*UpdateGroupResponse)(nil)
+	_ Response = (*DeleteGroupResponse)(nil)
+	_ Response = (*FetchGroupResponse)(nil)
+	_ Response = (*FetchGroupsResponse)(nil)
+	_ Response = (*CreateCampaignResponse)(nil)
+	_ Response = (*FetchCampaignResponse)(nil)
+	_ Response = (*PauseCampaignResponse)(nil)
+	_ Response = (*ResumeCampaignResponse)(nil)
+	_ Response = (*AbortCampaignResponse)(nil)
+	_ Response = (*CreateAPIKeyResponse)(nil)
+	_ Response = (*FetchAPIKeysResponse)(nil)
+	_ Response = (*RotateAPIKeyResponse)(nil)
+	_ Response = (*RevokeAPIKeyResponse)(nil)
 )
 
-// APIError represents api error
+// error types of APIError, clients branch on the type since messages are meant for humans and may change
+const (
+	ErrorTypeInvalidRequest   = "invalid_request"
+	ErrorTypeValidation       = "validation_failed"
+	ErrorTypeUnauthorized     = "unauthorized"
+	ErrorTypeForbidden        = "forbidden"
+	ErrorTypeNotFound         = "not_found"
+	ErrorTypeMethodNotAllowed = "method_not_allowed"
+	ErrorTypeConflict         = "conflict"
+	ErrorTypeRateLimited      = "rate_limited"
+	ErrorTypeQuotaExceeded    = "quota_exceeded"
+	ErrorTypeInternal         = "internal"
+)
+
+// APIError represents api error, Code is the http status code of the response. RetryAfter is the number of seconds
+// to wait before retrying a request that exceeded a limit, it is sent in the Retry-After header as well.
 type APIError struct {
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Message    string `json:"message"`
+	Code       int    `json:"code"`
+	Type       string `json:"type"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+// ErrorTypeOf returns the error type of an http status code
+func ErrorTypeOf(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrorTypeValidation
+	case http.StatusUnauthorized:
+		return ErrorTypeUnauthorized
+	case http.StatusForbidden:
+		return ErrorTypeForbidden
+	case http.StatusNotFound:
+		return ErrorTypeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorTypeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrorTypeConflict
+	case http.StatusTooManyRequests:
+		return ErrorTypeRateLimited
+	default:
+		return ErrorTypeInternal
+	}
 }
 
 // HealthRequest and HealthResponse represents health request and response
@@ -41,12 +192,73 @@ type (
 	HealthResponse struct{}
 )
 
+// health statuses
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// dependency statuses of the readiness response
+const (
+	DependencyOK          = "ok"
+	DependencyUnavailable = "unavailable"
+)
+
+// LivezRequest and LivezResponse represents liveness request and response
+type (
+	LivezRequest struct{}
+
+	LivezResponse struct {
+		Status string `json:"status"`
+	}
+)
+
+// ReadyzRequest and ReadyzResponse represents readiness request and response, the response is written with 503 when
+// the service is not ready
+type (
+	ReadyzRequest struct{}
+
+	BacklogData struct {
+		Queued  *int64 `json:"queued"`
+		Sending *int64 `json:"sending"`
+		Stream  *int64 `json:"stream,omitempty"`
+	}
+
+	ReadyzResponse struct {
+		Status       string            `json:"status"`
+		Draining     bool              `json:"draining"`
+		Dependencies map[string]string `json:"dependencies"`
+		Backlog      BacklogData       `json:"backlog"`
+		LastSentAt   *time.Time        `json:"lastSentAt"`
+	}
+)
+
+// StatusCode returns the http status code of the readiness response
+func (r ReadyzResponse) StatusCode() int {
+	if r.Status != HealthStatusUp {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+// StatusCode returns the http status code of the liveness response
+func (r LivezResponse) StatusCode() int {
+	return http.StatusOK
+}
+
 // SwitchAutoSendRequest and SwitchAutoSendResponse represents switch auto send request and response
 type (
-	SwitchAutoSendRequest struct{}
+	SwitchAutoSendRequest struct {
+		Operator string `json:"-" header:"x-ins-operator"`
+		Reason   string `json:"reason"`
+	}
 
 	SwitchAutoSendData struct {
-		AutoSendOn bool `json:"autoSendOn"`
+		AutoSendOn bool      `json:"autoSendOn"`
+		Reason     string    `json:"reason"`
+		ChangedBy  string    `json:"changedBy"`
+		ChangedAt  time.Time `json:"changedAt"`
 	}
 
 	SwitchAutoSendResponse struct {
@@ -55,17 +267,184 @@ type (
 	}
 )
 
+// SetSendingStateRequest and SetSendingStateResponse represents set sending state request and response
+type (
+	SetSendingStateRequest struct {
+		Operator string     `json:"-" header:"x-ins-operator"`
+		Scope    string     `json:"scope"`
+		Enabled  *bool      `json:"enabled" validate:"required"`
+		Reason   string     `json:"reason"`
+		ResumeAt *time.Time `json:"resumeAt"`
+	}
+
+	SendingStateData struct {
+		Scope     string     `json:"scope"`
+		Enabled   bool       `json:"enabled"`
+		Reason    string     `json:"reason"`
+		ResumeAt  *time.Time `json:"resumeAt"`
+		ChangedBy string     `json:"changedBy"`
+		ChangedAt time.Time  `json:"changedAt"`
+	}
+
+	SetSendingStateResponse struct {
+		Data   *SendingStateData `json:"data"`
+		Result *APIError         `json:"result"`
+	}
+)
+
+// FetchSendingStatesRequest and FetchSendingStatesResponse represents fetch sending states request and response
+type (
+	FetchSendingStatesRequest struct{}
+
+	FetchSendingStatesData struct {
+		States []SendingStateData `json:"states"`
+	}
+
+	FetchSendingStatesResponse struct {
+		Data   *FetchSendingStatesData `json:"data"`
+		Result *APIError               `json:"result"`
+	}
+)
+
+// FetchSchedulesRequest and FetchSchedulesResponse represents fetch schedules request and response
+type (
+	FetchSchedulesRequest struct{}
+
+	ScheduleData struct {
+		Name      string           `json:"name"`
+		Spec      string           `json:"spec"`
+		NextRunAt *time.Time       `json:"nextRunAt"`
+		Running   bool             `json:"running"`
+		LastRun   *ScheduleRunData `json:"lastRun"`
+		ChangedBy string           `json:"changedBy"`
+		ChangedAt *time.Time       `json:"changedAt"`
+	}
+
+	ScheduleRunData struct {
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+		Processed  int       `json:"processed"`
+		Error      string    `json:"error"`
+	}
+
+	FetchSchedulesData struct {
+		Schedules []ScheduleData `json:"schedules"`
+	}
+
+	FetchSchedulesResponse struct {
+		Data   *FetchSchedulesData `json:"data"`
+		Result *APIError           `json:"result"`
+	}
+)
+
+// UpdateScheduleRequest and UpdateScheduleResponse represents update schedule request and response
+type (
+	UpdateScheduleRequest struct {
+		Operator string `json:"-" header:"x-ins-operator"`
+		Name     string `json:"-" path:"name" validate:"required"`
+		Spec     string `json:"spec" validate:"required"`
+	}
+
+	UpdateScheduleResponse struct {
+		Data   *ScheduleData `json:"data"`
+		Result *APIError     `json:"result"`
+	}
+)
+
+// FetchJobRunsRequest and FetchJobRunsResponse represents fetch job runs request and response
+type (
+	FetchJobRunsRequest struct {
+		Name  string `json:"-" path:"name" validate:"required"`
+		Limit int    `json:"-" query:"limit" validate:"omitempty,min=1,max=1000"`
+	}
+
+	JobRunData struct {
+		ID         int64     `json:"id"`
+		Instance   string    `json:"instance"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+		DurationMs int64     `json:"durationMs"`
+		Processed  int       `json:"processed"`
+		Error      string    `json:"error"`
+	}
+
+	FetchJobRunsData struct {
+		Runs []JobRunData `json:"runs"`
+	}
+
+	FetchJobRunsResponse struct {
+		Data   *FetchJobRunsData `json:"data"`
+		Result *APIError         `json:"result"`
+	}
+)
+
+// FetchAutoSendHistoryRequest and FetchAutoSendHistoryResponse represents fetch auto send history request and response
+type (
+	FetchAutoSendHistoryRequest struct {
+		Scope string `json:"-" query:"scope"`
+		Limit int    `json:"-" query:"limit" validate:"omitempty,min=1,max=1000"`
+	}
+
+	FetchAutoSendHistoryData struct {
+		Changes []SendingStateData `json:"changes"`
+	}
+
+	FetchAutoSendHistoryResponse struct {
+		Data   *FetchAutoSendHistoryData `json:"data"`
+		Result *APIError                 `json:"result"`
+	}
+)
+
+// CreateMessageRequest and CreateMessageResponse represents create message request and response
+type (
+	CreateMessageRequest struct {
+		Recipient string `json:"recipient" validate:"required_without=GroupID,omitempty,phone"`
+		GroupID   int64  `json:"groupId" validate:"required_without=Recipient,excluded_with=Recipient"`
+		Content   string `json:"content" validate:"required"`
+		Template  bool   `json:"template"`
+	}
+
+	CreateMessageData struct {
+		Messages []CreatedMessage `json:"messages"`
+	}
+
+	CreatedMessage struct {
+		ID        int64  `json:"id"`
+		Recipient string `json:"recipient"`
+		Status    string `json:"status"`
+	}
+
+	CreateMessageResponse struct {
+		Data   *CreateMessageData `json:"data"`
+		Result *APIError          `json:"result"`
+	}
+)
+
 // FetchSentMessagesRequest and FetchSentMessagesResponse represents fetch sent messages request and response
 type (
-	FetchSentMessagesRequest struct{}
+	FetchSentMessagesRequest struct {
+		Cursor     string    `json:"-" query:"cursor"`
+		Limit      int       `json:"-" query:"limit" validate:"omitempty,min=1,max=1000"`
+		Recipient  string    `json:"-" query:"recipient" validate:"omitempty,phone"`
+		From       time.Time `json:"-" query:"from"`
+		To         time.Time `json:"-" query:"to"`
+		Status     []string  `json:"-" query:"status" validate:"omitempty,dive,oneof=queued sending sent delivered failed suppressed cancelled"`
+		CampaignID int64     `json:"-" query:"campaignId"`
+		Sort       string    `json:"-" query:"sort" validate:"omitempty,oneof=id -id sentAt -sentAt"`
+	}
 
 	FetchSentMessagesData struct {
 		SentMessages []FetchSentMessage `json:"sentMessages"`
+		NextCursor   string             `json:"nextCursor"`
 	}
 
 	FetchSentMessage struct {
-		Recipient string                    `json:"recipient"`
-		Contents  []FetchSentMessageContent `json:"contents"`
+		ID         int64                     `json:"id"`
+		Recipient  string                    `json:"recipient"`
+		Status     string                    `json:"status"`
+		CampaignID *int64                    `json:"campaignId"`
+		SentAt     *time.Time                `json:"sentAt"`
+		Contents   []FetchSentMessageContent `json:"contents"`
 	}
 
 	FetchSentMessageContent struct {
@@ -79,3 +458,494 @@ type (
 		Result *APIError              `json:"result"`
 	}
 )
+
+// FetchMessageRequest and FetchMessageResponse represents fetch message request and response
+type (
+	FetchMessageRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	FetchMessageData struct {
+		ID         int64                 `json:"id"`
+		Recipient  string                `json:"recipient"`
+		Content    string                `json:"content"`
+		Status     string                `json:"status"`
+		GroupID    *int64                `json:"groupId"`
+		CampaignID *int64                `json:"campaignId"`
+		SentAt     *time.Time            `json:"sentAt"`
+		Attempts   []MessageAttemptData  `json:"attempts"`
+		Chunks     []MessageChunkData    `json:"chunks"`
+		Receipts   []DeliveryReceiptData `json:"receipts"`
+	}
+
+	MessageAttemptData struct {
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+		ChunksSent int       `json:"chunksSent"`
+		Error      string    `json:"error"`
+	}
+
+	MessageChunkData struct {
+		Index       int       `json:"index"`
+		MessageId   string    `json:"messageId"`
+		SendingTime time.Time `json:"sendingTime"`
+		Content     string    `json:"content"`
+	}
+
+	DeliveryReceiptData struct {
+		MessageId  string    `json:"messageId"`
+		Status     string    `json:"status"`
+		Error      string    `json:"error"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+
+	FetchMessageResponse struct {
+		Data   *FetchMessageData `json:"data"`
+		Result *APIError         `json:"result"`
+	}
+)
+
+// ReceiveDeliveryReceiptRequest and ReceiveDeliveryReceiptResponse represents receive delivery receipt request and response
+type (
+	ReceiveDeliveryReceiptRequest struct {
+		AuthKey    string    `json:"-" header:"x-ins-auth-key"`
+		MessageID  string    `json:"messageId" validate:"required"`
+		Status     string    `json:"status" validate:"required"`
+		Error      string    `json:"error"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+
+	ReceiveDeliveryReceiptData struct {
+		ID     *int64 `json:"id"`
+		Status string `json:"status"`
+	}
+
+	ReceiveDeliveryReceiptResponse struct {
+		Data   *ReceiveDeliveryReceiptData `json:"data"`
+		Result *APIError                   `json:"result"`
+	}
+)
+
+// AddSuppressionRequest and AddSuppressionResponse represents add suppression request and response
+type (
+	AddSuppressionRequest struct {
+		Recipient string `json:"recipient" validate:"required,phone"`
+		Reason    string `json:"reason" validate:"required"`
+	}
+
+	SuppressionData struct {
+		Recipient string    `json:"recipient"`
+		Reason    string    `json:"reason"`
+		Source    string    `json:"source"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	AddSuppressionResponse struct {
+		Data   *SuppressionData `json:"data"`
+		Result *APIError        `json:"result"`
+	}
+)
+
+// RemoveSuppressionRequest and RemoveSuppressionResponse represents remove suppression request and response
+type (
+	RemoveSuppressionRequest struct {
+		Recipient string `json:"-" path:"recipient" validate:"required,phone"`
+	}
+
+	RemoveSuppressionData struct {
+		Recipient string `json:"recipient"`
+	}
+
+	RemoveSuppressionResponse struct {
+		Data   *RemoveSuppressionData `json:"data"`
+		Result *APIError              `json:"result"`
+	}
+)
+
+// FetchSuppressionsRequest and FetchSuppressionsResponse represents fetch suppressions request and response
+type (
+	FetchSuppressionsRequest struct{}
+
+	FetchSuppressionsData struct {
+		Suppressions []SuppressionData `json:"suppressions"`
+	}
+
+	FetchSuppressionsResponse struct {
+		Data   *FetchSuppressionsData `json:"data"`
+		Result *APIError              `json:"result"`
+	}
+)
+
+// ReceiveInboundMessageRequest and ReceiveInboundMessageResponse represents receive inbound message request and response
+type (
+	ReceiveInboundMessageRequest struct {
+		AuthKey    string    `json:"-" header:"x-ins-auth-key"`
+		From       string    `json:"from" validate:"required,phone"`
+		Content    string    `json:"content"`
+		MessageID  string    `json:"messageId"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+
+	ReceiveInboundMessageData struct {
+		Recipient string `json:"recipient"`
+		Keyword   string `json:"keyword"`
+		Action    string `json:"action"`
+		Replied   bool   `json:"replied"`
+	}
+
+	ReceiveInboundMessageResponse struct {
+		Data   *ReceiveInboundMessageData `json:"data"`
+		Result *APIError                  `json:"result"`
+	}
+)
+
+// FetchInboundMessagesRequest and FetchInboundMessagesResponse represents fetch inbound messages request and response
+type (
+	FetchInboundMessagesRequest struct {
+		Recipient string `json:"-" query:"recipient" validate:"required,phone"`
+		Limit     int    `json:"-" query:"limit" validate:"omitempty,min=1,max=1000"`
+	}
+
+	FetchInboundMessagesData struct {
+		InboundMessages []InboundMessage `json:"inboundMessages"`
+	}
+
+	InboundMessage struct {
+		Recipient  string    `json:"recipient"`
+		Content    string    `json:"content"`
+		Keyword    string    `json:"keyword"`
+		MessageID  string    `json:"messageId"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+
+	FetchInboundMessagesResponse struct {
+		Data   *FetchInboundMessagesData `json:"data"`
+		Result *APIError                 `json:"result"`
+	}
+)
+
+// ContactData represents contact of contact responses
+type ContactData struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	Phone      string            `json:"phone"`
+	Attributes map[string]string `json:"attributes"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+// CreateContactRequest and CreateContactResponse represents create contact request and response
+type (
+	CreateContactRequest struct {
+		Name       string            `json:"name" validate:"required"`
+		Phone      string            `json:"phone" validate:"required,phone"`
+		Attributes map[string]string `json:"attributes"`
+	}
+
+	CreateContactResponse struct {
+		Data   *ContactData `json:"data"`
+		Result *APIError    `json:"result"`
+	}
+)
+
+// UpdateContactRequest and UpdateContactResponse represents update contact request and response
+type (
+	UpdateContactRequest struct {
+		ID         int64             `json:"-" path:"id" validate:"required"`
+		Name       string            `json:"name" validate:"required"`
+		Phone      string            `json:"phone" validate:"required,phone"`
+		Attributes map[string]string `json:"attributes"`
+	}
+
+	UpdateContactResponse struct {
+		Data   *ContactData `json:"data"`
+		Result *APIError    `json:"result"`
+	}
+)
+
+// DeleteContactRequest and DeleteContactResponse represents delete contact request and response
+type (
+	DeleteContactRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	DeleteContactData struct {
+		ID int64 `json:"id"`
+	}
+
+	DeleteContactResponse struct {
+		Data   *DeleteContactData `json:"data"`
+		Result *APIError          `json:"result"`
+	}
+)
+
+// FetchContactRequest and FetchContactResponse represents fetch contact request and response
+type (
+	FetchContactRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	FetchContactResponse struct {
+		Data   *ContactData `json:"data"`
+		Result *APIError    `json:"result"`
+	}
+)
+
+// FetchContactsRequest and FetchContactsResponse represents fetch contacts request and response
+type (
+	FetchContactsRequest struct{}
+
+	FetchContactsData struct {
+		Contacts []ContactData `json:"contacts"`
+	}
+
+	FetchContactsResponse struct {
+		Data   *FetchContactsData `json:"data"`
+		Result *APIError          `json:"result"`
+	}
+)
+
+// GroupData represents group of group responses
+type GroupData struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Contacts    []ContactData `json:"contacts,omitempty"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// CreateGroupRequest and CreateGroupResponse represents create group request and response
+type (
+	CreateGroupRequest struct {
+		Name        string  `json:"name" validate:"required"`
+		Description string  `json:"description"`
+		ContactIDs  []int64 `json:"contactIds"`
+	}
+
+	CreateGroupResponse struct {
+		Data   *GroupData `json:"data"`
+		Result *APIError  `json:"result"`
+	}
+)
+
+// UpdateGroupRequest and UpdateGroupResponse represents update group request and response
+type (
+	UpdateGroupRequest struct {
+		ID          int64   `json:"-" path:"id" validate:"required"`
+		Name        string  `json:"name" validate:"required"`
+		Description string  `json:"description"`
+		ContactIDs  []int64 `json:"contactIds"`
+	}
+
+	UpdateGroupResponse struct {
+		Data   *GroupData `json:"data"`
+		Result *APIError  `json:"result"`
+	}
+)
+
+// DeleteGroupRequest and DeleteGroupResponse represents delete group request and response
+type (
+	DeleteGroupRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	DeleteGroupData struct {
+		ID int64 `json:"id"`
+	}
+
+	DeleteGroupResponse struct {
+		Data   *DeleteGroupData `json:"data"`
+		Result *APIError        `json:"result"`
+	}
+)
+
+// FetchGroupRequest and FetchGroupResponse represents fetch group request and response
+type (
+	FetchGroupRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	FetchGroupResponse struct {
+		Data   *GroupData `json:"data"`
+		Result *APIError  `json:"result"`
+	}
+)
+
+// FetchGroupsRequest and FetchGroupsResponse represents fetch groups request and response
+type (
+	FetchGroupsRequest struct{}
+
+	FetchGroupsData struct {
+		Groups []GroupData `json:"groups"`
+	}
+
+	FetchGroupsResponse struct {
+		Data   *FetchGroupsData `json:"data"`
+		Result *APIError        `json:"result"`
+	}
+)
+
+// CampaignData represents campaign of campaign responses
+type (
+	CampaignData struct {
+		ID                int64                `json:"id"`
+		Name              string               `json:"name"`
+		Content           string               `json:"content"`
+		Status            string               `json:"status"`
+		GroupID           *int64               `json:"groupId"`
+		AudienceSize      int                  `json:"audienceSize"`
+		StartAt           time.Time            `json:"startAt"`
+		ThrottlePerMinute int                  `json:"throttlePerMinute"`
+		Progress          CampaignProgressData `json:"progress"`
+		CreatedAt         time.Time            `json:"createdAt"`
+	}
+
+	CampaignProgressData struct {
+		Queued     int `json:"queued"`
+		Sent       int `json:"sent"`
+		Failed     int `json:"failed"`
+		Delivered  int `json:"delivered"`
+		Suppressed int `json:"suppressed"`
+		Cancelled  int `json:"cancelled"`
+		Total      int `json:"total"`
+	}
+)
+
+// CreateCampaignRequest and CreateCampaignResponse represents create campaign request and response
+type (
+	CreateCampaignRequest struct {
+		Name              string    `json:"name" validate:"required"`
+		Content           string    `json:"content" validate:"required"`
+		GroupID           int64     `json:"groupId" validate:"required_without=Recipients,excluded_with=Recipients"`
+		Recipients        []string  `json:"recipients" validate:"required_without=GroupID,omitempty,dive,phone"`
+		Template          bool      `json:"template"`
+		StartAt           time.Time `json:"startAt"`
+		ThrottlePerMinute int       `json:"throttlePerMinute" validate:"min=0"`
+	}
+
+	CreateCampaignResponse struct {
+		Data   *CampaignData `json:"data"`
+		Result *APIError     `json:"result"`
+	}
+)
+
+// FetchCampaignRequest and FetchCampaignResponse represents fetch campaign request and response
+type (
+	FetchCampaignRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	FetchCampaignResponse struct {
+		Data   *CampaignData `json:"data"`
+		Result *APIError     `json:"result"`
+	}
+)
+
+// PauseCampaignRequest and PauseCampaignResponse represents pause campaign request and response
+type (
+	PauseCampaignRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	PauseCampaignResponse struct {
+		Data   *CampaignData `json:"data"`
+		Result *APIError     `json:"result"`
+	}
+)
+
+// ResumeCampaignRequest and ResumeCampaignResponse represents resume campaign request and response
+type (
+	ResumeCampaignRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	ResumeCampaignResponse struct {
+		Data   *CampaignData `json:"data"`
+		Result *APIError     `json:"result"`
+	}
+)
+
+// AbortCampaignRequest and AbortCampaignResponse represents abort campaign request and response
+type (
+	AbortCampaignRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	AbortCampaignResponse struct {
+		Data   *AbortCampaignData `json:"data"`
+		Result *APIError          `json:"result"`
+	}
+
+	AbortCampaignData struct {
+		CampaignData
+		CancelledMessages int `json:"cancelledMessages"`
+	}
+)
+
+// APIKeyData represents api key of api key responses, Key is only returned when the key is created or rotated
+type APIKeyData struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Tenant     string     `json:"tenant"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RotatedAt  *time.Time `json:"rotatedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// CreateAPIKeyRequest and CreateAPIKeyResponse represents create api key request and response
+type (
+	CreateAPIKeyRequest struct {
+		Operator string   `json:"-" header:"x-ins-operator"`
+		Name     string   `json:"name" validate:"required"`
+		Scopes   []string `json:"scopes" validate:"required,min=1"`
+	}
+
+	CreateAPIKeyResponse struct {
+		Data   *APIKeyData `json:"data"`
+		Result *APIError   `json:"result"`
+	}
+)
+
+// FetchAPIKeysRequest and FetchAPIKeysResponse represents fetch api keys request and response
+type (
+	FetchAPIKeysRequest struct{}
+
+	FetchAPIKeysData struct {
+		Keys []APIKeyData `json:"keys"`
+	}
+
+	FetchAPIKeysResponse struct {
+		Data   *FetchAPIKeysData `json:"data"`
+		Result *APIError         `json:"result"`
+	}
+)
+
+// RotateAPIKeyRequest and RotateAPIKeyResponse represents rotate api key request and response
+type (
+	RotateAPIKeyRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	RotateAPIKeyResponse struct {
+		Data   *APIKeyData `json:"data"`
+		Result *APIError   `json:"result"`
+	}
+)
+
+// RevokeAPIKeyRequest and RevokeAPIKeyResponse represents revoke api key request and response
+type (
+	RevokeAPIKeyRequest struct {
+		ID int64 `json:"-" path:"id" validate:"required"`
+	}
+
+	RevokeAPIKeyResponse struct {
+		Data   *APIKeyData `json:"data"`
+		Result *APIError   `json:"result"`
+	}
+)